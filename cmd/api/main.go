@@ -2,15 +2,21 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // profile time zones must resolve on hosts without a zoneinfo database
 
 	"github.com/alexanderramin/kalistheniks/internal/config"
 	"github.com/alexanderramin/kalistheniks/internal/handlers"
+	"github.com/alexanderramin/kalistheniks/internal/jobs"
+	"github.com/alexanderramin/kalistheniks/internal/mailer"
+	"github.com/alexanderramin/kalistheniks/internal/oidc"
 	"github.com/alexanderramin/kalistheniks/internal/repositories"
 	"github.com/alexanderramin/kalistheniks/internal/services"
 	"github.com/alexanderramin/kalistheniks/internal/services/plan"
@@ -33,22 +39,95 @@ func main() {
 		logger.Fatalf("db ping failed: %v", err)
 	}
 
+	transport, closeMail, err := newMailer(cfg)
+	if err != nil {
+		logger.Fatalf("failed to configure mailer: %v", err)
+	}
+	defer closeMail()
+	// Mail goes out in the background so delivery never shapes a response
+	mail := mailer.NewAsyncMailer(transport, logger)
+	defer mail.Wait()
+
 	userRepo := repositories.NewUserRepository(database)
+	identityRepo := repositories.NewIdentityRepository(database)
 	sessionRepo := repositories.NewSessionRepository(database)
-	authService := services.NewAuthService(userRepo, cfg.JWTSecret)
-	sessionService := services.NewSessionService(sessionRepo)
-	planService := plan.NewPlanService(sessionRepo)
+	bodyweightRepo := repositories.NewBodyweightRepository(database)
+	exerciseRepo := repositories.NewExerciseRepository(database)
+	equipmentRepo := repositories.NewEquipmentRepository(database)
+	templateRepo := repositories.NewTemplateRepository(database)
+	readinessRepo := repositories.NewReadinessRepository(database)
+	authService := services.NewAuthService(userRepo, mail, cfg.JWTSecret, cfg.AppBaseURL)
+	socialLoginService := services.NewSocialLoginService(userRepo, identityRepo, newIdentityProviders(cfg), cfg.JWTSecret)
+	profileService := services.NewProfileService(userRepo)
+	exportService := services.NewExportService(userRepo, identityRepo, sessionRepo, bodyweightRepo, equipmentRepo, templateRepo, readinessRepo)
+	bodyweightService := services.NewBodyweightService(bodyweightRepo, sessionRepo, exerciseRepo, userRepo)
+	equipmentService := services.NewEquipmentService(equipmentRepo, userRepo)
+	sessionService := services.NewSessionService(sessionRepo, exerciseRepo)
+	workloadService := services.NewWorkloadService(sessionRepo, readinessRepo, userRepo)
+	planService := plan.NewPlanService(sessionRepo, userRepo, exerciseRepo, equipmentService, workloadService)
+	templateService := services.NewTemplateService(templateRepo, sessionRepo, planService)
+	adherenceService := services.NewAdherenceService(sessionRepo, userRepo)
+	calendarService := services.NewCalendarService(sessionRepo, userRepo, userRepo, exerciseRepo)
+	statsService := services.NewStatsService(sessionRepo, userRepo, exerciseRepo, bodyweightRepo)
+	historyService := services.NewHistoryService(sessionRepo, exerciseRepo, bodyweightRepo, userRepo)
+	dashboardService := services.NewDashboardService(sessionService, planService, statsService, exerciseRepo)
 
 	app := &handlers.App{
-		AuthService:    authService,
-		SessionService: sessionService,
-		PlanService:    planService,
-		Logger:         logger,
-		Config:         cfg,
+		AuthService:        authService,
+		SocialLoginService: socialLoginService,
+		ProfileService:     profileService,
+		ExportService:      exportService,
+		BodyweightService:  bodyweightService,
+		EquipmentService:   equipmentService,
+		SessionService:     sessionService,
+		TemplateService:    templateService,
+		AdherenceService:   adherenceService,
+		CalendarService:    calendarService,
+		StatsService:       statsService,
+		HistoryService:     historyService,
+		DashboardService:   dashboardService,
+		WorkloadService:    workloadService,
+		PlanService:        planService,
+		Logger:             logger,
+		Config:             cfg,
 	}
 
 	router := handlers.Router(app)
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	runner := jobs.NewRunner(logger, jobs.Job{
+		Name:     "purge-deleted-accounts",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			n, err := authService.PurgeDeletedAccounts(ctx)
+			if n > 0 {
+				logger.Printf("purged %d deleted accounts", n)
+			}
+			return err
+		},
+	}, jobs.Job{
+		Name:     "close-stale-sessions",
+		Interval: 15 * time.Minute,
+		Run: func(ctx context.Context) error {
+			n, err := sessionService.CloseStaleSessions(ctx)
+			if n > 0 {
+				logger.Printf("closed %d stale sessions", n)
+			}
+			return err
+		},
+	}, jobs.Job{
+		Name:     "reschedule-missed-sessions",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			moved, abandoned, err := calendarService.RescheduleMissed(ctx)
+			if moved > 0 || abandoned > 0 {
+				logger.Printf("rescheduled %d missed sessions, abandoned %d", moved, abandoned)
+			}
+			return err
+		},
+	})
+	runner.Start(jobCtx)
+
 	server := &http.Server{
 		Addr:              cfg.Addr,
 		Handler:           router,
@@ -83,6 +162,8 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Printf("server shutdown error: %v", err)
 	}
+	stopJobs()
+	runner.Wait()
 
 	// Close database connection
 	if err := database.Close(); err != nil {
@@ -91,3 +172,36 @@ func main() {
 
 	logger.Println("server stopped")
 }
+
+// newMailer selects the mail transport from config. The returned func releases any log file that was opened.
+func newMailer(cfg config.Config) (mailer.Mailer, func(), error) {
+	switch cfg.Mailer {
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), func() {}, nil
+	case "log", "":
+		if cfg.MailLogPath == "" {
+			return mailer.NewLogMailer(os.Stdout), func() {}, nil
+		}
+		f, err := os.OpenFile(cfg.MailLogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mailer.NewLogMailer(f), func() { _ = f.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown mailer %q", cfg.Mailer)
+	}
+}
+
+// newIdentityProviders builds an OIDC client per configured provider. Providers are contacted lazily on first use.
+func newIdentityProviders(cfg config.Config) map[string]services.IdentityProvider {
+	providers := make(map[string]services.IdentityProvider, len(cfg.OIDCProviders))
+	for _, p := range cfg.OIDCProviders {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  strings.TrimSuffix(cfg.APIBaseURL, "/") + "/auth/oidc/" + p.Name + "/callback",
+		}, nil)
+	}
+	return providers
+}
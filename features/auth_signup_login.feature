@@ -26,7 +26,7 @@ Feature: User signup and login to receive a JWT token
       """
       {"email":"user@example.com","password":"AnotherPass!2"}
       """
-    Then the response status should be 400
+    Then the response status should be 409
     And the response JSON field "error" should contain "email"
 
   Scenario: Login fails with wrong password
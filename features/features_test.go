@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/alexanderramin/kalistheniks/internal/config"
 	"github.com/alexanderramin/kalistheniks/internal/handlers"
+	"github.com/alexanderramin/kalistheniks/internal/mailer"
 	"github.com/alexanderramin/kalistheniks/internal/repositories"
 	"github.com/alexanderramin/kalistheniks/internal/services"
 	"github.com/alexanderramin/kalistheniks/internal/services/plan"
@@ -60,24 +62,57 @@ func InitializeTestSuite(ctx *godog.TestSuiteContext) {
 
 		// Create application dependencies
 		userRepo := repositories.NewUserRepository(testDB)
+		identityRepo := repositories.NewIdentityRepository(testDB)
 		sessionRepo := repositories.NewSessionRepository(testDB)
-		authService := services.NewAuthService(userRepo, jwtSecret)
-		sessionService := services.NewSessionService(sessionRepo)
-		planService := plan.NewPlanService(sessionRepo)
+		bodyweightRepo := repositories.NewBodyweightRepository(testDB)
+		exerciseRepo := repositories.NewExerciseRepository(testDB)
+		equipmentRepo := repositories.NewEquipmentRepository(testDB)
+		templateRepo := repositories.NewTemplateRepository(testDB)
+		readinessRepo := repositories.NewReadinessRepository(testDB)
+		authService := services.NewAuthService(userRepo, mailer.NewLogMailer(io.Discard), jwtSecret, "http://localhost:3000")
+		socialLoginService := services.NewSocialLoginService(userRepo, identityRepo, nil, jwtSecret)
+		profileService := services.NewProfileService(userRepo)
+		exportService := services.NewExportService(userRepo, identityRepo, sessionRepo, bodyweightRepo, equipmentRepo, templateRepo, readinessRepo)
+		bodyweightService := services.NewBodyweightService(bodyweightRepo, sessionRepo, exerciseRepo, userRepo)
+		equipmentService := services.NewEquipmentService(equipmentRepo, userRepo)
+		sessionService := services.NewSessionService(sessionRepo, exerciseRepo)
+		workloadService := services.NewWorkloadService(sessionRepo, readinessRepo, userRepo)
+		planService := plan.NewPlanService(sessionRepo, userRepo, exerciseRepo, equipmentService, workloadService)
+		templateService := services.NewTemplateService(templateRepo, sessionRepo, planService)
+		adherenceService := services.NewAdherenceService(sessionRepo, userRepo)
+		calendarService := services.NewCalendarService(sessionRepo, userRepo, userRepo, exerciseRepo)
+		statsService := services.NewStatsService(sessionRepo, userRepo, exerciseRepo, bodyweightRepo)
+		historyService := services.NewHistoryService(sessionRepo, exerciseRepo, bodyweightRepo, userRepo)
+		dashboardService := services.NewDashboardService(sessionService, planService, statsService, exerciseRepo)
 
 		cfg := config.Config{
 			Addr:      ":8080",
 			DBDSN:     "",
 			JWTSecret: jwtSecret,
 			Env:       "test",
+			// Scenarios log in far more often than a real client would
+			AuthRateLimit:         1000,
+			DuplicateSignupPolicy: config.SignupPolicyConflict,
 		}
 
 		app := &handlers.App{
-			AuthService:    authService,
-			SessionService: sessionService,
-			PlanService:    planService,
-			Logger:         log.New(os.Stdout, "test ", log.LstdFlags),
-			Config:         cfg,
+			AuthService:        authService,
+			SocialLoginService: socialLoginService,
+			ProfileService:     profileService,
+			ExportService:      exportService,
+			BodyweightService:  bodyweightService,
+			EquipmentService:   equipmentService,
+			SessionService:     sessionService,
+			TemplateService:    templateService,
+			AdherenceService:   adherenceService,
+			CalendarService:    calendarService,
+			StatsService:       statsService,
+			HistoryService:     historyService,
+			DashboardService:   dashboardService,
+			WorkloadService:    workloadService,
+			PlanService:        planService,
+			Logger:             log.New(os.Stdout, "test ", log.LstdFlags),
+			Config:             cfg,
 		}
 
 		// Create test HTTP server
@@ -1,6 +1,35 @@
 package config
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Email verification policies controlling what unverified accounts may do.
+const (
+	VerificationPolicyAllow    = "allow"     // unverified accounts have full access
+	VerificationPolicyReadOnly = "read_only" // unverified accounts may only read
+	VerificationPolicyBlock    = "block"     // unverified accounts are denied protected endpoints
+)
+
+// Duplicate signup policies controlling whether signup reveals that an email is registered.
+const (
+	SignupPolicyConceal  = "conceal"  // identical 202 response for new and existing emails; no token until login
+	SignupPolicyConflict = "conflict" // 201 with a token for new emails, 409 for existing ones
+)
+
+// OIDCProvider is an external OpenID Connect provider users may sign in with.
+type OIDCProvider struct {
+	Name         string // used in the /auth/oidc/{provider} routes
+	Issuer       string
+	ClientID     string
+	ClientSecret string // empty for public clients relying on PKCE alone
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
 
 // Config holds environment-driven configuration.
 type Config struct {
@@ -8,6 +37,32 @@ type Config struct {
 	JWTSecret string
 	Addr      string
 	Env       string // Environment: development, staging, production
+
+	// AppBaseURL is the public client URL used to build links in emails.
+	AppBaseURL string
+	// APIBaseURL is the public URL of this API, used to build OIDC redirect URLs.
+	APIBaseURL string
+
+	// Mail delivery: "log" writes messages to MailLogPath (or stdout), "smtp" sends through the relay below.
+	Mailer       string
+	MailFrom     string
+	MailLogPath  string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// EmailVerificationPolicy is one of the VerificationPolicy* constants.
+	EmailVerificationPolicy string
+
+	// DuplicateSignupPolicy is one of the SignupPolicy* constants.
+	DuplicateSignupPolicy string
+
+	// AuthRateLimit is the number of requests per minute per IP allowed on credential endpoints.
+	AuthRateLimit int
+
+	// OIDCProviders are read from OIDC_PROVIDERS (comma-separated names) and OIDC_<NAME>_* variables.
+	OIDCProviders []OIDCProvider
 }
 
 // Load reads configuration from environment variables.
@@ -18,12 +73,75 @@ func Load() (Config, error) {
 		JWTSecret: getenvOrDefault("JWT_SECRET", "replace-me"),
 		Addr:      getenvOrDefault("ADDR", ":8080"),
 		Env:       getenvOrDefault("ENV", "development"),
+
+		AppBaseURL: getenvOrDefault("APP_BASE_URL", "http://localhost:3000"),
+		APIBaseURL: getenvOrDefault("API_BASE_URL", "http://localhost:8080"),
+
+		Mailer:       getenvOrDefault("MAILER", "log"),
+		MailFrom:     getenvOrDefault("MAIL_FROM", "no-reply@kalistheniks.local"),
+		MailLogPath:  getenvOrDefault("MAIL_LOG_PATH", ""),
+		SMTPHost:     getenvOrDefault("SMTP_HOST", "localhost"),
+		SMTPPort:     getenvOrDefault("SMTP_PORT", "587"),
+		SMTPUsername: getenvOrDefault("SMTP_USERNAME", ""),
+		SMTPPassword: getenvOrDefault("SMTP_PASSWORD", ""),
+
+		EmailVerificationPolicy: getenvOrDefault("EMAIL_VERIFICATION_POLICY", VerificationPolicyAllow),
+		DuplicateSignupPolicy:   getenvOrDefault("DUPLICATE_SIGNUP_POLICY", SignupPolicyConceal),
+	}
+
+	authRateLimit, err := strconv.Atoi(getenvOrDefault("AUTH_RATE_LIMIT", "10"))
+	if err != nil || authRateLimit <= 0 {
+		return Config{}, fmt.Errorf("invalid AUTH_RATE_LIMIT %q", os.Getenv("AUTH_RATE_LIMIT"))
+	}
+	cfg.AuthRateLimit = authRateLimit
+
+	switch cfg.EmailVerificationPolicy {
+	case VerificationPolicyAllow, VerificationPolicyReadOnly, VerificationPolicyBlock:
+	default:
+		return Config{}, fmt.Errorf("invalid EMAIL_VERIFICATION_POLICY %q", cfg.EmailVerificationPolicy)
+	}
+
+	switch cfg.DuplicateSignupPolicy {
+	case SignupPolicyConceal, SignupPolicyConflict:
+	default:
+		return Config{}, fmt.Errorf("invalid DUPLICATE_SIGNUP_POLICY %q", cfg.DuplicateSignupPolicy)
+	}
+
+	providers, err := loadOIDCProviders(getenvOrDefault("OIDC_PROVIDERS", ""))
+	if err != nil {
+		return Config{}, err
 	}
+	cfg.OIDCProviders = providers
 
 	// TODO: add validation (e.g., ensure secrets and DSNs are set in production).
 	return cfg, nil
 }
 
+func loadOIDCProviders(names string) ([]OIDCProvider, error) {
+	var providers []OIDCProvider
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !providerNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid OIDC provider name %q", name)
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		p := OIDCProvider{
+			Name:         name,
+			Issuer:       getenvOrDefault(prefix+"ISSUER", ""),
+			ClientID:     getenvOrDefault(prefix+"CLIENT_ID", ""),
+			ClientSecret: getenvOrDefault(prefix+"CLIENT_SECRET", ""),
+		}
+		if p.Issuer == "" || p.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID are required", prefix, prefix)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
 func getenvOrDefault(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
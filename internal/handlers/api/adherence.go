@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/rules"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// defaultAdherenceWeeks is how many weeks GET /adherence/weekly covers without ?weeks.
+const defaultAdherenceWeeks = 8
+
+// CreatePlannedSet adds a set to the plan of a session that has not finished yet.
+func (h *Handler) CreatePlannedSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		ExerciseID      string   `json:"exercise_id"`
+		Kind            *string  `json:"kind"`
+		Reps            int      `json:"reps"`
+		Weight          *float64 `json:"weight"`
+		WeightKG        *float64 `json:"weight_kg"`
+		DurationSeconds *int     `json:"duration_seconds"`
+		DistanceM       *float64 `json:"distance_m"`
+		RestSeconds     *int     `json:"rest_seconds"`
+		GroupID         *string  `json:"group_id"`
+		Round           *int     `json:"round"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	exerciseID, err := uuid.Parse(payload.ExerciseID)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid exercise ID")
+		return
+	}
+	if err := validation.ValidateIntRange(payload.Reps, 0, 1000, "reps"); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.DurationSeconds != nil {
+		if err := validation.ValidateIntRange(*payload.DurationSeconds, 1, 86400, "duration_seconds"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.DistanceM != nil {
+		if err := validation.ValidateFloatRange(*payload.DistanceM, 0.1, 100000, "distance_m"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.RestSeconds != nil {
+		if err := validation.ValidateIntRange(*payload.RestSeconds, 0, 3600, "rest_seconds"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	var groupID *uuid.UUID
+	if payload.GroupID != nil {
+		id, err := uuid.Parse(*payload.GroupID)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid group ID")
+			return
+		}
+		groupID = &id
+	}
+	if payload.Round != nil {
+		if err := validation.ValidateIntRange(*payload.Round, 1, 1000, "round"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	kind := models.SetKindWorking
+	if payload.Kind != nil {
+		if err := validation.ValidateOneOf(*payload.Kind, setKinds, "kind"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		kind = *payload.Kind
+	}
+
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	weightKG, err := resolveWeight(payload.Weight, payload.WeightKG, system, 0, 1000)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	planned, err := h.Sessions.AddPlannedSet(r.Context(), userID, &models.PlannedSet{
+		SessionID:       sessionID,
+		ExerciseID:      exerciseID,
+		Kind:            kind,
+		Reps:            payload.Reps,
+		WeightKG:        weightKG,
+		DurationSeconds: payload.DurationSeconds,
+		DistanceM:       payload.DistanceM,
+		RestSeconds:     payload.RestSeconds,
+		GroupID:         groupID,
+		Round:           payload.Round,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSessionNotFound):
+			response.Error(w, http.StatusNotFound, "session not found")
+		case errors.Is(err, services.ErrSessionState):
+			response.Error(w, http.StatusConflict, "only planned or in-progress sessions can be planned")
+		case errors.Is(err, services.ErrUnknownExercise), errors.Is(err, services.ErrInvalidSet), errors.Is(err, services.ErrUnknownGroup):
+			response.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, "failed to add planned set")
+		}
+		return
+	}
+	response.JSON(w, http.StatusCreated, plannedSetResponse(*planned, system))
+}
+
+// SessionAdherence reports how much of a session's plan was done.
+func (h *Handler) SessionAdherence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	a, err := h.Adherence.SessionAdherence(r.Context(), userID, sessionID)
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		response.Error(w, http.StatusNotFound, "session not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to compute adherence")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	out := adherenceResponse(a.Adherence, system)
+	out["session_id"] = a.SessionID
+	out["performed_at"] = a.PerformedAt
+	out["status"] = a.Status
+	response.JSON(w, http.StatusOK, out)
+}
+
+// WeeklyAdherence reports plan adherence for each of the last weeks, e.g. ?weeks=12.
+func (h *Handler) WeeklyAdherence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	weeks := defaultAdherenceWeeks
+	if raw := r.URL.Query().Get("weeks"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "weeks must be a number")
+			return
+		}
+		if err := validation.ValidateIntRange(n, 1, 52, "weeks"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		weeks = n
+	}
+
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	weekly, err := h.Adherence.WeeklyAdherence(r.Context(), userID, weeks)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to compute adherence")
+		return
+	}
+	out := make([]map[string]any, 0, len(weekly))
+	for _, week := range weekly {
+		entry := adherenceResponse(week.Adherence, system)
+		entry["week_start"] = week.WeekStart.Format(time.DateOnly)
+		entry["sessions"] = week.Sessions
+		out = append(out, entry)
+	}
+	response.JSON(w, http.StatusOK, out)
+}
+
+// adherenceResponse reports planned and actual figures with their ratios; a ratio is null when nothing
+// of that kind was planned.
+func adherenceResponse(a models.Adherence, system string) map[string]any {
+	return map[string]any{
+		"planned_sets":    a.PlannedSets,
+		"completed_sets":  a.CompletedSets,
+		"planned_reps":    a.PlannedReps,
+		"actual_reps":     a.ActualReps,
+		"planned_load_kg": a.PlannedLoadKG,
+		"actual_load_kg":  a.ActualLoadKG,
+		"planned_load":    units.FromKG(a.PlannedLoadKG, system),
+		"actual_load":     units.FromKG(a.ActualLoadKG, system),
+		"unit":            units.Symbol(system),
+		"set_ratio":       rules.Ratio(float64(a.CompletedSets), float64(a.PlannedSets)),
+		"rep_ratio":       rules.Ratio(float64(a.ActualReps), float64(a.PlannedReps)),
+		"load_ratio":      rules.Ratio(a.ActualLoadKG, a.PlannedLoadKG),
+	}
+}
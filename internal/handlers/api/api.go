@@ -10,23 +10,57 @@ import (
 	"github.com/alexanderramin/kalistheniks/internal/handlers/contracts"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
 	"github.com/alexanderramin/kalistheniks/internal/validation"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type Handler struct {
-	Sessions contracts.SessionService
-	Plans    contracts.PlanService
+	Sessions   contracts.SessionService
+	Plans      contracts.PlanService
+	Profiles   contracts.ProfileService
+	Exports    contracts.ExportService
+	Bodyweight contracts.BodyweightService
+	Equipment  contracts.EquipmentService
+	Templates  contracts.TemplateService
+	Adherence  contracts.AdherenceService
+	Calendar   contracts.CalendarService
+	Stats      contracts.StatsService
+	History    contracts.HistoryService
+	Dashboard  contracts.DashboardService
+	Workload   contracts.WorkloadService
 }
 
-func New(sessions contracts.SessionService, plans contracts.PlanService) *Handler {
+func New(sessions contracts.SessionService, plans contracts.PlanService, profiles contracts.ProfileService, exports contracts.ExportService, bodyweight contracts.BodyweightService, equipment contracts.EquipmentService, templates contracts.TemplateService, adherence contracts.AdherenceService, calendar contracts.CalendarService, stats contracts.StatsService, history contracts.HistoryService, dashboard contracts.DashboardService, workload contracts.WorkloadService) *Handler {
 	return &Handler{
-		Sessions: sessions,
-		Plans:    plans,
+		Sessions:   sessions,
+		Plans:      plans,
+		Profiles:   profiles,
+		Exports:    exports,
+		Bodyweight: bodyweight,
+		Equipment:  equipment,
+		Templates:  templates,
+		Adherence:  adherence,
+		Calendar:   calendar,
+		Stats:      stats,
+		History:    history,
+		Dashboard:  dashboard,
+		Workload:   workload,
 	}
 }
 
+// setKinds lists every set kind a client may send.
+var setKinds = []string{
+	models.SetKindWarmup,
+	models.SetKindWorking,
+	models.SetKindDropset,
+	models.SetKindAMRAP,
+	models.SetKindBackoff,
+	models.SetKindFailure,
+}
+
 func (h *Handler) CreateSession(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.CurrentUserID(r)
 	if !ok {
@@ -41,6 +75,8 @@ func (h *Handler) CreateSession(w http.ResponseWriter, r *http.Request) {
 		PerformedAt *time.Time `json:"performed_at"`
 		SessionType *string    `json:"session_type"`
 		Notes       *string    `json:"notes"`
+		Status      *string    `json:"status"`
+		TemplateID  *string    `json:"template_id"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -64,12 +100,103 @@ func (h *Handler) CreateSession(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	session, err := h.Sessions.CreateSession(r.Context(), userID, payload.PerformedAt, payload.SessionType, payload.Notes)
+	var status string
+	if payload.Status != nil {
+		// Sessions end through the finish endpoint, so they cannot be created abandoned
+		allowed := []string{models.SessionPlanned, models.SessionInProgress, models.SessionCompleted}
+		if err := validation.ValidateOneOf(*payload.Status, allowed, "status"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		status = *payload.Status
+	}
+	if payload.TemplateID != nil {
+		h.createSessionFromTemplate(w, r, userID, *payload.TemplateID, payload.PerformedAt, payload.SessionType, payload.Notes, status)
+		return
+	}
+
+	session, err := h.Sessions.CreateSession(r.Context(), userID, payload.PerformedAt, payload.SessionType, payload.Notes, status)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to create session")
 		return
 	}
-	response.JSON(w, http.StatusCreated, session)
+	response.JSON(w, http.StatusCreated, sessionFields(session))
+}
+
+func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	session, err := h.Sessions.StartSession(r.Context(), userID, sessionID)
+	if err != nil {
+		sessionStateError(w, err, "failed to start session")
+		return
+	}
+	response.JSON(w, http.StatusOK, sessionFields(session))
+}
+
+// FinishSession completes a session in progress, or abandons it when the optional body says
+// {"status": "abandoned"}.
+func (h *Handler) FinishSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Status *string `json:"status"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		handleJSONError(w, err)
+		return
+	}
+	status := models.SessionCompleted
+	if payload.Status != nil {
+		allowed := []string{models.SessionCompleted, models.SessionAbandoned}
+		if err := validation.ValidateOneOf(*payload.Status, allowed, "status"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		status = *payload.Status
+	}
+
+	session, err := h.Sessions.FinishSession(r.Context(), userID, sessionID, status)
+	if err != nil {
+		sessionStateError(w, err, "failed to finish session")
+		return
+	}
+	response.JSON(w, http.StatusOK, sessionFields(session))
+}
+
+// sessionStateError writes the response for a failed session state change.
+func sessionStateError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		response.Error(w, http.StatusNotFound, "session not found")
+	case errors.Is(err, services.ErrSessionState):
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, message)
+	}
 }
 
 func (h *Handler) CreateSet(w http.ResponseWriter, r *http.Request) {
@@ -90,11 +217,19 @@ func (h *Handler) CreateSet(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
 
 	var payload struct {
-		ExerciseID string  `json:"exercise_id"`
-		SetIndex   int     `json:"set_index"`
-		Reps       int     `json:"reps"`
-		WeightKG   float64 `json:"weight_kg"`
-		RPE        *int    `json:"rpe"`
+		ExerciseID      string     `json:"exercise_id"`
+		SetIndex        int        `json:"set_index"`
+		Kind            *string    `json:"kind"`
+		Reps            int        `json:"reps"`
+		Weight          *float64   `json:"weight"`
+		WeightKG        *float64   `json:"weight_kg"`
+		RPE             *int       `json:"rpe"`
+		DurationSeconds *int       `json:"duration_seconds"`
+		DistanceM       *float64   `json:"distance_m"`
+		GroupID         *string    `json:"group_id"`
+		Round           *int       `json:"round"`
+		CompletedAt     *time.Time `json:"completed_at"`
+		RestSeconds     *int       `json:"rest_seconds"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -111,26 +246,54 @@ func (h *Handler) CreateSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate numeric fields
+	// Validate numeric fields; which of reps, duration and distance are required depends on the
+	// exercise and is checked by the service
 	if err := validation.ValidateNonNegativeInt(payload.SetIndex, "set_index"); err != nil {
 		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if err := validation.ValidatePositiveInt(payload.Reps, "reps"); err != nil {
+	if err := validation.ValidateNonNegativeInt(payload.Reps, "reps"); err != nil {
 		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if err := validation.ValidateIntRange(payload.Reps, 1, 1000, "reps"); err != nil {
+	if err := validation.ValidateIntRange(payload.Reps, 0, 1000, "reps"); err != nil {
 		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if err := validation.ValidatePositiveFloat(payload.WeightKG, "weight_kg"); err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error())
-		return
+	if payload.DurationSeconds != nil {
+		if err := validation.ValidateIntRange(*payload.DurationSeconds, 1, 86400, "duration_seconds"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
-	if err := validation.ValidateFloatRange(payload.WeightKG, 0.1, 1000, "weight_kg"); err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error())
-		return
+	if payload.DistanceM != nil {
+		if err := validation.ValidateFloatRange(*payload.DistanceM, 0.1, 100000, "distance_m"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	var groupID *uuid.UUID
+	if payload.GroupID != nil {
+		id, err := uuid.Parse(*payload.GroupID)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid group ID")
+			return
+		}
+		groupID = &id
+	}
+	if payload.Round != nil {
+		if err := validation.ValidateIntRange(*payload.Round, 1, 1000, "round"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	kind := models.SetKindWorking
+	if payload.Kind != nil {
+		if err := validation.ValidateOneOf(*payload.Kind, setKinds, "kind"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		kind = *payload.Kind
 	}
 	if payload.RPE != nil {
 		if err := validation.ValidateIntRange(*payload.RPE, 1, 10, "rpe"); err != nil {
@@ -138,13 +301,54 @@ func (h *Handler) CreateSet(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if payload.RestSeconds != nil {
+		if err := validation.ValidateIntRange(*payload.RestSeconds, 0, 3600, "rest_seconds"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	var completedAt time.Time
+	if payload.CompletedAt != nil {
+		completedAt = payload.CompletedAt.UTC()
+	}
 
-	set, err := h.Sessions.AddSet(r.Context(), userID, sessionUUID, exerciseUUID, payload.SetIndex, payload.Reps, payload.WeightKG, payload.RPE)
+	system, err := h.unitSystem(r.Context(), userID)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to add set")
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	// Zero is a plain bodyweight set; the exercise's bodyweight fraction supplies the load
+	weightKG, err := resolveWeight(payload.Weight, payload.WeightKG, system, 0, 1000)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	response.JSON(w, http.StatusCreated, set)
+
+	set, err := h.Sessions.AddSet(r.Context(), userID, &models.Set{
+		SessionID:       sessionUUID,
+		ExerciseID:      exerciseUUID,
+		SetIndex:        payload.SetIndex,
+		Kind:            kind,
+		Reps:            payload.Reps,
+		WeightKG:        weightKG,
+		RPE:             payload.RPE,
+		DurationSeconds: payload.DurationSeconds,
+		DistanceM:       payload.DistanceM,
+		GroupID:         groupID,
+		Round:           payload.Round,
+		CompletedAt:     completedAt,
+		RestSeconds:     payload.RestSeconds,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownExercise), errors.Is(err, services.ErrInvalidSet), errors.Is(err, services.ErrUnknownGroup):
+			response.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			sessionStateError(w, err, "failed to add set")
+		}
+		return
+	}
+	response.JSON(w, http.StatusCreated, setResponse(*set, system))
 }
 
 func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
@@ -158,7 +362,45 @@ func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusInternalServerError, "failed to list sessions")
 		return
 	}
-	response.JSON(w, http.StatusOK, sessions)
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	out := make([]map[string]any, 0, len(sessions))
+	for _, s := range sessions {
+		out = append(out, sessionResponse(s, system))
+	}
+	response.JSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) GetSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	session, err := h.Sessions.GetSession(r.Context(), userID, sessionID)
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		response.Error(w, http.StatusNotFound, "session not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to load session")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	response.JSON(w, http.StatusOK, sessionResponse(session, system))
 }
 
 func (h *Handler) NextPlan(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) LogBodyweight(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Weight     *float64   `json:"weight"`
+		WeightKG   *float64   `json:"weight_kg"`
+		MeasuredAt *time.Time `json:"measured_at"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	weightKG, err := resolveWeight(payload.Weight, payload.WeightKG, system, 20, 400)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Allow some clock skew between client and server
+	if payload.MeasuredAt != nil && payload.MeasuredAt.After(time.Now().Add(time.Hour)) {
+		response.Error(w, http.StatusBadRequest, "measured_at cannot be in the future")
+		return
+	}
+
+	entry, err := h.Bodyweight.LogBodyweight(r.Context(), userID, weightKG, payload.MeasuredAt)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to log bodyweight")
+		return
+	}
+	response.JSON(w, http.StatusCreated, bodyweightResponse(entry, system))
+}
+
+func (h *Handler) ListBodyweight(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	entries, err := h.Bodyweight.ListBodyweight(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list bodyweight")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	out := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, bodyweightResponse(e, system))
+	}
+	response.JSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) SessionMetrics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	// Tonnage counts working sets unless other kinds are asked for, e.g. ?kinds=working,amrap or ?kinds=all
+	kinds, err := kindsParam(r.URL.Query().Get("kinds"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m, err := h.Bodyweight.SessionMetrics(r.Context(), userID, sessionID, kinds)
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		response.Error(w, http.StatusNotFound, "session not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to compute metrics")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+
+	sets := make([]map[string]any, 0, len(m.Sets))
+	for _, s := range m.Sets {
+		sets = append(sets, map[string]any{
+			"set_id":                 s.SetID,
+			"exercise_id":            s.ExerciseID,
+			"kind":                   s.Kind,
+			"reps":                   s.Reps,
+			"added_weight_kg":        s.AddedKG,
+			"added_weight":           units.FromKG(s.AddedKG, system),
+			"effective_load_kg":      s.EffectiveKG,
+			"effective_load":         units.FromKG(s.EffectiveKG, system),
+			"relative_to_bodyweight": s.Relative,
+		})
+	}
+	var bodyweight *float64
+	if m.BodyweightKG != nil {
+		converted := units.FromKG(*m.BodyweightKG, system)
+		bodyweight = &converted
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"session_id":    m.SessionID,
+		"performed_at":  m.PerformedAt,
+		"unit":          units.Symbol(system),
+		"bodyweight_kg": m.BodyweightKG,
+		"bodyweight":    bodyweight,
+		"tonnage_kg":    m.TonnageKG,
+		"tonnage":       units.FromKG(m.TonnageKG, system),
+		"sets":          sets,
+	})
+}
+
+func bodyweightResponse(e *models.BodyweightEntry, system string) map[string]any {
+	return map[string]any{
+		"id":          e.ID,
+		"weight_kg":   e.WeightKG,
+		"weight":      units.FromKG(e.WeightKG, system),
+		"unit":        units.Symbol(system),
+		"measured_at": e.MeasuredAt,
+	}
+}
+
+// kindsParam reads a comma-separated list of set kinds, or "all". An empty list leaves the choice to the
+// service.
+func kindsParam(raw string) ([]string, error) {
+	switch raw {
+	case "":
+		return nil, nil
+	case "all":
+		return setKinds, nil
+	}
+	kinds := strings.Split(raw, ",")
+	for _, kind := range kinds {
+		if err := validation.ValidateOneOf(kind, setKinds, "kinds"); err != nil {
+			return nil, err
+		}
+	}
+	return kinds, nil
+}
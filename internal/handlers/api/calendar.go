@@ -0,0 +1,244 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/ical"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// feedEventDuration is how long a planned session blocks in a subscribed calendar; sessions have no
+// planned length.
+const feedEventDuration = time.Hour
+
+// GetCalendar lays out the user's sessions day by day between two dates, e.g.
+// ?from=2025-03-01&to=2025-03-31. Both dates are included and read in the user's time zone.
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	from, err := time.Parse(time.DateOnly, r.URL.Query().Get("from"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "from must be a date such as 2025-03-01")
+		return
+	}
+	to, err := time.Parse(time.DateOnly, r.URL.Query().Get("to"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "to must be a date such as 2025-03-31")
+		return
+	}
+
+	days, err := h.Calendar.Calendar(r.Context(), userID, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCalendarRange):
+			response.Error(w, http.StatusBadRequest, "to must be on or after from, at most 92 days later")
+		default:
+			response.Error(w, http.StatusInternalServerError, "failed to load calendar")
+		}
+		return
+	}
+	out := make([]map[string]any, 0, len(days))
+	for _, day := range days {
+		sessions := make([]map[string]any, 0, len(day.Sessions))
+		for _, s := range day.Sessions {
+			sessions = append(sessions, sessionFields(s))
+		}
+		out = append(out, map[string]any{
+			"date":         day.Date.Format(time.DateOnly),
+			"training_day": day.TrainingDay,
+			"missed":       day.Missed,
+			"sessions":     sessions,
+		})
+	}
+	response.JSON(w, http.StatusOK, out)
+}
+
+// RescheduleSession moves a planned session to the time in {"performed_at": "..."}.
+func (h *Handler) RescheduleSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		PerformedAt *time.Time `json:"performed_at"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+	if payload.PerformedAt == nil {
+		response.Error(w, http.StatusBadRequest, "performed_at is required")
+		return
+	}
+
+	session, err := h.Calendar.Reschedule(r.Context(), userID, sessionID, *payload.PerformedAt)
+	if err != nil {
+		sessionStateError(w, err, "failed to reschedule session")
+		return
+	}
+	response.JSON(w, http.StatusOK, sessionFields(session))
+}
+
+// IssueCalendarFeed creates the secret token for the user's calendar feed, replacing any earlier one. The
+// token is only shown in this response.
+func (h *Handler) IssueCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	feedToken, err := h.Calendar.IssueFeedToken(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to create calendar feed")
+		return
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{
+		"token": feedToken,
+		"path":  "/calendar.ics?token=" + url.QueryEscape(feedToken),
+	})
+}
+
+// RevokeCalendarFeed turns the user's calendar feed off.
+func (h *Handler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	err := h.Calendar.RevokeFeedToken(r.Context(), userID)
+	switch {
+	case errors.Is(err, services.ErrCalendarFeedNotFound):
+		response.Error(w, http.StatusNotFound, "calendar feed not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to revoke calendar feed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CalendarFeed serves a user's upcoming planned sessions as iCalendar for calendar apps to subscribe to.
+// Calendar apps cannot log in, so the secret token in ?token= stands in for authentication.
+func (h *Handler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.Calendar.Feed(r.Context(), r.URL.Query().Get("token"))
+	switch {
+	case errors.Is(err, services.ErrCalendarFeedNotFound):
+		response.Error(w, http.StatusNotFound, "calendar feed not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to load calendar feed")
+		return
+	}
+
+	cal := ical.Calendar{ProdID: "-//Kalistheniks//Training Calendar//EN", Name: "Kalistheniks training"}
+	for _, s := range feed.Sessions {
+		cal.Events = append(cal.Events, feedEvent(s, feed.Exercises, feed.Profile.Units))
+	}
+	var body bytes.Buffer
+	if err := ical.Encode(&body, cal, time.Now()); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load calendar feed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="kalistheniks.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body.Bytes())
+}
+
+// feedEvent turns a planned session into a calendar event. The session ID makes the UID, so a moved
+// session replaces its old event instead of adding another.
+func feedEvent(s *models.Session, exercises map[uuid.UUID]models.Exercise, system string) ical.Event {
+	summary := "Workout"
+	if s.SessionType != nil {
+		summary += ": " + *s.SessionType
+	}
+	lines := plannedSetLines(s.PlannedSets, exercises, system)
+	if s.Notes != nil && *s.Notes != "" {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, *s.Notes)
+	}
+	return ical.Event{
+		UID:         s.ID.String() + "@kalistheniks",
+		Start:       s.PerformedAt,
+		End:         s.PerformedAt.Add(feedEventDuration),
+		Summary:     summary,
+		Description: strings.Join(lines, "\n"),
+	}
+}
+
+// plannedSetLines describes a plan with one line per run of identical targets, e.g. "Squat: 3 × 5 @ 100 kg".
+func plannedSetLines(planned []models.PlannedSet, exercises map[uuid.UUID]models.Exercise, system string) []string {
+	type run struct {
+		name, target string
+		sets         int
+	}
+	var runs []run
+	for _, p := range planned {
+		name := "Unknown exercise"
+		if e, ok := exercises[p.ExerciseID]; ok {
+			name = e.Name
+		}
+		target := plannedTarget(p, system)
+		if n := len(runs); n > 0 && runs[n-1].name == name && runs[n-1].target == target {
+			runs[n-1].sets++
+			continue
+		}
+		runs = append(runs, run{name: name, target: target, sets: 1})
+	}
+	lines := make([]string, 0, len(runs))
+	for _, r := range runs {
+		lines = append(lines, fmt.Sprintf("%s: %d × %s", r.name, r.sets, r.target))
+	}
+	return lines
+}
+
+// plannedTarget describes what one planned set asks for: a time, a distance, or reps with any load.
+func plannedTarget(p models.PlannedSet, system string) string {
+	var target string
+	switch {
+	case p.DurationSeconds != nil:
+		target = strconv.Itoa(*p.DurationSeconds) + " s"
+	case p.DistanceM != nil:
+		target = strconv.FormatFloat(*p.DistanceM, 'f', -1, 64) + " m"
+	default:
+		target = strconv.Itoa(p.Reps)
+		if p.WeightKG > 0 {
+			target += " @ " + strconv.FormatFloat(units.FromKG(p.WeightKG, system), 'f', -1, 64) + " " + units.Symbol(system)
+		}
+	}
+	if p.Kind != "" && p.Kind != models.SetKindWorking {
+		target += " (" + p.Kind + ")"
+	}
+	return target
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+)
+
+// GetDashboard returns everything the home screen shows in one response: the last session, the next
+// planned one and suggested workout, this week's volume against last week's, recent personal records
+// and the training streak.
+func (h *Handler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	dashboard, err := h.Dashboard.Dashboard(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load dashboard")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+
+	var last, next map[string]any
+	if dashboard.LastSession != nil {
+		last = sessionResponse(dashboard.LastSession, system)
+	}
+	if dashboard.NextSession != nil {
+		next = sessionResponse(dashboard.NextSession, system)
+	}
+	records := make([]map[string]any, 0, len(dashboard.RecentRecords))
+	for _, pr := range dashboard.RecentRecords {
+		records = append(records, map[string]any{
+			"exercise_id":   pr.ExerciseID,
+			"exercise_name": dashboard.Exercises[pr.ExerciseID].Name,
+			"weight_kg":     pr.WeightKG,
+			"weight":        units.FromKG(pr.WeightKG, system),
+			"reps":          pr.Reps,
+			"session_id":    pr.SessionID,
+			"performed_at":  pr.PerformedAt,
+		})
+	}
+	exercises := make([]map[string]any, 0, len(dashboard.Exercises))
+	for _, ex := range dashboard.Exercises {
+		exercises = append(exercises, map[string]any{
+			"id":          ex.ID,
+			"name":        ex.Name,
+			"measurement": ex.Measurement,
+		})
+	}
+	sort.Slice(exercises, func(i, j int) bool {
+		return exercises[i]["name"].(string) < exercises[j]["name"].(string)
+	})
+
+	// The change is left out while last week has no volume to compare with.
+	var change *float64
+	if dashboard.LastWeek.VolumeKG > 0 {
+		pct := math.Round((dashboard.ThisWeek.VolumeKG/dashboard.LastWeek.VolumeKG-1)*1000) / 10
+		change = &pct
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"unit":                  units.Symbol(system),
+		"last_session":          last,
+		"next_session":          next,
+		"next_workout":          dashboard.Suggestion,
+		"this_week":             weeklyVolumeResponse(dashboard.ThisWeek, system),
+		"last_week":             weeklyVolumeResponse(dashboard.LastWeek, system),
+		"volume_change_percent": change,
+		"recent_records":        records,
+		"current_streak_weeks":  dashboard.CurrentStreakWeeks,
+		"longest_streak_weeks":  dashboard.LongestStreakWeeks,
+		"exercises":             exercises,
+	})
+}
+
+func weeklyVolumeResponse(v models.WeeklyVolume, system string) map[string]any {
+	return map[string]any{
+		"week_start": v.WeekStart.Format(time.DateOnly),
+		"sessions":   v.Sessions,
+		"sets":       v.Sets,
+		"reps":       v.Reps,
+		"volume_kg":  v.VolumeKG,
+		"volume":     units.FromKG(v.VolumeKG, system),
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+)
+
+func (h *Handler) GetEquipment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	e, err := h.Equipment.GetEquipment(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load equipment")
+		return
+	}
+	response.JSON(w, http.StatusOK, equipmentResponse(e, system))
+}
+
+// UpdateEquipment replaces the whole equipment profile; weights are in the user's unit.
+func (h *Handler) UpdateEquipment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		BarWeight      *float64  `json:"bar_weight"`
+		Plates         []float64 `json:"plates"`
+		Dumbbells      []float64 `json:"dumbbells"`
+		HasRings       bool      `json:"has_rings"`
+		HasPullUpBar   bool      `json:"has_pullup_bar"`
+		HasParallettes bool      `json:"has_parallettes"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+
+	if payload.BarWeight == nil {
+		response.Error(w, http.StatusBadRequest, "bar_weight is required")
+		return
+	}
+	barKG, err := toKGInRange(*payload.BarWeight, system, 0, 50, "bar_weight")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	platesKG, err := toKGList(payload.Plates, system, 0.25, 50, 20, "plates")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	dumbbellsKG, err := toKGList(payload.Dumbbells, system, 0.5, 100, 100, "dumbbells")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e, err := h.Equipment.UpdateEquipment(r.Context(), &models.Equipment{
+		UserID:         userID,
+		BarKG:          barKG,
+		PlatesKG:       platesKG,
+		DumbbellsKG:    dumbbellsKG,
+		HasRings:       payload.HasRings,
+		HasPullUpBar:   payload.HasPullUpBar,
+		HasParallettes: payload.HasParallettes,
+	})
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to save equipment")
+		return
+	}
+	response.JSON(w, http.StatusOK, equipmentResponse(e, system))
+}
+
+// PlateLoading answers GET /tools/plates?weight= with the plates to put on each side of the bar.
+func (h *Handler) PlateLoading(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	weight, err := strconv.ParseFloat(r.URL.Query().Get("weight"), 64)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "weight must be a number")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	targetKG, err := toKGInRange(weight, system, 0, 1000, "weight")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	loading, err := h.Equipment.PlateLoading(r.Context(), userID, targetKG)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to calculate plates")
+		return
+	}
+	perSide := make([]float64, len(loading.PerSideKG))
+	for i, p := range loading.PerSideKG {
+		perSide[i] = units.FromKG(p, system)
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"unit":        units.Symbol(system),
+		"target":      weight,
+		"weight":      units.FromKG(loading.AchievedKG, system),
+		"weight_kg":   loading.AchievedKG,
+		"exact":       loading.AchievedKG == loading.TargetKG,
+		"bar":         units.FromKG(loading.BarKG, system),
+		"per_side":    perSide,
+		"per_side_kg": loading.PerSideKG,
+	})
+}
+
+// toKGInRange checks a weight in the user's unit against a kilogram range and converts it.
+func toKGInRange(value float64, system string, minKG, maxKG float64, field string) (float64, error) {
+	min, max := units.FromKG(minKG, system), units.FromKG(maxKG, system)
+	if err := validation.ValidateFloatRange(value, min, max, field); err != nil {
+		return 0, err
+	}
+	return units.ToKG(value, system), nil
+}
+
+func toKGList(values []float64, system string, minKG, maxKG float64, maxLen int, field string) ([]float64, error) {
+	if len(values) > maxLen {
+		return nil, fmt.Errorf("%w: %s can list at most %d weights", validation.ErrInvalidValue, field, maxLen)
+	}
+	out := make([]float64, len(values))
+	for i, v := range values {
+		kg, err := toKGInRange(v, system, minKG, maxKG, field)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = kg
+	}
+	return out, nil
+}
+
+func equipmentResponse(e *models.Equipment, system string) map[string]any {
+	plates := make([]float64, len(e.PlatesKG))
+	for i, p := range e.PlatesKG {
+		plates[i] = units.FromKG(p, system)
+	}
+	dumbbells := make([]float64, len(e.DumbbellsKG))
+	for i, d := range e.DumbbellsKG {
+		dumbbells[i] = units.FromKG(d, system)
+	}
+	return map[string]any{
+		"unit":            units.Symbol(system),
+		"bar_weight":      units.FromKG(e.BarKG, system),
+		"bar_weight_kg":   e.BarKG,
+		"plates":          plates,
+		"plates_kg":       e.PlatesKG,
+		"dumbbells":       dumbbells,
+		"dumbbells_kg":    e.DumbbellsKG,
+		"has_rings":       e.HasRings,
+		"has_pullup_bar":  e.HasPullUpBar,
+		"has_parallettes": e.HasParallettes,
+		"updated_at":      e.UpdatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+)
+
+func (h *Handler) ExportMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	export, err := h.Exports.Export(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to export data")
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="kalistheniks-export.json"`)
+	response.JSON(w, http.StatusOK, exportResponse(export))
+}
+
+// exportResponse lists every stored field except secrets: password and TOTP hashes never leave the server.
+func exportResponse(e *models.DataExport) map[string]any {
+	account := meResponse(e.User, e.Profile)
+	account["updated_at"] = e.User.UpdatedAt
+	account["deletion_scheduled_at"] = e.User.DeletionScheduledAt
+
+	identities := make([]map[string]any, 0, len(e.Identities))
+	for _, i := range e.Identities {
+		identities = append(identities, map[string]any{
+			"provider":   i.Provider,
+			"subject":    i.Subject,
+			"email":      i.Email,
+			"created_at": i.CreatedAt,
+		})
+	}
+
+	bodyweight := make([]map[string]any, 0, len(e.Bodyweight))
+	for _, b := range e.Bodyweight {
+		bodyweight = append(bodyweight, bodyweightResponse(b, e.Profile.Units))
+	}
+
+	var equipment map[string]any
+	if e.Equipment != nil {
+		equipment = equipmentResponse(e.Equipment, e.Profile.Units)
+	}
+
+	sessions := make([]map[string]any, 0, len(e.Sessions))
+	for _, session := range e.Sessions {
+		sessions = append(sessions, sessionResponse(session, e.Profile.Units))
+	}
+
+	templates := make([]map[string]any, 0, len(e.Templates))
+	for _, t := range e.Templates {
+		templates = append(templates, templateResponse(t, e.Profile.Units))
+	}
+
+	readiness := make([]map[string]any, 0, len(e.Readiness))
+	for _, c := range e.Readiness {
+		readiness = append(readiness, checkInResponse(c))
+	}
+
+	exercises := make([]map[string]any, 0, len(e.Exercises))
+	for _, ex := range e.Exercises {
+		exercises = append(exercises, map[string]any{
+			"id":                  ex.ID,
+			"name":                ex.Name,
+			"body_part":           ex.BodyPart,
+			"primary_muscle":      ex.PrimaryMuscle,
+			"secondary_muscle":    ex.SecondaryMuscle,
+			"bodyweight_fraction": ex.BodyweightFraction,
+			"measurement":         ex.Measurement,
+		})
+	}
+
+	records := make([]map[string]any, 0, len(e.Records))
+	for _, pr := range e.Records {
+		records = append(records, map[string]any{
+			"exercise_id":  pr.ExerciseID,
+			"weight_kg":    pr.WeightKG,
+			"reps":         pr.Reps,
+			"session_id":   pr.SessionID,
+			"performed_at": pr.PerformedAt,
+		})
+	}
+
+	return map[string]any{
+		"generated_at": e.GeneratedAt,
+		"account":      account,
+		"identities":   identities,
+		"bodyweight":   bodyweight,
+		"equipment":    equipment,
+		"sessions":     sessions,
+		"templates":    templates,
+		"readiness":    readiness,
+		"exercises":    exercises,
+		"records":      records,
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// groupKinds lists every set group kind a client may send.
+var groupKinds = []string{
+	models.GroupSuperset,
+	models.GroupGiantSet,
+	models.GroupCircuit,
+	models.GroupEMOM,
+	models.GroupAMRAP,
+}
+
+func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Kind        string `json:"kind"`
+		Rounds      *int   `json:"rounds"`
+		RestSeconds *int   `json:"rest_seconds"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if err := validation.ValidateOneOf(payload.Kind, groupKinds, "kind"); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.Rounds != nil {
+		if err := validation.ValidateIntRange(*payload.Rounds, 1, 1000, "rounds"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.RestSeconds != nil {
+		if err := validation.ValidateIntRange(*payload.RestSeconds, 0, 3600, "rest_seconds"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	group, err := h.Sessions.CreateGroup(r.Context(), userID, sessionID, payload.Kind, payload.Rounds, payload.RestSeconds)
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		response.Error(w, http.StatusNotFound, "session not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to create group")
+		return
+	}
+	response.JSON(w, http.StatusCreated, groupResponse(*group))
+}
+
+func groupResponse(g models.SetGroup) map[string]any {
+	return map[string]any{
+		"id":           g.ID,
+		"session_id":   g.SessionID,
+		"group_index":  g.GroupIndex,
+		"kind":         g.Kind,
+		"rounds":       g.Rounds,
+		"rest_seconds": g.RestSeconds,
+	}
+}
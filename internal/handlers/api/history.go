@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// historyWindows lists the aggregation windows of GET /exercises/{id}/history.
+var historyWindows = []string{models.HistoryWindowSession, models.HistoryWindowWeek, models.HistoryWindowMonth}
+
+// ExerciseHistory returns the user's progress on an exercise as a time series, one point per session
+// unless ?window=week or ?window=month asks for more. Working sets count unless ?kinds= says otherwise.
+func (h *Handler) ExerciseHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	exerciseID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid exercise ID")
+		return
+	}
+	window := models.HistoryWindowSession
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		if err := validation.ValidateOneOf(raw, historyWindows, "window"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		window = raw
+	}
+	kinds, err := kindsParam(r.URL.Query().Get("kinds"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	history, err := h.History.ExerciseHistory(r.Context(), userID, exerciseID, window, kinds)
+	switch {
+	case errors.Is(err, services.ErrUnknownExercise):
+		response.Error(w, http.StatusNotFound, "exercise not found")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to load exercise history")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+
+	points := make([]map[string]any, 0, len(history.Points))
+	for _, p := range history.Points {
+		var e1rm *float64
+		if p.TopE1RMKG != nil {
+			converted := units.FromKG(*p.TopE1RMKG, system)
+			e1rm = &converted
+		}
+		points = append(points, map[string]any{
+			"start":       p.Start,
+			"session_id":  p.SessionID,
+			"sessions":    p.Sessions,
+			"sets":        p.Sets,
+			"best_set":    bestSetResponse(p.BestSet, p.BestSetLoadKG, system),
+			"total_reps":  p.TotalReps,
+			"volume_kg":   p.VolumeKG,
+			"volume":      units.FromKG(p.VolumeKG, system),
+			"top_e1rm_kg": p.TopE1RMKG,
+			"top_e1rm":    e1rm,
+			"average_rpe": p.AverageRPE,
+		})
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"exercise_id": history.ExerciseID,
+		"measurement": history.Measurement,
+		"window":      history.Window,
+		"unit":        units.Symbol(system),
+		"points":      points,
+	})
+}
+
+// bestSetResponse shows the best set of a history point with both the logged weight and the load it
+// amounted to.
+func bestSetResponse(s models.Set, loadKG float64, system string) map[string]any {
+	return map[string]any{
+		"set_id":           s.ID,
+		"session_id":       s.SessionID,
+		"kind":             s.Kind,
+		"reps":             s.Reps,
+		"weight_kg":        s.WeightKG,
+		"weight":           units.FromKG(s.WeightKG, system),
+		"load_kg":          loadKG,
+		"load":             units.FromKG(loadKG, system),
+		"duration_seconds": s.DurationSeconds,
+		"distance_m":       s.DistanceM,
+		"rpe":              s.RPE,
+	}
+}
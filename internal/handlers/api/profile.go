@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+)
+
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	user, profile, err := h.Profiles.GetMe(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	response.JSON(w, http.StatusOK, meResponse(user, profile))
+}
+
+func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	// bodyweight, height and birth year can be cleared by sending null
+	var payload struct {
+		Bodyweight   nullable[float64] `json:"bodyweight"`
+		BodyweightKG nullable[float64] `json:"bodyweight_kg"`
+		HeightCM     nullable[float64] `json:"height_cm"`
+		BirthYear    nullable[int]     `json:"birth_year"`
+		Experience   *string           `json:"experience"`
+		Units        *string           `json:"units"`
+		TimeZone     *string           `json:"time_zone"`
+		TrainingDays *[]string         `json:"training_days"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if payload.Bodyweight.Set && payload.BodyweightKG.Set {
+		response.Error(w, http.StatusBadRequest, "invalid value: provide either bodyweight or bodyweight_kg, not both")
+		return
+	}
+	if payload.BodyweightKG.Value != nil {
+		if err := validation.ValidateFloatRange(*payload.BodyweightKG.Value, 20, 400, "bodyweight_kg"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.HeightCM.Value != nil {
+		if err := validation.ValidateFloatRange(*payload.HeightCM.Value, 100, 250, "height_cm"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.BirthYear.Value != nil {
+		if err := validation.ValidateIntRange(*payload.BirthYear.Value, 1900, time.Now().Year()-10, "birth_year"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.Experience != nil {
+		levels := []string{models.ExperienceBeginner, models.ExperienceIntermediate, models.ExperienceAdvanced}
+		if err := validation.ValidateOneOf(*payload.Experience, levels, "experience"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.Units != nil {
+		if err := validation.ValidateOneOf(*payload.Units, []string{models.UnitsMetric, models.UnitsImperial}, "units"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if payload.TimeZone != nil {
+		if err := validation.ValidateTimeZone(*payload.TimeZone, "time_zone"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	var trainingDays *[]string
+	if payload.TrainingDays != nil {
+		days := []string{}
+		seen := make(map[string]bool)
+		for _, day := range *payload.TrainingDays {
+			if err := validation.ValidateOneOf(day, weekdays, "training_days"); err != nil {
+				response.Error(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+		trainingDays = &days
+	}
+
+	// bodyweight is read in the unit system the profile will have after this update
+	bodyweightKG := payload.BodyweightKG.Value
+	if payload.Bodyweight.Value != nil {
+		var system string
+		if payload.Units != nil {
+			system = *payload.Units
+		} else {
+			current, err := h.unitSystem(r.Context(), userID)
+			if err != nil {
+				response.Error(w, http.StatusInternalServerError, "failed to load profile")
+				return
+			}
+			system = current
+		}
+		min, max := units.FromKG(20, system), units.FromKG(400, system)
+		if err := validation.ValidateFloatRange(*payload.Bodyweight.Value, min, max, "bodyweight"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		kg := units.ToKG(*payload.Bodyweight.Value, system)
+		bodyweightKG = &kg
+	}
+
+	user, profile, err := h.Profiles.UpdateProfile(r.Context(), userID, models.ProfileUpdate{
+		BodyweightKG:    bodyweightKG,
+		HeightCM:        payload.HeightCM.Value,
+		BirthYear:       payload.BirthYear.Value,
+		Experience:      payload.Experience,
+		Units:           payload.Units,
+		TimeZone:        payload.TimeZone,
+		TrainingDays:    trainingDays,
+		ClearBodyweight: bodyweightKG == nil && (payload.Bodyweight.Set || payload.BodyweightKG.Set),
+		ClearHeight:     payload.HeightCM.Set && payload.HeightCM.Value == nil,
+		ClearBirthYear:  payload.BirthYear.Set && payload.BirthYear.Value == nil,
+	})
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to update profile")
+		return
+	}
+	response.JSON(w, http.StatusOK, meResponse(user, profile))
+}
+
+// nullable is a JSON field that tells an explicit null apart from a missing field: Set is true whenever
+// the field was present, and Value is nil when it was null.
+type nullable[T any] struct {
+	Value *T
+	Set   bool
+}
+
+func (n *nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// weekdays lists the names training days are given by, Monday first.
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+func meResponse(u *models.User, p *models.Profile) map[string]any {
+	trainingDays := p.TrainingDays
+	if trainingDays == nil {
+		trainingDays = []string{}
+	}
+	var bodyweight *float64
+	if p.BodyweightKG != nil {
+		converted := units.FromKG(*p.BodyweightKG, p.Units)
+		bodyweight = &converted
+	}
+	return map[string]any{
+		"id":                u.ID,
+		"email":             u.Email,
+		"email_verified_at": u.EmailVerifiedAt,
+		"totp_enabled":      u.TOTPEnabledAt != nil,
+		"created_at":        u.CreatedAt,
+		"profile": map[string]any{
+			"bodyweight_kg": p.BodyweightKG,
+			"bodyweight":    bodyweight,
+			"unit":          units.Symbol(p.Units),
+			"height_cm":     p.HeightCM,
+			"birth_year":    p.BirthYear,
+			"experience":    p.Experience,
+			"units":         p.Units,
+			"time_zone":     p.TimeZone,
+			"training_days": trainingDays,
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+)
+
+// defaultConsistencyWeeks is how many weeks GET /stats/consistency covers without ?weeks.
+const defaultConsistencyWeeks = 12
+
+// Consistency reports training streaks and how often, how long and on which days the user trained over
+// the last weeks, e.g. ?weeks=26.
+func (h *Handler) Consistency(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	weeks := defaultConsistencyWeeks
+	if raw := r.URL.Query().Get("weeks"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "weeks must be a number")
+			return
+		}
+		if err := validation.ValidateIntRange(n, 1, 52, "weeks"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		weeks = n
+	}
+
+	stats, err := h.Stats.Consistency(r.Context(), userID, weeks)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to compute stats")
+		return
+	}
+	weekly := make([]map[string]any, 0, len(stats.Weeks))
+	for _, week := range stats.Weeks {
+		weekly = append(weekly, map[string]any{
+			"week_start": week.WeekStart.Format(time.DateOnly),
+			"sessions":   week.Sessions,
+		})
+	}
+	byWeekday := make(map[string]int, len(stats.SessionsByWeekday))
+	for day, n := range stats.SessionsByWeekday {
+		byWeekday[strings.ToLower(day.String())] = n
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"current_streak_weeks":     stats.CurrentStreakWeeks,
+		"longest_streak_weeks":     stats.LongestStreakWeeks,
+		"total_sessions":           stats.TotalSessions,
+		"weeks":                    weekly,
+		"sessions_per_week":        stats.SessionsPerWeek,
+		"average_duration_seconds": stats.AverageDurationSeconds,
+		"sessions_by_weekday":      byWeekday,
+	})
+}
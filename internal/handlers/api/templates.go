@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SaveTemplate saves a session's structure as a template. The body is optional and may name the template.
+func (h *Handler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+
+	// Limit request body size
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Name *string `json:"name"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		handleJSONError(w, err)
+		return
+	}
+	var name string
+	if payload.Name != nil {
+		if err := validation.ValidateStringLength(*payload.Name, 1, 100, "name"); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		name = *payload.Name
+	}
+
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	template, err := h.Templates.SaveTemplate(r.Context(), userID, sessionID, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSessionNotFound):
+			response.Error(w, http.StatusNotFound, "session not found")
+		case errors.Is(err, services.ErrEmptyTemplate):
+			response.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, "failed to save template")
+		}
+		return
+	}
+	response.JSON(w, http.StatusCreated, templateResponse(template, system))
+}
+
+func (h *Handler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	templates, err := h.Templates.ListTemplates(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list templates")
+		return
+	}
+	out := make([]map[string]any, 0, len(templates))
+	for _, t := range templates {
+		out = append(out, templateResponse(t, system))
+	}
+	response.JSON(w, http.StatusOK, out)
+}
+
+// createSessionFromTemplate answers POST /sessions with a template_id. Sessions from a template are
+// planned or in progress; a completed one would have nothing to plan.
+func (h *Handler) createSessionFromTemplate(w http.ResponseWriter, r *http.Request, userID uuid.UUID, rawTemplateID string, performedAt *time.Time, sessionType, notes *string, status string) {
+	templateID, err := uuid.Parse(rawTemplateID)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid template ID")
+		return
+	}
+	if status == models.SessionCompleted {
+		response.Error(w, http.StatusBadRequest, "sessions from a template are planned or in_progress")
+		return
+	}
+
+	system, err := h.unitSystem(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load profile")
+		return
+	}
+	session, err := h.Templates.CreateSessionFromTemplate(r.Context(), userID, templateID, performedAt, sessionType, notes, status)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTemplateNotFound):
+			response.Error(w, http.StatusNotFound, "template not found")
+		default:
+			response.Error(w, http.StatusInternalServerError, "failed to create session")
+		}
+		return
+	}
+	response.JSON(w, http.StatusCreated, sessionResponse(session, system))
+}
+
+func templateResponse(t *models.SessionTemplate, system string) map[string]any {
+	groups := make([]map[string]any, 0, len(t.Groups))
+	for _, g := range t.Groups {
+		groups = append(groups, map[string]any{
+			"group_index":  g.GroupIndex,
+			"kind":         g.Kind,
+			"rounds":       g.Rounds,
+			"rest_seconds": g.RestSeconds,
+		})
+	}
+	sets := make([]map[string]any, 0, len(t.Sets))
+	for _, s := range t.Sets {
+		sets = append(sets, map[string]any{
+			"position":         s.Position,
+			"exercise_id":      s.ExerciseID,
+			"kind":             s.Kind,
+			"reps":             s.Reps,
+			"weight_kg":        s.WeightKG,
+			"weight":           units.FromKG(s.WeightKG, system),
+			"unit":             units.Symbol(system),
+			"duration_seconds": s.DurationSeconds,
+			"distance_m":       s.DistanceM,
+			"rest_seconds":     s.RestSeconds,
+			"group_index":      s.GroupIndex,
+			"round":            s.Round,
+		})
+	}
+	return map[string]any{
+		"id":           t.ID,
+		"name":         t.Name,
+		"session_type": t.SessionType,
+		"created_at":   t.CreatedAt,
+		"groups":       groups,
+		"sets":         sets,
+	}
+}
+
+func plannedSetResponse(p models.PlannedSet, system string) map[string]any {
+	return map[string]any{
+		"id":               p.ID,
+		"position":         p.Position,
+		"exercise_id":      p.ExerciseID,
+		"kind":             p.Kind,
+		"reps":             p.Reps,
+		"weight_kg":        p.WeightKG,
+		"weight":           units.FromKG(p.WeightKG, system),
+		"unit":             units.Symbol(system),
+		"duration_seconds": p.DurationSeconds,
+		"distance_m":       p.DistanceM,
+		"rest_seconds":     p.RestSeconds,
+		"group_id":         p.GroupID,
+		"round":            p.Round,
+	}
+}
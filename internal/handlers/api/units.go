@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+	"github.com/google/uuid"
+)
+
+// unitSystem returns the caller's preferred unit system from their profile.
+func (h *Handler) unitSystem(ctx context.Context, userID uuid.UUID) (string, error) {
+	profile, err := h.Profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return profile.Units, nil
+}
+
+// resolveWeight turns a request weight into kilograms. weight is in the user's unit system and
+// weightKG is always kilograms; clients send at most one. The allowed range is given in kilograms
+// and checked in whichever unit the client used, so error messages quote numbers the client knows.
+// Neither field counts as zero.
+func resolveWeight(weight, weightKG *float64, system string, minKG, maxKG float64) (float64, error) {
+	switch {
+	case weight != nil && weightKG != nil:
+		return 0, fmt.Errorf("%w: provide either weight or weight_kg, not both", validation.ErrInvalidValue)
+	case weight != nil:
+		min, max := units.FromKG(minKG, system), units.FromKG(maxKG, system)
+		if err := validation.ValidateFloatRange(*weight, min, max, "weight"); err != nil {
+			return 0, err
+		}
+		return units.ToKG(*weight, system), nil
+	}
+	var kg float64
+	if weightKG != nil {
+		kg = *weightKG
+	}
+	if err := validation.ValidateFloatRange(kg, minKG, maxKG, "weight_kg"); err != nil {
+		return 0, err
+	}
+	return kg, nil
+}
+
+func setResponse(set models.Set, system string) map[string]any {
+	return map[string]any{
+		"id":                  set.ID,
+		"session_id":          set.SessionID,
+		"exercise_id":         set.ExerciseID,
+		"set_index":           set.SetIndex,
+		"kind":                set.Kind,
+		"reps":                set.Reps,
+		"weight_kg":           set.WeightKG,
+		"weight":              units.FromKG(set.WeightKG, system),
+		"unit":                units.Symbol(system),
+		"rpe":                 set.RPE,
+		"duration_seconds":    set.DurationSeconds,
+		"distance_m":          set.DistanceM,
+		"group_id":            set.GroupID,
+		"round":               set.Round,
+		"completed_at":        set.CompletedAt,
+		"rest_seconds":        set.RestSeconds,
+		"actual_rest_seconds": set.ActualRestSeconds,
+	}
+}
+
+// sessionResponse lists the sets outside any group under "sets" and nests the rest under their group,
+// ordered by round.
+func sessionResponse(s *models.Session, system string) map[string]any {
+	sets := make([]map[string]any, 0, len(s.Sets))
+	grouped := make(map[uuid.UUID][]models.Set)
+	for _, set := range s.Sets {
+		if set.GroupID != nil {
+			grouped[*set.GroupID] = append(grouped[*set.GroupID], set)
+			continue
+		}
+		sets = append(sets, setResponse(set, system))
+	}
+
+	groups := make([]map[string]any, 0, len(s.Groups))
+	for _, g := range s.Groups {
+		members := grouped[g.ID]
+		sort.SliceStable(members, func(i, j int) bool {
+			return roundOf(members[i]) < roundOf(members[j])
+		})
+		groupSets := make([]map[string]any, 0, len(members))
+		for _, set := range members {
+			groupSets = append(groupSets, setResponse(set, system))
+		}
+		group := groupResponse(g)
+		group["sets"] = groupSets
+		groups = append(groups, group)
+	}
+
+	planned := make([]map[string]any, 0, len(s.PlannedSets))
+	for _, p := range s.PlannedSets {
+		planned = append(planned, plannedSetResponse(p, system))
+	}
+
+	out := sessionFields(s)
+	out["sets"] = sets
+	out["groups"] = groups
+	out["planned_sets"] = planned
+	return out
+}
+
+// sessionFields is a session without its sets. The duration is known once a started session has
+// finished.
+func sessionFields(s *models.Session) map[string]any {
+	var duration *int
+	if s.StartedAt != nil && s.FinishedAt != nil {
+		seconds := int(s.FinishedAt.Sub(*s.StartedAt).Seconds())
+		duration = &seconds
+	}
+	return map[string]any{
+		"id":               s.ID,
+		"user_id":          s.UserID,
+		"performed_at":     s.PerformedAt,
+		"session_type":     s.SessionType,
+		"notes":            s.Notes,
+		"status":           s.Status,
+		"started_at":       s.StartedAt,
+		"finished_at":      s.FinishedAt,
+		"duration_seconds": duration,
+		"rescheduled_from": s.RescheduledFrom,
+		"missed_count":     s.MissedCount,
+	}
+}
+
+func roundOf(set models.Set) int {
+	if set.Round == nil {
+		return 0
+	}
+	return *set.Round
+}
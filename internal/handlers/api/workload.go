@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+)
+
+// CheckIn records today's readiness: sleep, soreness and stress, each rated 1 to 5.
+func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Sleep    *int `json:"sleep"`
+		Soreness *int `json:"soreness"`
+		Stress   *int `json:"stress"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+	if payload.Sleep == nil || payload.Soreness == nil || payload.Stress == nil {
+		response.Error(w, http.StatusBadRequest, "sleep, soreness and stress are required")
+		return
+	}
+
+	checkIn, err := h.Workload.CheckIn(r.Context(), userID, *payload.Sleep, *payload.Soreness, *payload.Stress)
+	switch {
+	case errors.Is(err, services.ErrInvalidCheckIn):
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to save check-in")
+		return
+	}
+	response.JSON(w, http.StatusCreated, checkInResponse(checkIn))
+}
+
+// GetWorkload reports the acute:chronic workload ratio, today's readiness and how far both scale down
+// suggested volume.
+func (h *Handler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	workload, err := h.Workload.Workload(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to compute workload")
+		return
+	}
+
+	var readiness map[string]any
+	if workload.Readiness != nil {
+		readiness = checkInResponse(workload.Readiness)
+		readiness["score"] = workload.ReadinessScore
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"acute_load":   workload.AcuteLoad,
+		"chronic_load": workload.ChronicLoad,
+		"ratio":        workload.Ratio,
+		"readiness":    readiness,
+		"volume_scale": workload.VolumeScale,
+		"notes":        workload.Notes,
+	})
+}
+
+func checkInResponse(c *models.ReadinessCheckIn) map[string]any {
+	return map[string]any{
+		"date":     c.Day.Format(time.DateOnly),
+		"sleep":    c.Sleep,
+		"soreness": c.Soreness,
+		"stress":   c.Stress,
+	}
+}
@@ -9,9 +9,21 @@ import (
 
 // App wires shared dependencies for HTTP handlers.
 type App struct {
-	AuthService    contracts.AuthService
-	SessionService contracts.SessionService
-	PlanService    contracts.PlanService
-	Logger         *log.Logger
-	Config         config.Config
+	AuthService        contracts.AuthService
+	SocialLoginService contracts.SocialLoginService
+	ProfileService     contracts.ProfileService
+	ExportService      contracts.ExportService
+	BodyweightService  contracts.BodyweightService
+	EquipmentService   contracts.EquipmentService
+	SessionService     contracts.SessionService
+	TemplateService    contracts.TemplateService
+	AdherenceService   contracts.AdherenceService
+	CalendarService    contracts.CalendarService
+	StatsService       contracts.StatsService
+	HistoryService     contracts.HistoryService
+	DashboardService   contracts.DashboardService
+	WorkloadService    contracts.WorkloadService
+	PlanService        contracts.PlanService
+	Logger             *log.Logger
+	Config             config.Config
 }
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/alexanderramin/kalistheniks/internal/validation"
+)
+
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if err := validation.ValidatePassword(payload.NewPassword); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, err := h.AuthService.ChangePassword(r.Context(), userID, payload.CurrentPassword, payload.NewPassword)
+	switch {
+	case errors.Is(err, services.ErrAccountLocked):
+		response.Error(w, http.StatusTooManyRequests, "too many failed login attempts, try again later")
+		return
+	case errors.Is(err, services.ErrInvalidCredentials):
+		response.Error(w, http.StatusForbidden, "invalid password")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to change password")
+		return
+	}
+	// Every other token for the account stopped working; the client swaps in this one
+	response.JSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+func (h *Handler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Password string `json:"password"`
+		NewEmail string `json:"new_email"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if err := validation.ValidateEmail(payload.NewEmail); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid email address")
+		return
+	}
+
+	err := h.AuthService.RequestEmailChange(r.Context(), userID, payload.Password, payload.NewEmail)
+	switch {
+	case errors.Is(err, services.ErrAccountLocked):
+		response.Error(w, http.StatusTooManyRequests, "too many failed login attempts, try again later")
+		return
+	case errors.Is(err, services.ErrInvalidCredentials):
+		response.Error(w, http.StatusForbidden, "invalid password")
+		return
+	case errors.Is(err, services.ErrSameEmail):
+		response.Error(w, http.StatusBadRequest, "new email matches the current one")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to request email change")
+		return
+	}
+	// Same response whether or not the address is taken to avoid user enumeration
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "check the new address for a confirmation link",
+	})
+}
+
+func (h *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	changeToken := r.URL.Query().Get("token")
+	if changeToken == "" {
+		response.Error(w, http.StatusBadRequest, "invalid or expired email change token")
+		return
+	}
+
+	err := h.AuthService.ConfirmEmailChange(r.Context(), changeToken)
+	switch {
+	case errors.Is(err, services.ErrInvalidEmailChange):
+		response.Error(w, http.StatusBadRequest, "invalid or expired email change token")
+		return
+	case errors.Is(err, services.ErrEmailTaken):
+		response.Error(w, http.StatusConflict, "email already registered")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to change email")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"message": "email changed"})
+}
+
+func (h *Handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Password string `json:"password"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	deleteAt, err := h.AuthService.DeleteAccount(r.Context(), userID, payload.Password)
+	switch {
+	case errors.Is(err, services.ErrAccountLocked):
+		response.Error(w, http.StatusTooManyRequests, "too many failed login attempts, try again later")
+		return
+	case errors.Is(err, services.ErrInvalidCredentials):
+		response.Error(w, http.StatusForbidden, "invalid password")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to delete account")
+		return
+	}
+	// All sessions are signed out; signing in again before deletion_scheduled_at restores the account
+	response.JSON(w, http.StatusAccepted, map[string]any{
+		"message":               "account scheduled for deletion",
+		"deletion_scheduled_at": deleteAt,
+	})
+}
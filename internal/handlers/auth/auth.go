@@ -6,18 +6,24 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/alexanderramin/kalistheniks/internal/config"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/contracts"
+	"github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
 	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
 	"github.com/alexanderramin/kalistheniks/internal/validation"
 )
 
 type Handler struct {
-	AuthService contracts.AuthService
+	AuthService        contracts.AuthService
+	SocialLoginService contracts.SocialLoginService
+	// SignupPolicy is config.SignupPolicyConceal or config.SignupPolicyConflict; empty means conceal.
+	SignupPolicy string
 }
 
-func New(auth contracts.AuthService) *Handler {
-	return &Handler{AuthService: auth}
+func New(auth contracts.AuthService, social contracts.SocialLoginService, signupPolicy string) *Handler {
+	return &Handler{AuthService: auth, SocialLoginService: social, SignupPolicy: signupPolicy}
 }
 
 func (h *Handler) Health(w http.ResponseWriter, _ *http.Request) {
@@ -66,7 +72,23 @@ func (h *Handler) Signup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, token, err := h.AuthService.Signup(r.Context(), payload.Email, payload.Password)
-	if err != nil {
+	if h.SignupPolicy != config.SignupPolicyConflict {
+		// Concealing: new and already registered emails get the same answer and the client logs in next
+		if err != nil && !errors.Is(err, services.ErrEmailTaken) {
+			response.Error(w, http.StatusBadRequest, "failed to create account")
+			return
+		}
+		response.JSON(w, http.StatusAccepted, map[string]string{
+			"message": "check your email to finish signing up",
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrEmailTaken):
+		response.Error(w, http.StatusConflict, "email already registered")
+		return
+	case err != nil:
 		// Don't expose internal error details
 		response.Error(w, http.StatusBadRequest, "failed to create account")
 		return
@@ -113,6 +135,14 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, token, err := h.AuthService.Login(r.Context(), payload.Email, payload.Password)
+	if errors.Is(err, services.ErrMFARequired) {
+		// Password was correct; the client must exchange the challenge and a second factor for a token
+		response.JSON(w, http.StatusOK, map[string]any{
+			"mfa_required":    true,
+			"challenge_token": token,
+		})
+		return
+	}
 	if err != nil {
 		// Use generic error message to avoid user enumeration
 		response.Error(w, http.StatusUnauthorized, "invalid credentials")
@@ -124,10 +154,262 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if err := validation.ValidateEmail(payload.Email); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid email address")
+		return
+	}
+
+	if err := h.AuthService.RequestPasswordReset(r.Context(), payload.Email); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to request password reset")
+		return
+	}
+	// Same response whether or not the account exists to avoid user enumeration
+	response.JSON(w, http.StatusAccepted, map[string]string{
+		"message": "if an account exists for that email, a reset link has been sent",
+	})
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if payload.Token == "" {
+		response.Error(w, http.StatusBadRequest, "invalid or expired reset token")
+		return
+	}
+	if err := validation.ValidatePassword(payload.Password); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := h.AuthService.ResetPassword(r.Context(), payload.Token, payload.Password)
+	switch {
+	case errors.Is(err, services.ErrInvalidResetToken):
+		response.Error(w, http.StatusBadRequest, "invalid or expired reset token")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to reset password")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"message": "password updated"})
+}
+
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	verifyToken := r.URL.Query().Get("token")
+	if verifyToken == "" {
+		response.Error(w, http.StatusBadRequest, "invalid or expired verification token")
+		return
+	}
+
+	err := h.AuthService.VerifyEmail(r.Context(), verifyToken)
+	switch {
+	case errors.Is(err, services.ErrInvalidVerifyToken):
+		response.Error(w, http.StatusBadRequest, "invalid or expired verification token")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to verify email")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"message": "email verified"})
+}
+
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	err := h.AuthService.ResendVerification(r.Context(), userID)
+	switch {
+	case errors.Is(err, services.ErrAlreadyVerified):
+		response.Error(w, http.StatusConflict, "email already verified")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to send verification email")
+		return
+	}
+	response.JSON(w, http.StatusAccepted, map[string]string{"message": "verification email sent"})
+}
+
+func (h *Handler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	if payload.ChallengeToken == "" || payload.Code == "" {
+		response.Error(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	user, token, err := h.AuthService.VerifyMFA(r.Context(), payload.ChallengeToken, payload.Code)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"user":  userResponse(user),
+		"token": token,
+	})
+}
+
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	secret, uri, err := h.AuthService.EnrollTOTP(r.Context(), userID)
+	switch {
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		response.Error(w, http.StatusConflict, "two-factor authentication already enabled")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to start two-factor enrollment")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]string{
+		"secret":           secret,
+		"provisioning_uri": uri,
+	})
+}
+
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Code string `json:"code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	codes, err := h.AuthService.ConfirmTOTP(r.Context(), userID, payload.Code)
+	switch {
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		response.Error(w, http.StatusConflict, "two-factor authentication already enabled")
+		return
+	case errors.Is(err, services.ErrMFANotEnrolled):
+		response.Error(w, http.StatusBadRequest, "two-factor enrollment not started")
+		return
+	case errors.Is(err, services.ErrInvalidMFACode):
+		response.Error(w, http.StatusBadRequest, "invalid code")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to enable two-factor authentication")
+		return
+	}
+	// Recovery codes are shown once; only their hashes are stored
+	response.JSON(w, http.StatusOK, map[string]any{"recovery_codes": codes})
+}
+
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.CurrentUserID(r)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1MB
+
+	var payload struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		handleJSONError(w, err)
+		return
+	}
+
+	err := h.AuthService.DisableTOTP(r.Context(), userID, payload.Password, payload.Code)
+	switch {
+	case errors.Is(err, services.ErrAccountLocked):
+		response.Error(w, http.StatusTooManyRequests, "too many failed login attempts, try again later")
+		return
+	case errors.Is(err, services.ErrMFANotEnrolled):
+		response.Error(w, http.StatusBadRequest, "two-factor authentication not enabled")
+		return
+	case errors.Is(err, services.ErrInvalidCredentials), errors.Is(err, services.ErrInvalidMFACode):
+		response.Error(w, http.StatusForbidden, "invalid password or code")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to disable two-factor authentication")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"message": "two-factor authentication disabled"})
+}
+
 func userResponse(u *models.User) map[string]any {
 	return map[string]any{
-		"id":         u.ID,
-		"email":      u.Email,
-		"created_at": u.CreatedAt,
+		"id":                u.ID,
+		"email":             u.Email,
+		"email_verified_at": u.EmailVerifiedAt,
+		"totp_enabled":      u.TOTPEnabledAt != nil,
+		"created_at":        u.CreatedAt,
+	}
+}
+
+// handleJSONError provides consistent error handling for JSON decoding errors
+func handleJSONError(w http.ResponseWriter, err error) {
+	var syntaxErr *json.SyntaxError
+	var unmarshalErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		response.Error(w, http.StatusBadRequest, "malformed JSON")
+	case errors.As(err, &unmarshalErr):
+		response.Error(w, http.StatusBadRequest, "invalid field type")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		response.Error(w, http.StatusBadRequest, "malformed JSON")
+	case err.Error() == "http: request body too large":
+		response.Error(w, http.StatusRequestEntityTooLarge, "request body too large")
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid request body")
 	}
 }
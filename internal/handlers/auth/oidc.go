@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
+	"github.com/alexanderramin/kalistheniks/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) ListProviders(w http.ResponseWriter, _ *http.Request) {
+	response.JSON(w, http.StatusOK, map[string]any{"providers": h.SocialLoginService.Providers()})
+}
+
+// StartOIDCLogin redirects the browser to the provider's consent page.
+func (h *Handler) StartOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.SocialLoginService.StartLogin(r.Context(), chi.URLParam(r, "provider"))
+	switch {
+	case errors.Is(err, services.ErrUnknownProvider):
+		response.Error(w, http.StatusNotFound, "unknown identity provider")
+		return
+	case errors.Is(err, services.ErrIdentityProvider):
+		response.Error(w, http.StatusBadGateway, "identity provider unavailable")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to start login")
+		return
+	}
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallback completes the login the provider redirected back from and answers like Login.
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("error") != "" {
+		// The user declined or the provider refused; the state is left to expire.
+		response.Error(w, http.StatusUnauthorized, "login was cancelled at the identity provider")
+		return
+	}
+	if q.Get("state") == "" || q.Get("code") == "" {
+		response.Error(w, http.StatusBadRequest, "invalid or expired login state")
+		return
+	}
+
+	user, token, err := h.SocialLoginService.CompleteLogin(r.Context(), chi.URLParam(r, "provider"), q.Get("state"), q.Get("code"))
+	switch {
+	case errors.Is(err, services.ErrMFARequired):
+		response.JSON(w, http.StatusOK, map[string]any{
+			"mfa_required":    true,
+			"challenge_token": token,
+		})
+		return
+	case errors.Is(err, services.ErrUnknownProvider):
+		response.Error(w, http.StatusNotFound, "unknown identity provider")
+		return
+	case errors.Is(err, services.ErrInvalidLoginState):
+		response.Error(w, http.StatusBadRequest, "invalid or expired login state")
+		return
+	case errors.Is(err, services.ErrIdentityConflict):
+		response.Error(w, http.StatusConflict, "an account with this email already exists; log in with your password")
+		return
+	case errors.Is(err, services.ErrIdentityNoEmail):
+		response.Error(w, http.StatusUnprocessableEntity, "identity provider did not share an email address")
+		return
+	case errors.Is(err, services.ErrAccountLocked):
+		response.Error(w, http.StatusTooManyRequests, "too many failed login attempts, try again later")
+		return
+	case errors.Is(err, services.ErrIdentityProvider):
+		response.Error(w, http.StatusUnauthorized, "identity provider login failed")
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to complete login")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"user":  userResponse(user),
+		"token": token,
+	})
+}
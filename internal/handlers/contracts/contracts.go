@@ -12,14 +12,96 @@ type AuthService interface {
 	Signup(ctx context.Context, email, password string) (*models.User, string, error)
 	Login(ctx context.Context, email, password string) (*models.User, string, error)
 	VerifyToken(ctx context.Context, token string) (string, error)
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+	VerifyEmail(ctx context.Context, verifyToken string) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (string, string, error)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID uuid.UUID, password, code string) error
+	VerifyMFA(ctx context.Context, challenge, code string) (*models.User, string, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (string, error)
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, changeToken string) error
+	DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (time.Time, error)
+}
+
+type SocialLoginService interface {
+	Providers() []string
+	StartLogin(ctx context.Context, provider string) (string, error)
+	CompleteLogin(ctx context.Context, provider, state, code string) (*models.User, string, error)
+}
+
+type ProfileService interface {
+	GetMe(ctx context.Context, userID uuid.UUID) (*models.User, *models.Profile, error)
+	GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, update models.ProfileUpdate) (*models.User, *models.Profile, error)
+}
+
+type ExportService interface {
+	Export(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+}
+
+type BodyweightService interface {
+	LogBodyweight(ctx context.Context, userID uuid.UUID, weightKG float64, measuredAt *time.Time) (*models.BodyweightEntry, error)
+	ListBodyweight(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error)
+	SessionMetrics(ctx context.Context, userID, sessionID uuid.UUID, kinds []string) (*models.SessionMetrics, error)
+}
+
+type EquipmentService interface {
+	GetEquipment(ctx context.Context, userID uuid.UUID) (*models.Equipment, error)
+	UpdateEquipment(ctx context.Context, e *models.Equipment) (*models.Equipment, error)
+	PlateLoading(ctx context.Context, userID uuid.UUID, targetKG float64) (*models.PlateLoading, error)
 }
 
 type SessionService interface {
-	CreateSession(ctx context.Context, userID uuid.UUID, performedAt *time.Time, sessionType *string, notes *string) (*models.Session, error)
-	AddSet(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, exerciseID uuid.UUID, setIndex, reps int, weight float64, rpe *int) (*models.Set, error)
+	CreateSession(ctx context.Context, userID uuid.UUID, performedAt *time.Time, sessionType *string, notes *string, status string) (*models.Session, error)
+	StartSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error)
+	FinishSession(ctx context.Context, userID, sessionID uuid.UUID, status string) (*models.Session, error)
+	AddSet(ctx context.Context, userID uuid.UUID, set *models.Set) (*models.Set, error)
+	CreateGroup(ctx context.Context, userID, sessionID uuid.UUID, kind string, rounds, restSeconds *int) (*models.SetGroup, error)
+	AddPlannedSet(ctx context.Context, userID uuid.UUID, planned *models.PlannedSet) (*models.PlannedSet, error)
+	GetSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error)
 	ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
 }
 
+type TemplateService interface {
+	SaveTemplate(ctx context.Context, userID, sessionID uuid.UUID, name string) (*models.SessionTemplate, error)
+	ListTemplates(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error)
+	CreateSessionFromTemplate(ctx context.Context, userID, templateID uuid.UUID, performedAt *time.Time, sessionType, notes *string, status string) (*models.Session, error)
+}
+
+type AdherenceService interface {
+	SessionAdherence(ctx context.Context, userID, sessionID uuid.UUID) (*models.SessionAdherence, error)
+	WeeklyAdherence(ctx context.Context, userID uuid.UUID, weeks int) ([]models.WeeklyAdherence, error)
+}
+
+type CalendarService interface {
+	Calendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.CalendarDay, error)
+	Reschedule(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error)
+	IssueFeedToken(ctx context.Context, userID uuid.UUID) (string, error)
+	RevokeFeedToken(ctx context.Context, userID uuid.UUID) error
+	Feed(ctx context.Context, token string) (*models.CalendarFeed, error)
+}
+
+type StatsService interface {
+	Consistency(ctx context.Context, userID uuid.UUID, weeks int) (*models.ConsistencyStats, error)
+}
+
+type HistoryService interface {
+	ExerciseHistory(ctx context.Context, userID, exerciseID uuid.UUID, window string, kinds []string) (*models.ExerciseHistory, error)
+}
+
+type DashboardService interface {
+	Dashboard(ctx context.Context, userID uuid.UUID) (*models.Dashboard, error)
+}
+
+type WorkloadService interface {
+	CheckIn(ctx context.Context, userID uuid.UUID, sleep, soreness, stress int) (*models.ReadinessCheckIn, error)
+	Workload(ctx context.Context, userID uuid.UUID) (*models.Workload, error)
+}
+
 type PlanService interface {
 	NextSuggestion(ctx context.Context, userID uuid.UUID) (*models.PlanSuggestion, error)
 }
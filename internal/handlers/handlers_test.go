@@ -2,26 +2,44 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/alexanderramin/kalistheniks/internal/config"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/mocks"
 	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
 
-//go:generate mockgen -source=./contracts/contracts.go -destination=./mocks/mocks.go -package=mocks AuthService,SessionService,PlanService
+//go:generate mockgen -source=./contracts/contracts.go -destination=./mocks/mocks.go -package=mocks AuthService,SocialLoginService,ProfileService,ExportService,BodyweightService,EquipmentService,SessionService,TemplateService,AdherenceService,CalendarService,StatsService,HistoryService,DashboardService,WorkloadService,PlanService
 type HandlerSuite struct {
 	suite.Suite
 	ctrl        *gomock.Controller
 	authMock    *mocks.MockAuthService
+	socialMock  *mocks.MockSocialLoginService
+	profileMock *mocks.MockProfileService
+	exportMock  *mocks.MockExportService
+	bodyMock    *mocks.MockBodyweightService
+	equipMock   *mocks.MockEquipmentService
 	sessionMock *mocks.MockSessionService
+	templMock   *mocks.MockTemplateService
+	adhereMock  *mocks.MockAdherenceService
+	calMock     *mocks.MockCalendarService
+	statsMock   *mocks.MockStatsService
+	histMock    *mocks.MockHistoryService
+	dashMock    *mocks.MockDashboardService
+	loadMock    *mocks.MockWorkloadService
 	planMock    *mocks.MockPlanService
 	handler     http.Handler
 }
@@ -33,13 +51,37 @@ func TestHandlerSuite(t *testing.T) {
 func (s *HandlerSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.authMock = mocks.NewMockAuthService(s.ctrl)
+	s.socialMock = mocks.NewMockSocialLoginService(s.ctrl)
+	s.profileMock = mocks.NewMockProfileService(s.ctrl)
+	s.exportMock = mocks.NewMockExportService(s.ctrl)
+	s.bodyMock = mocks.NewMockBodyweightService(s.ctrl)
+	s.equipMock = mocks.NewMockEquipmentService(s.ctrl)
 	s.sessionMock = mocks.NewMockSessionService(s.ctrl)
+	s.templMock = mocks.NewMockTemplateService(s.ctrl)
+	s.adhereMock = mocks.NewMockAdherenceService(s.ctrl)
+	s.calMock = mocks.NewMockCalendarService(s.ctrl)
+	s.statsMock = mocks.NewMockStatsService(s.ctrl)
+	s.histMock = mocks.NewMockHistoryService(s.ctrl)
+	s.dashMock = mocks.NewMockDashboardService(s.ctrl)
+	s.loadMock = mocks.NewMockWorkloadService(s.ctrl)
 	s.planMock = mocks.NewMockPlanService(s.ctrl)
 
 	app := &App{
-		AuthService:    s.authMock,
-		SessionService: s.sessionMock,
-		PlanService:    s.planMock,
+		AuthService:        s.authMock,
+		SocialLoginService: s.socialMock,
+		ProfileService:     s.profileMock,
+		ExportService:      s.exportMock,
+		BodyweightService:  s.bodyMock,
+		EquipmentService:   s.equipMock,
+		SessionService:     s.sessionMock,
+		TemplateService:    s.templMock,
+		AdherenceService:   s.adhereMock,
+		CalendarService:    s.calMock,
+		StatsService:       s.statsMock,
+		HistoryService:     s.histMock,
+		DashboardService:   s.dashMock,
+		WorkloadService:    s.loadMock,
+		PlanService:        s.planMock,
 	}
 	s.handler = Router(app)
 }
@@ -50,6 +92,11 @@ func (s *HandlerSuite) TearDownTest() {
 	}
 }
 
+// expectUnits stubs the profile lookup handlers make to convert weights.
+func (s *HandlerSuite) expectUnits(userID uuid.UUID, system string) {
+	s.profileMock.EXPECT().GetProfile(gomock.Any(), userID).Return(&models.Profile{UserID: userID, Units: system}, nil)
+}
+
 func (s *HandlerSuite) TestHealth() {
 	resp := s.doRequest(http.MethodGet, "/health", nil, "")
 	s.Equal(http.StatusOK, resp.StatusCode)
@@ -61,7 +108,15 @@ func (s *HandlerSuite) TestSignup() {
 		s.authMock.EXPECT().Signup(gomock.Any(), "user@example.com", "Password123").Return(user, "token", nil)
 
 		resp := s.doRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"user@example.com","password":"Password123"}`), "")
-		s.Equal(http.StatusCreated, resp.StatusCode)
+		s.Equal(http.StatusAccepted, resp.StatusCode)
+		s.NotContains(s.readBody(resp), "token")
+	})
+
+	s.Run("duplicate email is concealed", func() {
+		s.authMock.EXPECT().Signup(gomock.Any(), "taken@example.com", "Password123").Return(nil, "", services.ErrEmailTaken)
+
+		resp := s.doRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"taken@example.com","password":"Password123"}`), "")
+		s.Equal(http.StatusAccepted, resp.StatusCode)
 	})
 
 	s.Run("bad input", func() {
@@ -121,6 +176,13 @@ func (s *HandlerSuite) TestLogin() {
 		s.Equal(http.StatusUnauthorized, resp.StatusCode)
 	})
 
+	s.Run("locked account looks like invalid credentials", func() {
+		s.authMock.EXPECT().Login(gomock.Any(), "login@example.com", "password").Return(nil, "", services.ErrInvalidCredentials)
+
+		resp := s.doRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email":"login@example.com","password":"password"}`), "")
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+
 	s.Run("empty email", func() {
 		resp := s.doRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email":"","password":"password"}`), "")
 		s.Equal(http.StatusUnauthorized, resp.StatusCode)
@@ -142,6 +204,481 @@ func (s *HandlerSuite) TestLogin() {
 	})
 }
 
+func (s *HandlerSuite) TestSignupConflictPolicy() {
+	s.handler = Router(&App{
+		AuthService:    s.authMock,
+		SessionService: s.sessionMock,
+		PlanService:    s.planMock,
+		Config:         config.Config{DuplicateSignupPolicy: config.SignupPolicyConflict},
+	})
+
+	s.Run("success returns token", func() {
+		user := &models.User{ID: uuid.New()}
+		s.authMock.EXPECT().Signup(gomock.Any(), "user@example.com", "Password123").Return(user, "token", nil)
+
+		resp := s.doRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"user@example.com","password":"Password123"}`), "")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		s.Contains(s.readBody(resp), "token")
+	})
+
+	s.Run("duplicate email conflicts", func() {
+		s.authMock.EXPECT().Signup(gomock.Any(), "taken@example.com", "Password123").Return(nil, "", services.ErrEmailTaken)
+
+		resp := s.doRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"taken@example.com","password":"Password123"}`), "")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("other errors stay generic", func() {
+		s.authMock.EXPECT().Signup(gomock.Any(), "user@example.com", "Password123").Return(nil, "", services.ErrCreateUser)
+
+		resp := s.doRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"user@example.com","password":"Password123"}`), "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestCredentialRateLimit() {
+	s.handler = Router(&App{
+		AuthService:    s.authMock,
+		SessionService: s.sessionMock,
+		PlanService:    s.planMock,
+		Config:         config.Config{AuthRateLimit: 3},
+	})
+
+	for i := 0; i < 3; i++ {
+		resp := s.doRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email":"","password":""}`), "")
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	}
+	resp := s.doRequest(http.MethodPost, "/signup", bytes.NewBufferString(`{"email":"","password":""}`), "")
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+
+	// Non-credential endpoints keep the global limit
+	resp = s.doRequest(http.MethodGet, "/health", nil, "")
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerSuite) TestPasswordReset() {
+	s.Run("forgot password accepted", func() {
+		s.authMock.EXPECT().RequestPasswordReset(gomock.Any(), "user@example.com").Return(nil)
+
+		resp := s.doRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(`{"email":"user@example.com"}`), "")
+		s.Equal(http.StatusAccepted, resp.StatusCode)
+	})
+
+	s.Run("forgot password invalid email", func() {
+		resp := s.doRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(`{"email":"nope"}`), "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("forgot password service error", func() {
+		s.authMock.EXPECT().RequestPasswordReset(gomock.Any(), "user@example.com").Return(errors.New("smtp down"))
+
+		resp := s.doRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(`{"email":"user@example.com"}`), "")
+		s.Equal(http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	s.Run("reset password success", func() {
+		s.authMock.EXPECT().ResetPassword(gomock.Any(), "reset-token", "NewPassword1").Return(nil)
+
+		resp := s.doRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(`{"token":"reset-token","password":"NewPassword1"}`), "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("reset password invalid token", func() {
+		s.authMock.EXPECT().ResetPassword(gomock.Any(), "used-token", "NewPassword1").Return(services.ErrInvalidResetToken)
+
+		resp := s.doRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(`{"token":"used-token","password":"NewPassword1"}`), "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("reset password weak password", func() {
+		resp := s.doRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(`{"token":"reset-token","password":"weak"}`), "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestEmailVerification() {
+	userID := uuid.New()
+
+	s.Run("verify email success", func() {
+		s.authMock.EXPECT().VerifyEmail(gomock.Any(), "verify-token").Return(nil)
+
+		resp := s.doRequest(http.MethodGet, "/verify-email?token=verify-token", nil, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("verify email missing token", func() {
+		resp := s.doRequest(http.MethodGet, "/verify-email", nil, "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("verify email invalid token", func() {
+		s.authMock.EXPECT().VerifyEmail(gomock.Any(), "stale").Return(services.ErrInvalidVerifyToken)
+
+		resp := s.doRequest(http.MethodGet, "/verify-email?token=stale", nil, "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("resend verification", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().ResendVerification(gomock.Any(), userID).Return(nil)
+
+		resp := s.doRequest(http.MethodPost, "/verify-email/resend", nil, "goodtoken")
+		s.Equal(http.StatusAccepted, resp.StatusCode)
+	})
+
+	s.Run("resend when already verified", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().ResendVerification(gomock.Any(), userID).Return(services.ErrAlreadyVerified)
+
+		resp := s.doRequest(http.MethodPost, "/verify-email/resend", nil, "goodtoken")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("block policy rejects unverified account", func() {
+		s.handler = Router(&App{
+			AuthService:    s.authMock,
+			SessionService: s.sessionMock,
+			PlanService:    s.planMock,
+			Config:         config.Config{EmailVerificationPolicy: config.VerificationPolicyBlock},
+		})
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().IsEmailVerified(gomock.Any(), userID).Return(false, nil)
+
+		resp := s.doRequest(http.MethodGet, "/sessions", nil, "goodtoken")
+		s.Equal(http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestTwoFactor() {
+	userID := uuid.New()
+
+	s.Run("login with 2fa returns challenge", func() {
+		s.authMock.EXPECT().Login(gomock.Any(), "test@example.com", "Password123!").Return(&models.User{ID: userID}, "challenge", services.ErrMFARequired)
+
+		body := bytes.NewBufferString(`{"email":"test@example.com","password":"Password123!"}`)
+		resp := s.doRequest(http.MethodPost, "/login", body, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(true, got["mfa_required"])
+		s.Equal("challenge", got["challenge_token"])
+		s.NotContains(got, "token")
+	})
+
+	s.Run("complete login with code", func() {
+		s.authMock.EXPECT().VerifyMFA(gomock.Any(), "challenge", "123456").Return(&models.User{ID: userID}, "token123", nil)
+
+		body := bytes.NewBufferString(`{"challenge_token":"challenge","code":"123456"}`)
+		resp := s.doRequest(http.MethodPost, "/login/mfa", body, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "token123")
+	})
+
+	s.Run("wrong code", func() {
+		s.authMock.EXPECT().VerifyMFA(gomock.Any(), "challenge", "000000").Return(nil, "", services.ErrInvalidMFACode)
+
+		body := bytes.NewBufferString(`{"challenge_token":"challenge","code":"000000"}`)
+		resp := s.doRequest(http.MethodPost, "/login/mfa", body, "")
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	s.Run("enroll", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().EnrollTOTP(gomock.Any(), userID).Return("SECRET", "otpauth://totp/x", nil)
+
+		resp := s.doRequest(http.MethodPost, "/me/2fa/enroll", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "otpauth://totp/x")
+	})
+
+	s.Run("confirm returns recovery codes", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().ConfirmTOTP(gomock.Any(), userID, "123456").Return([]string{"abcde-fghij"}, nil)
+
+		body := bytes.NewBufferString(`{"code":"123456"}`)
+		resp := s.doRequest(http.MethodPost, "/me/2fa/confirm", body, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "abcde-fghij")
+	})
+
+	s.Run("confirm with wrong code", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().ConfirmTOTP(gomock.Any(), userID, "000000").Return(nil, services.ErrInvalidMFACode)
+
+		body := bytes.NewBufferString(`{"code":"000000"}`)
+		resp := s.doRequest(http.MethodPost, "/me/2fa/confirm", body, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("disable with wrong password", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().DisableTOTP(gomock.Any(), userID, "wrong", "123456").Return(services.ErrInvalidCredentials)
+
+		body := bytes.NewBufferString(`{"password":"wrong","code":"123456"}`)
+		resp := s.doRequest(http.MethodPost, "/me/2fa/disable", body, "goodtoken")
+		s.Equal(http.StatusForbidden, resp.StatusCode)
+	})
+
+	s.Run("disable while locked out", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().DisableTOTP(gomock.Any(), userID, "password123", "123456").Return(services.ErrAccountLocked)
+
+		body := bytes.NewBufferString(`{"password":"password123","code":"123456"}`)
+		resp := s.doRequest(http.MethodPost, "/me/2fa/disable", body, "goodtoken")
+		s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestAccountChanges() {
+	userID := uuid.New()
+
+	s.Run("change password returns replacement token", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().ChangePassword(gomock.Any(), userID, "OldPassword1!", "NewPassword1!").Return("newtoken", nil)
+
+		body := bytes.NewBufferString(`{"current_password":"OldPassword1!","new_password":"NewPassword1!"}`)
+		resp := s.doRequest(http.MethodPost, "/me/password", body, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "newtoken")
+	})
+
+	s.Run("change password with wrong current password", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().ChangePassword(gomock.Any(), userID, "wrong", "NewPassword1!").Return("", services.ErrInvalidCredentials)
+
+		body := bytes.NewBufferString(`{"current_password":"wrong","new_password":"NewPassword1!"}`)
+		resp := s.doRequest(http.MethodPost, "/me/password", body, "goodtoken")
+		s.Equal(http.StatusForbidden, resp.StatusCode)
+	})
+
+	s.Run("change password rejects weak password", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		body := bytes.NewBufferString(`{"current_password":"OldPassword1!","new_password":"short"}`)
+		resp := s.doRequest(http.MethodPost, "/me/password", body, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("request email change", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().RequestEmailChange(gomock.Any(), userID, "Password123!", "new@example.com").Return(nil)
+
+		body := bytes.NewBufferString(`{"password":"Password123!","new_email":"new@example.com"}`)
+		resp := s.doRequest(http.MethodPost, "/me/email", body, "goodtoken")
+		s.Equal(http.StatusAccepted, resp.StatusCode)
+	})
+
+	s.Run("request email change rejects invalid address", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		body := bytes.NewBufferString(`{"password":"Password123!","new_email":"not-an-email"}`)
+		resp := s.doRequest(http.MethodPost, "/me/email", body, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("confirm email change", func() {
+		s.authMock.EXPECT().ConfirmEmailChange(gomock.Any(), "raw").Return(nil)
+
+		resp := s.doRequest(http.MethodGet, "/confirm-email?token=raw", nil, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("confirm email change with stale token", func() {
+		s.authMock.EXPECT().ConfirmEmailChange(gomock.Any(), "stale").Return(services.ErrInvalidEmailChange)
+
+		resp := s.doRequest(http.MethodGet, "/confirm-email?token=stale", nil, "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("confirm email change to an address registered meanwhile", func() {
+		s.authMock.EXPECT().ConfirmEmailChange(gomock.Any(), "raw").Return(services.ErrEmailTaken)
+
+		resp := s.doRequest(http.MethodGet, "/confirm-email?token=raw", nil, "")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestAccountDeletionAndExport() {
+	userID := uuid.New()
+
+	s.Run("delete schedules deletion", func() {
+		deleteAt := time.Date(2030, 1, 31, 12, 0, 0, 0, time.UTC)
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().DeleteAccount(gomock.Any(), userID, "Password123!").Return(deleteAt, nil)
+
+		body := bytes.NewBufferString(`{"password":"Password123!"}`)
+		resp := s.doRequest(http.MethodDelete, "/me", body, "goodtoken")
+		s.Equal(http.StatusAccepted, resp.StatusCode)
+		s.Contains(s.readBody(resp), "2030-01-31T12:00:00Z")
+	})
+
+	s.Run("delete with wrong password", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.authMock.EXPECT().DeleteAccount(gomock.Any(), userID, "wrong").Return(time.Time{}, services.ErrInvalidCredentials)
+
+		body := bytes.NewBufferString(`{"password":"wrong"}`)
+		resp := s.doRequest(http.MethodDelete, "/me", body, "goodtoken")
+		s.Equal(http.StatusForbidden, resp.StatusCode)
+	})
+
+	s.Run("export", func() {
+		exerciseID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.exportMock.EXPECT().Export(gomock.Any(), userID).Return(&models.DataExport{
+			User:      &models.User{ID: userID, Email: "a@example.com", PasswordHash: "secret-hash"},
+			Profile:   &models.Profile{UserID: userID, Units: models.UnitsMetric, TimeZone: "UTC"},
+			Sessions:  []*models.Session{{ID: uuid.New(), Sets: []models.Set{{ExerciseID: exerciseID, Reps: 5, WeightKG: 100}}}},
+			Records:   []models.PersonalRecord{{ExerciseID: exerciseID, Reps: 5, WeightKG: 100}},
+			Readiness: []*models.ReadinessCheckIn{{Day: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Sleep: 4, Soreness: 2, Stress: 3}},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/me/export", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(resp.Header.Get("Content-Disposition"), "attachment")
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("a@example.com", got["account"].(map[string]any)["email"])
+		s.Len(got["sessions"], 1)
+		s.Len(got["records"], 1)
+		s.Equal([]any{map[string]any{"date": "2025-03-04", "sleep": 4.0, "soreness": 2.0, "stress": 3.0}}, got["readiness"])
+		s.NotContains(got["account"], "password_hash")
+	})
+}
+
+func (s *HandlerSuite) TestOIDCLogin() {
+	userID := uuid.New()
+
+	s.Run("list providers", func() {
+		s.socialMock.EXPECT().Providers().Return([]string{"google"})
+
+		resp := s.doRequest(http.MethodGet, "/auth/oidc", nil, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "google")
+	})
+
+	s.Run("start redirects to provider", func() {
+		s.socialMock.EXPECT().StartLogin(gomock.Any(), "google").Return("https://idp.example.com/authorize?state=x", nil)
+
+		resp := s.doRequest(http.MethodGet, "/auth/oidc/google", nil, "")
+		s.Equal(http.StatusFound, resp.StatusCode)
+		s.Equal("https://idp.example.com/authorize?state=x", resp.Header.Get("Location"))
+	})
+
+	s.Run("start with unknown provider", func() {
+		s.socialMock.EXPECT().StartLogin(gomock.Any(), "nope").Return("", services.ErrUnknownProvider)
+
+		resp := s.doRequest(http.MethodGet, "/auth/oidc/nope", nil, "")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("callback issues token", func() {
+		s.socialMock.EXPECT().CompleteLogin(gomock.Any(), "google", "state1", "code1").Return(&models.User{ID: userID}, "token123", nil)
+
+		resp := s.doRequest(http.MethodGet, "/auth/oidc/google/callback?state=state1&code=code1", nil, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "token123")
+	})
+
+	s.Run("callback with stale state", func() {
+		s.socialMock.EXPECT().CompleteLogin(gomock.Any(), "google", "old", "code1").Return(nil, "", services.ErrInvalidLoginState)
+
+		resp := s.doRequest(http.MethodGet, "/auth/oidc/google/callback?state=old&code=code1", nil, "")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("callback for unlinkable email", func() {
+		s.socialMock.EXPECT().CompleteLogin(gomock.Any(), "google", "state1", "code1").Return(nil, "", services.ErrIdentityConflict)
+
+		resp := s.doRequest(http.MethodGet, "/auth/oidc/google/callback?state=state1&code=code1", nil, "")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("callback after user declined", func() {
+		resp := s.doRequest(http.MethodGet, "/auth/oidc/google/callback?error=access_denied&state=state1", nil, "")
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestProfileEndpoints() {
+	userID := uuid.New()
+
+	s.Run("get me", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		bodyweight := 80.5
+		s.profileMock.EXPECT().GetMe(gomock.Any(), userID).Return(
+			&models.User{ID: userID, Email: "me@example.com"},
+			&models.Profile{UserID: userID, BodyweightKG: &bodyweight, Units: models.UnitsMetric, TimeZone: "UTC"}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/me", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("me@example.com", got["email"])
+		profile := got["profile"].(map[string]any)
+		s.Equal(80.5, profile["bodyweight_kg"])
+		s.Equal("UTC", profile["time_zone"])
+		s.Nil(profile["height_cm"])
+	})
+
+	s.Run("patch me", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		experience := models.ExperienceIntermediate
+		tz := "Europe/Berlin"
+		s.profileMock.EXPECT().UpdateProfile(gomock.Any(), userID, models.ProfileUpdate{Experience: &experience, TimeZone: &tz}).Return(
+			&models.User{ID: userID},
+			&models.Profile{UserID: userID, Experience: &experience, Units: models.UnitsMetric, TimeZone: tz}, nil)
+
+		body := bytes.NewBufferString(`{"experience":"intermediate","time_zone":"Europe/Berlin"}`)
+		resp := s.doRequest(http.MethodPatch, "/me", body, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Contains(s.readBody(resp), "Europe/Berlin")
+	})
+
+	s.Run("patch training days", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		days := []string{"monday", "thursday"}
+		s.profileMock.EXPECT().UpdateProfile(gomock.Any(), userID, models.ProfileUpdate{TrainingDays: &days}).Return(
+			&models.User{ID: userID},
+			&models.Profile{UserID: userID, Units: models.UnitsMetric, TimeZone: "UTC", TrainingDays: days}, nil)
+
+		body := bytes.NewBufferString(`{"training_days":["monday","thursday","monday"]}`)
+		resp := s.doRequest(http.MethodPatch, "/me", body, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal([]any{"monday", "thursday"}, got["profile"].(map[string]any)["training_days"])
+	})
+
+	s.Run("null clears a field", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.profileMock.EXPECT().UpdateProfile(gomock.Any(), userID, models.ProfileUpdate{ClearBodyweight: true, ClearHeight: true}).Return(
+			&models.User{ID: userID},
+			&models.Profile{UserID: userID, Units: models.UnitsMetric, TimeZone: "UTC"}, nil)
+
+		body := bytes.NewBufferString(`{"bodyweight":null,"height_cm":null}`)
+		resp := s.doRequest(http.MethodPatch, "/me", body, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	invalid := map[string]string{
+		"bodyweight out of range": `{"bodyweight_kg":5}`,
+		"unknown experience":      `{"experience":"expert"}`,
+		"unknown units":           `{"units":"stones"}`,
+		"unknown time zone":       `{"time_zone":"Nowhere/City"}`,
+		"unknown training day":    `{"training_days":["mon"]}`,
+		"future birth year":       `{"birth_year":3000}`,
+		"unknown field":           `{"email":"new@example.com"}`,
+	}
+	for name, payload := range invalid {
+		s.Run(name, func() {
+			s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+			resp := s.doRequest(http.MethodPatch, "/me", bytes.NewBufferString(payload), "goodtoken")
+			s.Equal(http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
 func (s *HandlerSuite) TestSessionEndpoints() {
 	userID := uuid.New()
 
@@ -153,7 +690,7 @@ func (s *HandlerSuite) TestSessionEndpoints() {
 	s.Run("create session success", func() {
 		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
 		created := &models.Session{ID: uuid.New(), UserID: userID}
-		s.sessionMock.EXPECT().CreateSession(gomock.Any(), userID, gomock.Nil(), gomock.Nil(), gomock.Nil()).Return(created, nil)
+		s.sessionMock.EXPECT().CreateSession(gomock.Any(), userID, gomock.Nil(), gomock.Nil(), gomock.Nil(), "").Return(created, nil)
 
 		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(`{}`), "goodtoken")
 		s.Equal(http.StatusCreated, resp.StatusCode)
@@ -170,7 +707,8 @@ func (s *HandlerSuite) TestSessionEndpoints() {
 		sessionID := uuid.New()
 		exerciseID := uuid.New()
 		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
-		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, sessionID, exerciseID, 0, 8, 20.0, gomock.Nil()).Return(&models.Set{ID: uuid.New(), SessionID: sessionID, ExerciseID: exerciseID}, nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 8, WeightKG: 20.0}).Return(&models.Set{ID: uuid.New(), SessionID: sessionID, ExerciseID: exerciseID}, nil)
 
 		body := map[string]any{"exercise_id": exerciseID.String(), "set_index": 0, "reps": 8, "weight_kg": 20.0}
 		payload, _ := json.Marshal(body)
@@ -193,6 +731,7 @@ func (s *HandlerSuite) TestSessionEndpoints() {
 		sessionID := uuid.New()
 		exerciseID := uuid.New()
 		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
 
 		body := map[string]any{"exercise_id": exerciseID.String(), "set_index": 0, "reps": 8, "weight_kg": -10.0}
 		payload, _ := json.Marshal(body)
@@ -229,6 +768,7 @@ func (s *HandlerSuite) TestSessionEndpoints() {
 
 	s.Run("list sessions success", func() {
 		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
 		s.sessionMock.EXPECT().ListSessions(gomock.Any(), userID).Return([]*models.Session{}, nil)
 
 		resp := s.doRequest(http.MethodGet, "/sessions", nil, "goodtoken")
@@ -236,25 +776,1153 @@ func (s *HandlerSuite) TestSessionEndpoints() {
 	})
 }
 
-func (s *HandlerSuite) TestPlanEndpoint() {
+func (s *HandlerSuite) TestSessionLifecycle() {
 	userID := uuid.New()
+	sessionID := uuid.New()
+	started := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	finished := started.Add(55 * time.Minute)
 
-	s.Run("unauthorized", func() {
-		resp := s.doRequest(http.MethodGet, "/plan/next", nil, "")
-		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Run("create planned session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().CreateSession(gomock.Any(), userID, gomock.Nil(), gomock.Nil(), gomock.Nil(), models.SessionPlanned).
+			Return(&models.Session{ID: sessionID, UserID: userID, Status: models.SessionPlanned}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(`{"status":"planned"}`), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(sessionID.String(), got["id"])
+		s.Equal("planned", got["status"])
 	})
 
-	s.Run("success", func() {
+	s.Run("create abandoned session", func() {
 		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
-		s.planMock.EXPECT().NextSuggestion(gomock.Any(), userID).Return(&models.PlanSuggestion{ExerciseID: uuid.New(), WeightKG: 20, Reps: 8}, nil)
 
-		resp := s.doRequest(http.MethodGet, "/plan/next", nil, "goodtoken")
+		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(`{"status":"abandoned"}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("start session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().StartSession(gomock.Any(), userID, sessionID).
+			Return(&models.Session{ID: sessionID, Status: models.SessionInProgress, StartedAt: &started}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/start", nil, "goodtoken")
 		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("in_progress", got["status"])
+		s.Nil(got["duration_seconds"])
 	})
-}
+
+	s.Run("start session twice", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().StartSession(gomock.Any(), userID, sessionID).Return(nil, services.ErrSessionState)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/start", nil, "goodtoken")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("start someone else's session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().StartSession(gomock.Any(), userID, sessionID).Return(nil, services.ErrSessionNotFound)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/start", nil, "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("finish session without a body", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().FinishSession(gomock.Any(), userID, sessionID, models.SessionCompleted).
+			Return(&models.Session{ID: sessionID, Status: models.SessionCompleted, StartedAt: &started, FinishedAt: &finished}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/finish", bytes.NewBufferString(""), "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("completed", got["status"])
+		s.Equal(3300.0, got["duration_seconds"])
+	})
+
+	s.Run("abandon session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().FinishSession(gomock.Any(), userID, sessionID, models.SessionAbandoned).
+			Return(&models.Session{ID: sessionID, Status: models.SessionAbandoned, StartedAt: &started, FinishedAt: &finished}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/finish", bytes.NewBufferString(`{"status":"abandoned"}`), "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("finish with a status that does not end a session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/finish", bytes.NewBufferString(`{"status":"planned"}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestSessionTemplates() {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	templateID := uuid.New()
+	exerciseID := uuid.New()
+	rest := 120
+	template := &models.SessionTemplate{
+		ID:   templateID,
+		Name: "Push day",
+		Sets: []models.TemplateSet{{Position: 0, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 60, RestSeconds: &rest}},
+	}
+
+	s.Run("save template", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.templMock.EXPECT().SaveTemplate(gomock.Any(), userID, sessionID, "Push day").Return(template, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/template", bytes.NewBufferString(`{"name":"Push day"}`), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(templateID.String(), got["id"])
+		s.Equal("Push day", got["name"])
+		sets := got["sets"].([]any)
+		s.Require().Len(sets, 1)
+		s.Equal(60.0, sets[0].(map[string]any)["weight_kg"])
+	})
+
+	s.Run("save template without a body", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.templMock.EXPECT().SaveTemplate(gomock.Any(), userID, sessionID, "").Return(template, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/template", bytes.NewBufferString(""), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+	})
+
+	s.Run("save template of a session without sets", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.templMock.EXPECT().SaveTemplate(gomock.Any(), userID, sessionID, "").Return(nil, services.ErrEmptyTemplate)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/template", bytes.NewBufferString(""), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("save template of someone else's session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.templMock.EXPECT().SaveTemplate(gomock.Any(), userID, sessionID, "").Return(nil, services.ErrSessionNotFound)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/template", bytes.NewBufferString(""), "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("list templates", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsImperial)
+		s.templMock.EXPECT().ListTemplates(gomock.Any(), userID).Return([]*models.SessionTemplate{template}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/templates", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got []map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Require().Len(got, 1)
+		set := got[0]["sets"].([]any)[0].(map[string]any)
+		s.Equal("lb", set["unit"])
+	})
+
+	s.Run("create session from template", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.templMock.EXPECT().CreateSessionFromTemplate(gomock.Any(), userID, templateID, gomock.Nil(), gomock.Nil(), gomock.Nil(), "").
+			Return(&models.Session{
+				ID:          sessionID,
+				Status:      models.SessionPlanned,
+				PlannedSets: []models.PlannedSet{{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 6, WeightKG: 60, RestSeconds: &rest}},
+			}, nil)
+
+		body := fmt.Sprintf(`{"template_id":%q}`, templateID)
+		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("planned", got["status"])
+		planned := got["planned_sets"].([]any)
+		s.Require().Len(planned, 1)
+		s.Equal(6.0, planned[0].(map[string]any)["reps"])
+	})
+
+	s.Run("create session from unknown template", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.templMock.EXPECT().CreateSessionFromTemplate(gomock.Any(), userID, templateID, gomock.Nil(), gomock.Nil(), gomock.Nil(), "").
+			Return(nil, services.ErrTemplateNotFound)
+
+		body := fmt.Sprintf(`{"template_id":%q}`, templateID)
+		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("create completed session from template", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		body := fmt.Sprintf(`{"template_id":%q,"status":"completed"}`, templateID)
+		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("create session from malformed template ID", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions", bytes.NewBufferString(`{"template_id":"nope"}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestSetGroups() {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	groupID := uuid.New()
+	pullUp, dip := uuid.New(), uuid.New()
+	rounds, rest := 3, 90
+
+	s.Run("create circuit", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().CreateGroup(gomock.Any(), userID, sessionID, models.GroupCircuit, &rounds, &rest).
+			Return(&models.SetGroup{ID: groupID, SessionID: sessionID, Kind: models.GroupCircuit, Rounds: &rounds, RestSeconds: &rest}, nil)
+
+		body := `{"kind":"circuit","rounds":3,"rest_seconds":90}`
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/groups", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(groupID.String(), got["id"])
+		s.Equal(3.0, got["rounds"])
+	})
+
+	s.Run("create group with unknown kind", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/groups", bytes.NewBufferString(`{"kind":"tabata"}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("create group in someone else's session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().CreateGroup(gomock.Any(), userID, sessionID, models.GroupSuperset, gomock.Nil(), gomock.Nil()).
+			Return(nil, services.ErrSessionNotFound)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/groups", bytes.NewBufferString(`{"kind":"superset"}`), "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("set in a group round", func() {
+		round := 2
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: pullUp, Kind: models.SetKindWorking, Reps: 8, GroupID: &groupID, Round: &round}).
+			Return(&models.Set{ID: uuid.New(), SessionID: sessionID, ExerciseID: pullUp, Reps: 8, GroupID: &groupID, Round: &round}, nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":8,"group_id":%q,"round":2}`, pullUp, groupID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+	})
+
+	s.Run("set in an unknown group", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, gomock.Any()).Return(nil, services.ErrUnknownGroup)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":8,"group_id":%q}`, pullUp, uuid.New())
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("session detail nests grouped sets", func() {
+		one, two := 1, 2
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().GetSession(gomock.Any(), userID, sessionID).Return(&models.Session{
+			ID: sessionID,
+			Sets: []models.Set{
+				{SetIndex: 0, ExerciseID: pullUp, Reps: 5, WeightKG: 20},
+				{SetIndex: 1, ExerciseID: pullUp, Reps: 8, GroupID: &groupID, Round: &two},
+				{SetIndex: 2, ExerciseID: pullUp, Reps: 8, GroupID: &groupID, Round: &one},
+				{SetIndex: 3, ExerciseID: dip, Reps: 10, GroupID: &groupID, Round: &one},
+			},
+			Groups: []models.SetGroup{{ID: groupID, SessionID: sessionID, Kind: models.GroupSuperset, Rounds: &rounds}},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String(), nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got struct {
+			Sets   []map[string]any `json:"sets"`
+			Groups []struct {
+				Kind string           `json:"kind"`
+				Sets []map[string]any `json:"sets"`
+			} `json:"groups"`
+		}
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Len(got.Sets, 1, "only ungrouped sets are listed at the top level")
+		s.Require().Len(got.Groups, 1)
+		s.Equal(models.GroupSuperset, got.Groups[0].Kind)
+		s.Require().Len(got.Groups[0].Sets, 3)
+		var order []float64
+		for _, set := range got.Groups[0].Sets {
+			order = append(order, set["set_index"].(float64))
+		}
+		s.Equal([]float64{2, 3, 1}, order, "ordered by round, then set index")
+	})
+
+	s.Run("session detail not found", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().GetSession(gomock.Any(), userID, sessionID).Return(nil, services.ErrSessionNotFound)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String(), nil, "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestPlanAdherence() {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	exerciseID := uuid.New()
+	adherence := models.Adherence{PlannedSets: 4, CompletedSets: 3, PlannedReps: 20, ActualReps: 15, PlannedLoadKG: 2000, ActualLoadKG: 1500}
+
+	s.Run("plan a set", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddPlannedSet(gomock.Any(), userID, &models.PlannedSet{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100}).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, p *models.PlannedSet) (*models.PlannedSet, error) {
+				p.Position = 2
+				return p, nil
+			})
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"reps":5,"weight_kg":100}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/planned-sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(2.0, got["position"])
+	})
+
+	s.Run("plan a set in a finished session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddPlannedSet(gomock.Any(), userID, gomock.Any()).Return(nil, services.ErrSessionState)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"reps":5}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/planned-sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("plan a set with an unknown kind", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"reps":5,"kind":"negative"}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/planned-sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("session adherence", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.adhereMock.EXPECT().SessionAdherence(gomock.Any(), userID, sessionID).
+			Return(&models.SessionAdherence{SessionID: sessionID, Status: models.SessionCompleted, Adherence: adherence}, nil)
+		s.expectUnits(userID, models.UnitsMetric)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String()+"/adherence", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(3.0, got["completed_sets"])
+		s.Equal(0.75, got["set_ratio"])
+		s.Equal(0.75, got["load_ratio"])
+	})
+
+	s.Run("adherence of someone else's session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.adhereMock.EXPECT().SessionAdherence(gomock.Any(), userID, sessionID).Return(nil, services.ErrSessionNotFound)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String()+"/adherence", nil, "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("weekly adherence", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+		s.adhereMock.EXPECT().WeeklyAdherence(gomock.Any(), userID, 2).Return([]models.WeeklyAdherence{
+			{WeekStart: monday},
+			{WeekStart: monday.AddDate(0, 0, 7), Sessions: 2, Adherence: adherence},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/adherence/weekly?weeks=2", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got []map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Require().Len(got, 2)
+		s.Equal("2025-03-03", got[0]["week_start"])
+		s.Nil(got[0]["set_ratio"], "nothing planned that week")
+		s.Equal(0.75, got[1]["rep_ratio"])
+	})
+
+	s.Run("weekly adherence over too many weeks", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/adherence/weekly?weeks=53", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestCalendar() {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	s.Run("calendar", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		planned := &models.Session{ID: sessionID, Status: models.SessionPlanned, PerformedAt: monday.Add(18 * time.Hour)}
+		s.calMock.EXPECT().Calendar(gomock.Any(), userID, monday, monday.AddDate(0, 0, 1)).Return([]models.CalendarDay{
+			{Date: monday, TrainingDay: true, Missed: true, Sessions: []*models.Session{planned}},
+			{Date: monday.AddDate(0, 0, 1), Sessions: []*models.Session{}},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/calendar?from=2025-03-03&to=2025-03-04", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got []map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Require().Len(got, 2)
+		s.Equal("2025-03-03", got[0]["date"])
+		s.Equal(true, got[0]["training_day"])
+		s.Equal(true, got[0]["missed"])
+		s.Equal(sessionID.String(), got[0]["sessions"].([]any)[0].(map[string]any)["id"])
+		s.Empty(got[1]["sessions"])
+	})
+
+	s.Run("calendar without dates", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/calendar?from=2025-03-03", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("calendar over too long a range", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.calMock.EXPECT().Calendar(gomock.Any(), userID, gomock.Any(), gomock.Any()).Return(nil, services.ErrCalendarRange)
+
+		resp := s.doRequest(http.MethodGet, "/calendar?from=2025-01-01&to=2025-12-31", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("reschedule", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		at := monday.AddDate(0, 0, 2).Add(7 * time.Hour)
+		s.calMock.EXPECT().Reschedule(gomock.Any(), userID, sessionID, at).
+			Return(&models.Session{ID: sessionID, Status: models.SessionPlanned, PerformedAt: at, RescheduledFrom: &monday}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/reschedule", bytes.NewBufferString(`{"performed_at":"2025-03-05T07:00:00Z"}`), "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("2025-03-03T00:00:00Z", got["rescheduled_from"])
+	})
+
+	s.Run("reschedule without a time", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/reschedule", bytes.NewBufferString(`{}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("reschedule a started session", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.calMock.EXPECT().Reschedule(gomock.Any(), userID, sessionID, gomock.Any()).Return(nil, services.ErrSessionState)
+
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/reschedule", bytes.NewBufferString(`{"performed_at":"2025-03-05T07:00:00Z"}`), "goodtoken")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestCalendarFeed() {
+	userID := uuid.New()
+
+	s.Run("issue feed token", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.calMock.EXPECT().IssueFeedToken(gomock.Any(), userID).Return("secret", nil)
+
+		resp := s.doRequest(http.MethodPost, "/me/calendar-feed", nil, "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("secret", got["token"])
+		s.Equal("/calendar.ics?token=secret", got["path"])
+	})
+
+	s.Run("revoke feed token", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.calMock.EXPECT().RevokeFeedToken(gomock.Any(), userID).Return(nil)
+
+		resp := s.doRequest(http.MethodDelete, "/me/calendar-feed", nil, "goodtoken")
+		s.Equal(http.StatusNoContent, resp.StatusCode)
+	})
+
+	s.Run("revoke without a feed", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.calMock.EXPECT().RevokeFeedToken(gomock.Any(), userID).Return(services.ErrCalendarFeedNotFound)
+
+		resp := s.doRequest(http.MethodDelete, "/me/calendar-feed", nil, "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("feed", func() {
+		sessionID, squat := uuid.New(), uuid.New()
+		upper := "upper"
+		hold := 30
+		at := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+		s.calMock.EXPECT().Feed(gomock.Any(), "secret").Return(&models.CalendarFeed{
+			Profile: &models.Profile{UserID: userID, Units: models.UnitsMetric},
+			Sessions: []*models.Session{{
+				ID:          sessionID,
+				Status:      models.SessionPlanned,
+				PerformedAt: at,
+				SessionType: &upper,
+				PlannedSets: []models.PlannedSet{
+					{ExerciseID: squat, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20},
+					{ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+					{ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+					{ExerciseID: uuid.New(), Kind: models.SetKindWorking, DurationSeconds: &hold},
+				},
+			}},
+			Exercises: map[uuid.UUID]models.Exercise{squat: {ID: squat, Name: "Squat"}},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/calendar.ics?token=secret", nil, "")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("text/calendar; charset=utf-8", resp.Header.Get("Content-Type"))
+		body := strings.ReplaceAll(s.readBody(resp), "\r\n ", "") // unfold long lines
+		s.Contains(body, "BEGIN:VCALENDAR\r\n")
+		s.Contains(body, "UID:"+sessionID.String()+"@kalistheniks\r\n")
+		s.Contains(body, "DTSTART:20250303T180000Z\r\n")
+		s.Contains(body, "SUMMARY:Workout: upper\r\n")
+		s.Contains(body, `DESCRIPTION:Squat: 1 × 10 @ 20 kg (warmup)\nSquat: 2 × 5 @ 100 kg\nUnknown exercise: 1 × 30 s`)
+	})
+
+	s.Run("unknown feed token", func() {
+		s.calMock.EXPECT().Feed(gomock.Any(), "guess").Return(nil, services.ErrCalendarFeedNotFound)
+
+		resp := s.doRequest(http.MethodGet, "/calendar.ics?token=guess", nil, "")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestConsistencyStats() {
+	userID := uuid.New()
+
+	s.Run("stats", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		avg := 3000
+		s.statsMock.EXPECT().Consistency(gomock.Any(), userID, 2).Return(&models.ConsistencyStats{
+			CurrentStreakWeeks: 3,
+			LongestStreakWeeks: 5,
+			TotalSessions:      20,
+			Weeks: []models.WeeklySessions{
+				{WeekStart: time.Date(2025, 2, 24, 0, 0, 0, 0, time.UTC), Sessions: 2},
+				{WeekStart: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), Sessions: 1},
+			},
+			SessionsPerWeek:        1.5,
+			AverageDurationSeconds: &avg,
+			SessionsByWeekday:      map[time.Weekday]int{time.Monday: 2, time.Thursday: 1, time.Sunday: 0},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/stats/consistency?weeks=2", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(3.0, got["current_streak_weeks"])
+		s.Equal(5.0, got["longest_streak_weeks"])
+		s.Equal(1.5, got["sessions_per_week"])
+		s.Equal(3000.0, got["average_duration_seconds"])
+		s.Equal("2025-02-24", got["weeks"].([]any)[0].(map[string]any)["week_start"])
+		s.Equal(map[string]any{"monday": 2.0, "thursday": 1.0, "sunday": 0.0}, got["sessions_by_weekday"])
+	})
+
+	s.Run("default weeks", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.statsMock.EXPECT().Consistency(gomock.Any(), userID, 12).Return(&models.ConsistencyStats{}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/stats/consistency", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("too many weeks", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/stats/consistency?weeks=53", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestExerciseHistory() {
+	userID, exerciseID := uuid.New(), uuid.New()
+
+	s.Run("history", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsImperial)
+		e1rm, rpe := 120.0, 8.5
+		s.histMock.EXPECT().ExerciseHistory(gomock.Any(), userID, exerciseID, models.HistoryWindowWeek, []string{models.SetKindWorking, models.SetKindAMRAP}).
+			Return(&models.ExerciseHistory{
+				ExerciseID:  exerciseID,
+				Measurement: models.MeasurementReps,
+				Window:      models.HistoryWindowWeek,
+				Points: []models.ExerciseHistoryPoint{{
+					Start:         time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+					Sessions:      2,
+					Sets:          6,
+					BestSet:       models.Set{Kind: models.SetKindWorking, Reps: 3, WeightKG: 100},
+					BestSetLoadKG: 100,
+					TotalReps:     24,
+					VolumeKG:      2000,
+					TopE1RMKG:     &e1rm,
+					AverageRPE:    &rpe,
+				}},
+			}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/exercises/"+exerciseID.String()+"/history?window=week&kinds=working,amrap", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("week", got["window"])
+		s.Equal("lb", got["unit"])
+		point := got["points"].([]any)[0].(map[string]any)
+		s.Equal(2.0, point["sessions"])
+		s.Equal(2000.0, point["volume_kg"])
+		s.Equal(264.6, point["top_e1rm"])
+		s.Equal(8.5, point["average_rpe"])
+		s.Nil(point["session_id"])
+		s.Equal(220.5, point["best_set"].(map[string]any)["load"])
+	})
+
+	s.Run("unknown window", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/exercises/"+exerciseID.String()+"/history?window=year", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("unknown kind", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/exercises/"+exerciseID.String()+"/history?kinds=heavy", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("unknown exercise", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.histMock.EXPECT().ExerciseHistory(gomock.Any(), userID, exerciseID, models.HistoryWindowSession, nil).Return(nil, services.ErrUnknownExercise)
+
+		resp := s.doRequest(http.MethodGet, "/exercises/"+exerciseID.String()+"/history", nil, "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestDashboard() {
+	userID, squatID := uuid.New(), uuid.New()
+
+	s.Run("dashboard", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsImperial)
+		last := &models.Session{ID: uuid.New(), Status: models.SessionCompleted, Sets: []models.Set{{ExerciseID: squatID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100}}}
+		s.dashMock.EXPECT().Dashboard(gomock.Any(), userID).Return(&models.Dashboard{
+			LastSession:        last,
+			Suggestion:         &models.PlanSuggestion{ExerciseID: squatID, WeightKG: 102.5, Weight: 225, Unit: "lb", Reps: 5},
+			ThisWeek:           models.WeeklyVolume{WeekStart: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), Sessions: 2, Sets: 10, Reps: 50, VolumeKG: 5500},
+			LastWeek:           models.WeeklyVolume{WeekStart: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), Sessions: 2, Sets: 10, Reps: 50, VolumeKG: 5000},
+			RecentRecords:      []models.PersonalRecord{{ExerciseID: squatID, WeightKG: 100, Reps: 5, SessionID: last.ID}},
+			CurrentStreakWeeks: 4,
+			LongestStreakWeeks: 9,
+			Exercises:          map[uuid.UUID]models.Exercise{squatID: {ID: squatID, Name: "Squat"}},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/dashboard", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("lb", got["unit"])
+		s.Equal(last.ID.String(), got["last_session"].(map[string]any)["id"])
+		s.Nil(got["next_session"])
+		s.Equal(225.0, got["next_workout"].(map[string]any)["weight"])
+		s.Equal("2025-03-10", got["this_week"].(map[string]any)["week_start"])
+		s.Equal(5500.0, got["this_week"].(map[string]any)["volume_kg"])
+		s.Equal(10.0, got["volume_change_percent"])
+		record := got["recent_records"].([]any)[0].(map[string]any)
+		s.Equal("Squat", record["exercise_name"])
+		s.Equal(220.5, record["weight"])
+		s.Equal(4.0, got["current_streak_weeks"])
+		s.Equal(9.0, got["longest_streak_weeks"])
+	})
+
+	s.Run("no volume last week", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.dashMock.EXPECT().Dashboard(gomock.Any(), userID).Return(&models.Dashboard{ThisWeek: models.WeeklyVolume{VolumeKG: 1000}}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/dashboard", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Nil(got["volume_change_percent"])
+		s.Nil(got["last_session"])
+		s.Empty(got["recent_records"])
+	})
+
+	s.Run("failure", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.dashMock.EXPECT().Dashboard(gomock.Any(), userID).Return(nil, errors.New("boom"))
+
+		resp := s.doRequest(http.MethodGet, "/dashboard", nil, "goodtoken")
+		s.Equal(http.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestWorkload() {
+	userID := uuid.New()
+
+	s.Run("check in", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.loadMock.EXPECT().CheckIn(gomock.Any(), userID, 4, 2, 3).Return(&models.ReadinessCheckIn{
+			Day: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Sleep: 4, Soreness: 2, Stress: 3,
+		}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/readiness", bytes.NewBufferString(`{"sleep":4,"soreness":2,"stress":3}`), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("2025-03-04", got["date"])
+	})
+
+	s.Run("missing rating", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodPost, "/readiness", bytes.NewBufferString(`{"sleep":4,"soreness":2}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("rating out of range", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.loadMock.EXPECT().CheckIn(gomock.Any(), userID, 9, 2, 3).Return(nil, services.ErrInvalidCheckIn)
+
+		resp := s.doRequest(http.MethodPost, "/readiness", bytes.NewBufferString(`{"sleep":9,"soreness":2,"stress":3}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("workload", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		ratio, score := 1.62, 0.25
+		s.loadMock.EXPECT().Workload(gomock.Any(), userID).Return(&models.Workload{
+			AcuteLoad:      270,
+			ChronicLoad:    166.5,
+			Ratio:          &ratio,
+			Readiness:      &models.ReadinessCheckIn{Day: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Sleep: 2, Soreness: 4, Stress: 4},
+			ReadinessScore: &score,
+			VolumeScale:    0.6,
+			Notes:          "Training load spiked this week; volume cut to recover.",
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/workload", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(1.62, got["ratio"])
+		s.Equal(0.6, got["volume_scale"])
+		s.Equal(0.25, got["readiness"].(map[string]any)["score"])
+	})
+}
+
+func (s *HandlerSuite) TestBodyweightEndpoints() {
+	userID := uuid.New()
+
+	s.Run("log bodyweight", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.bodyMock.EXPECT().LogBodyweight(gomock.Any(), userID, 81.5, gomock.Nil()).
+			Return(&models.BodyweightEntry{ID: uuid.New(), UserID: userID, WeightKG: 81.5, MeasuredAt: time.Now()}, nil)
+
+		resp := s.doRequest(http.MethodPost, "/me/bodyweight", bytes.NewBufferString(`{"weight_kg":81.5}`), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		s.Contains(s.readBody(resp), `"weight_kg":81.5`)
+	})
+
+	s.Run("log bodyweight out of range", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+
+		resp := s.doRequest(http.MethodPost, "/me/bodyweight", bytes.NewBufferString(`{"weight_kg":5}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("list bodyweight", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.bodyMock.EXPECT().ListBodyweight(gomock.Any(), userID).Return([]*models.BodyweightEntry{}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/me/bodyweight", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.JSONEq("[]", s.readBody(resp))
+	})
+
+	s.Run("session metrics", func() {
+		sessionID := uuid.New()
+		bodyweight := 80.0
+		relative := 0.64
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.bodyMock.EXPECT().SessionMetrics(gomock.Any(), userID, sessionID, gomock.Nil()).Return(&models.SessionMetrics{
+			SessionID:    sessionID,
+			BodyweightKG: &bodyweight,
+			TonnageKG:    512,
+			Sets:         []models.SetLoad{{SetID: uuid.New(), Reps: 10, EffectiveKG: 51.2, Relative: &relative}},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String()+"/metrics", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(512.0, got["tonnage_kg"])
+		s.Equal(51.2, got["sets"].([]any)[0].(map[string]any)["effective_load_kg"])
+	})
+
+	s.Run("session metrics for chosen set kinds", func() {
+		sessionID := uuid.New()
+		for query, kinds := range map[string][]string{
+			"kinds=all":           {models.SetKindWarmup, models.SetKindWorking, models.SetKindDropset, models.SetKindAMRAP, models.SetKindBackoff, models.SetKindFailure},
+			"kinds=working,amrap": {models.SetKindWorking, models.SetKindAMRAP},
+		} {
+			s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+			s.expectUnits(userID, models.UnitsMetric)
+			s.bodyMock.EXPECT().SessionMetrics(gomock.Any(), userID, sessionID, kinds).Return(&models.SessionMetrics{SessionID: sessionID}, nil)
+
+			resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String()+"/metrics?"+query, nil, "goodtoken")
+			s.Equal(http.StatusOK, resp.StatusCode, query)
+		}
+	})
+
+	s.Run("session metrics with unknown set kind", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+uuid.New().String()+"/metrics?kinds=working,cooldown", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("session metrics not found", func() {
+		sessionID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.bodyMock.EXPECT().SessionMetrics(gomock.Any(), userID, sessionID, gomock.Nil()).Return(nil, services.ErrSessionNotFound)
+
+		resp := s.doRequest(http.MethodGet, "/sessions/"+sessionID.String()+"/metrics", nil, "goodtoken")
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("bodyweight set with no added load", func() {
+		sessionID := uuid.New()
+		exerciseID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 12}).Return(&models.Set{ID: uuid.New()}, nil)
+
+		body := map[string]any{"exercise_id": exerciseID.String(), "set_index": 0, "reps": 12, "weight_kg": 0}
+		payload, _ := json.Marshal(body)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBuffer(payload), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+	})
+
+	s.Run("hold set records duration", func() {
+		sessionID := uuid.New()
+		exerciseID := uuid.New()
+		seconds := 45
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWorking, DurationSeconds: &seconds}).
+			Return(&models.Set{ID: uuid.New(), SessionID: sessionID, ExerciseID: exerciseID, DurationSeconds: &seconds}, nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"duration_seconds":45}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(45.0, got["duration_seconds"])
+		s.Nil(got["distance_m"])
+	})
+
+	s.Run("warm-up set", func() {
+		sessionID := uuid.New()
+		exerciseID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20}).
+			Return(&models.Set{ID: uuid.New(), Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20}, nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"kind":"warmup","reps":10,"weight_kg":20}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("warmup", got["kind"])
+	})
+
+	s.Run("set with completion time and rest", func() {
+		sessionID := uuid.New()
+		exerciseID := uuid.New()
+		completed := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+		rest := 90
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, CompletedAt: completed, RestSeconds: &rest}).
+			Return(&models.Set{ID: uuid.New(), Reps: 5, CompletedAt: completed, RestSeconds: &rest}, nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":5,"completed_at":"2025-03-01T10:30:00+01:00","rest_seconds":90}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal("2025-03-01T09:30:00Z", got["completed_at"])
+		s.Equal(90.0, got["rest_seconds"])
+	})
+
+	s.Run("set with out of range rest", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":5,"rest_seconds":-1}`, uuid.New())
+		resp := s.doRequest(http.MethodPost, "/sessions/"+uuid.New().String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("set with unknown kind", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"kind":"cooldown","reps":10}`, uuid.New())
+		resp := s.doRequest(http.MethodPost, "/sessions/"+uuid.New().String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("set missing the exercise's measurement", func() {
+		sessionID := uuid.New()
+		exerciseID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, gomock.Any()).
+			Return(nil, fmt.Errorf("%w: hold sets need duration_seconds", services.ErrInvalidSet))
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":10}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+		s.Contains(s.readBody(resp), "duration_seconds")
+	})
+
+	s.Run("set for unknown exercise", func() {
+		sessionID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, gomock.Any()).Return(nil, services.ErrUnknownExercise)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":10}`, uuid.New())
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("set in a planned session", func() {
+		sessionID := uuid.New()
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, gomock.Any()).Return(nil, services.ErrSessionState)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":10}`, uuid.New())
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("set with out of range duration and distance", func() {
+		sessionID := uuid.New()
+		exerciseID := uuid.New()
+		for _, field := range []string{`"duration_seconds":0`, `"distance_m":-5`} {
+			s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+			body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,%s}`, exerciseID, field)
+			resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+			s.Equal(http.StatusBadRequest, resp.StatusCode, field)
+		}
+	})
+}
+
+func (s *HandlerSuite) TestUnitConversion() {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	exerciseID := uuid.New()
+
+	s.Run("set in pounds is stored in kilograms", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsImperial)
+		s.sessionMock.EXPECT().AddSet(gomock.Any(), userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 61.23}).
+			Return(&models.Set{ID: uuid.New(), SessionID: sessionID, ExerciseID: exerciseID, Reps: 5, WeightKG: 61.23}, nil)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":5,"weight":135}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusCreated, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(135.0, got["weight"])
+		s.Equal("lb", got["unit"])
+		s.Equal(61.23, got["weight_kg"])
+	})
+
+	s.Run("pound range is checked in pounds", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsImperial)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":5,"weight":2500}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+		s.Contains(s.readBody(resp), "2204.6")
+	})
+
+	s.Run("weight and weight_kg together are rejected", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+
+		body := fmt.Sprintf(`{"exercise_id":%q,"set_index":0,"reps":5,"weight":60,"weight_kg":60}`, exerciseID)
+		resp := s.doRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/sets", bytes.NewBufferString(body), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("sessions list weights in pounds", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.sessionMock.EXPECT().ListSessions(gomock.Any(), userID).Return([]*models.Session{
+			{ID: sessionID, UserID: userID, Sets: []models.Set{{ExerciseID: exerciseID, Reps: 5, WeightKG: 100}}},
+		}, nil)
+		s.expectUnits(userID, models.UnitsImperial)
+
+		resp := s.doRequest(http.MethodGet, "/sessions", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got []map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		set := got[0]["sets"].([]any)[0].(map[string]any)
+		s.Equal(220.5, set["weight"])
+		s.Equal(100.0, set["weight_kg"])
+	})
+
+	s.Run("profile bodyweight follows the new unit system", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		units := models.UnitsImperial
+		bodyweightKG := 81.65
+		s.profileMock.EXPECT().UpdateProfile(gomock.Any(), userID, models.ProfileUpdate{BodyweightKG: &bodyweightKG, Units: &units}).
+			Return(&models.User{ID: userID}, &models.Profile{UserID: userID, BodyweightKG: &bodyweightKG, Units: units}, nil)
+
+		resp := s.doRequest(http.MethodPatch, "/me", bytes.NewBufferString(`{"bodyweight":180,"units":"imperial"}`), "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		profile := got["profile"].(map[string]any)
+		s.Equal(180.0, profile["bodyweight"])
+		s.Equal("lb", profile["unit"])
+	})
+}
+
+func (s *HandlerSuite) TestEquipmentEndpoints() {
+	userID := uuid.New()
+
+	s.Run("get equipment", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.equipMock.EXPECT().GetEquipment(gomock.Any(), userID).
+			Return(&models.Equipment{UserID: userID, BarKG: 20, PlatesKG: []float64{20, 10}, DumbbellsKG: []float64{}}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/me/equipment", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(20.0, got["bar_weight"])
+		s.Len(got["plates"], 2)
+	})
+
+	s.Run("update equipment in pounds", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsImperial)
+		want := &models.Equipment{UserID: userID, BarKG: 20.41, PlatesKG: []float64{20.41, 1.13}, DumbbellsKG: []float64{}, HasRings: true}
+		s.equipMock.EXPECT().UpdateEquipment(gomock.Any(), want).Return(want, nil)
+
+		body := bytes.NewBufferString(`{"bar_weight":45,"plates":[45,2.5],"has_rings":true}`)
+		resp := s.doRequest(http.MethodPut, "/me/equipment", body, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(45.0, got["bar_weight"])
+		s.Equal([]any{45.0, 2.5}, got["plates"])
+		s.Equal("lb", got["unit"])
+	})
+
+	s.Run("update equipment without bar", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+
+		resp := s.doRequest(http.MethodPut, "/me/equipment", bytes.NewBufferString(`{"plates":[20]}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("update equipment with impossible plate", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+
+		resp := s.doRequest(http.MethodPut, "/me/equipment", bytes.NewBufferString(`{"bar_weight":20,"plates":[200]}`), "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("plate calculator", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.expectUnits(userID, models.UnitsMetric)
+		s.equipMock.EXPECT().PlateLoading(gomock.Any(), userID, 83.0).Return(&models.PlateLoading{
+			TargetKG: 83, AchievedKG: 82.5, BarKG: 20, PerSideKG: []float64{25, 5, 1.25},
+		}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/tools/plates?weight=83", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&got))
+		s.Equal(82.5, got["weight"])
+		s.Equal(false, got["exact"])
+		s.Equal([]any{25.0, 5.0, 1.25}, got["per_side"])
+	})
+
+	s.Run("plate calculator without weight", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+
+		resp := s.doRequest(http.MethodGet, "/tools/plates", nil, "goodtoken")
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func (s *HandlerSuite) TestPlanEndpoint() {
+	userID := uuid.New()
+
+	s.Run("unauthorized", func() {
+		resp := s.doRequest(http.MethodGet, "/plan/next", nil, "")
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	s.Run("success", func() {
+		s.authMock.EXPECT().VerifyToken(gomock.Any(), "goodtoken").Return(userID.String(), nil)
+		s.planMock.EXPECT().NextSuggestion(gomock.Any(), userID).Return(&models.PlanSuggestion{ExerciseID: uuid.New(), WeightKG: 20, Reps: 8}, nil)
+
+		resp := s.doRequest(http.MethodGet, "/plan/next", nil, "goodtoken")
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+}
 
 // helpers
 
+func (s *HandlerSuite) readBody(resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return string(body)
+}
+
 func (s *HandlerSuite) doRequest(method, path string, body *bytes.Buffer, token string) *http.Response {
 	var reader io.Reader
 	if body != nil {
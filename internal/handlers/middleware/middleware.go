@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/alexanderramin/kalistheniks/internal/config"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/contracts"
 	"github.com/alexanderramin/kalistheniks/internal/handlers/response"
 	"github.com/google/uuid"
@@ -39,6 +40,40 @@ func (m *Auth) RequireAuth(next http.Handler) http.Handler {
 	})
 }
 
+// RequireVerifiedEmail restricts unverified accounts according to policy. It must run after RequireAuth.
+func (m *Auth) RequireVerifiedEmail(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if policy == "" || policy == config.VerificationPolicyAllow {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if policy == config.VerificationPolicyReadOnly && isReadOnlyMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID, ok := CurrentUserID(r)
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			verified, err := m.auth.IsEmailVerified(r.Context(), userID)
+			if err != nil {
+				response.Error(w, http.StatusInternalServerError, "failed to check email verification")
+				return
+			}
+			if !verified {
+				response.Error(w, http.StatusForbidden, "email not verified")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
 // CurrentUserID extracts the authenticated user ID from the request context.
 func CurrentUserID(r *http.Request) (uuid.UUID, bool) {
 	id, ok := r.Context().Value(userIDContextKey).(string)
@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/alexanderramin/kalistheniks/internal/models"
 	"github.com/google/uuid"
@@ -15,7 +18,8 @@ import (
 
 // mockAuthService is a mock implementation for testing
 type mockAuthService struct {
-	verifyTokenFunc func(ctx context.Context, token string) (string, error)
+	verifyTokenFunc     func(ctx context.Context, token string) (string, error)
+	isEmailVerifiedFunc func(ctx context.Context, userID uuid.UUID) (bool, error)
 }
 
 func (m *mockAuthService) VerifyToken(ctx context.Context, token string) (string, error) {
@@ -33,6 +37,61 @@ func (m *mockAuthService) Login(ctx context.Context, email, password string) (*m
 	return nil, "", errors.New("not implemented")
 }
 
+func (m *mockAuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) VerifyEmail(ctx context.Context, verifyToken string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if m.isEmailVerifiedFunc != nil {
+		return m.isEmailVerifiedFunc(ctx, userID)
+	}
+	return false, errors.New("not implemented")
+}
+
+func (m *mockAuthService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (string, string, error) {
+	return "", "", errors.New("not implemented")
+}
+
+func (m *mockAuthService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAuthService) DisableTOTP(ctx context.Context, userID uuid.UUID, password, code string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) VerifyMFA(ctx context.Context, challenge, code string) (*models.User, string, error) {
+	return nil, "", errors.New("not implemented")
+}
+
+func (m *mockAuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (m *mockAuthService) RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) ConfirmEmailChange(ctx context.Context, changeToken string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockAuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	return time.Time{}, errors.New("not implemented")
+}
+
 func TestRequireAuth(t *testing.T) {
 	userID := uuid.New().String()
 
@@ -119,6 +178,87 @@ func TestRequireAuth(t *testing.T) {
 	}
 }
 
+func TestRequireVerifiedEmail(t *testing.T) {
+	userID := uuid.New()
+	verified := func(ctx context.Context, id uuid.UUID) (bool, error) { return true, nil }
+	unverified := func(ctx context.Context, id uuid.UUID) (bool, error) { return false, nil }
+
+	tests := []struct {
+		name           string
+		policy         string
+		method         string
+		verifiedFunc   func(ctx context.Context, userID uuid.UUID) (bool, error)
+		expectedStatus int
+	}{
+		{
+			name:           "allow policy skips check",
+			policy:         "allow",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "empty policy skips check",
+			policy:         "",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "read only policy allows reads",
+			policy:         "read_only",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "read only policy blocks unverified writes",
+			policy:         "read_only",
+			method:         http.MethodPost,
+			verifiedFunc:   unverified,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "block policy blocks unverified reads",
+			policy:         "block",
+			method:         http.MethodGet,
+			verifiedFunc:   unverified,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "block policy allows verified",
+			policy:         "block",
+			method:         http.MethodPost,
+			verifiedFunc:   verified,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "lookup error",
+			policy: "block",
+			method: http.MethodGet,
+			verifiedFunc: func(ctx context.Context, id uuid.UUID) (bool, error) {
+				return false, errors.New("db error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware := NewAuth(&mockAuthService{isEmailVerifiedFunc: tt.verifiedFunc})
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			handler := middleware.RequireVerifiedEmail(tt.policy)(next)
+
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			req = req.WithContext(context.WithValue(req.Context(), userIDContextKey, userID.String()))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
 func TestCurrentUserID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -245,3 +385,20 @@ func TestCurrentUserIDFromRequest(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, userID, extractedID)
 }
+
+func TestLoggerMasksTokens(t *testing.T) {
+	var logged bytes.Buffer
+	var seen string
+	handler := requestLogger(log.New(&logged, "", 0), []string{"token"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.URL.Query().Get("token")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar.ics?token=s3cret&x=1", nil))
+	assert.Equal(t, "s3cret", seen, "the handler still gets the token")
+	assert.NotContains(t, logged.String(), "s3cret")
+	assert.Contains(t, logged.String(), "/calendar.ics?token=REDACTED&x=1")
+
+	logged.Reset()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar?from=2025-03-01", nil))
+	assert.Contains(t, logged.String(), "/calendar?from=2025-03-01")
+}
@@ -37,6 +37,110 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, currentPassword, newPassword)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthServiceMockRecorder) ChangePassword(ctx, userID, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthService)(nil).ChangePassword), ctx, userID, currentPassword, newPassword)
+}
+
+// ConfirmEmailChange mocks base method.
+func (m *MockAuthService) ConfirmEmailChange(ctx context.Context, changeToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", ctx, changeToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockAuthServiceMockRecorder) ConfirmEmailChange(ctx, changeToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockAuthService)(nil).ConfirmEmailChange), ctx, changeToken)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockAuthService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockAuthServiceMockRecorder) ConfirmTOTP(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockAuthService)(nil).ConfirmTOTP), ctx, userID, code)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, userID, password)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAuthServiceMockRecorder) DeleteAccount(ctx, userID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAuthService)(nil).DeleteAccount), ctx, userID, password)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockAuthService) DisableTOTP(ctx context.Context, userID uuid.UUID, password, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, userID, password, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockAuthServiceMockRecorder) DisableTOTP(ctx, userID, password, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockAuthService)(nil).DisableTOTP), ctx, userID, password, code)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockAuthService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockAuthServiceMockRecorder) EnrollTOTP(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockAuthService)(nil).EnrollTOTP), ctx, userID)
+}
+
+// IsEmailVerified mocks base method.
+func (m *MockAuthService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEmailVerified", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEmailVerified indicates an expected call of IsEmailVerified.
+func (mr *MockAuthServiceMockRecorder) IsEmailVerified(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEmailVerified", reflect.TypeOf((*MockAuthService)(nil).IsEmailVerified), ctx, userID)
+}
+
 // Login mocks base method.
 func (m *MockAuthService) Login(ctx context.Context, email, password string) (*models.User, string, error) {
 	m.ctrl.T.Helper()
@@ -53,6 +157,62 @@ func (mr *MockAuthServiceMockRecorder) Login(ctx, email, password interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), ctx, email, password)
 }
 
+// RequestEmailChange mocks base method.
+func (m *MockAuthService) RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmailChange", ctx, userID, password, newEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmailChange indicates an expected call of RequestEmailChange.
+func (mr *MockAuthServiceMockRecorder) RequestEmailChange(ctx, userID, password, newEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailChange", reflect.TypeOf((*MockAuthService)(nil).RequestEmailChange), ctx, userID, password, newEmail)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockAuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockAuthServiceMockRecorder) RequestPasswordReset(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockAuthService)(nil).RequestPasswordReset), ctx, email)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAuthService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAuthServiceMockRecorder) ResendVerification(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAuthService)(nil).ResendVerification), ctx, userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, resetToken, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthServiceMockRecorder) ResetPassword(ctx, resetToken, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthService)(nil).ResetPassword), ctx, resetToken, newPassword)
+}
+
 // Signup mocks base method.
 func (m *MockAuthService) Signup(ctx context.Context, email, password string) (*models.User, string, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +229,36 @@ func (mr *MockAuthServiceMockRecorder) Signup(ctx, email, password interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockAuthService)(nil).Signup), ctx, email, password)
 }
 
+// VerifyEmail mocks base method.
+func (m *MockAuthService) VerifyEmail(ctx context.Context, verifyToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, verifyToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthServiceMockRecorder) VerifyEmail(ctx, verifyToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthService)(nil).VerifyEmail), ctx, verifyToken)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockAuthService) VerifyMFA(ctx context.Context, challenge, code string) (*models.User, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", ctx, challenge, code)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockAuthServiceMockRecorder) VerifyMFA(ctx, challenge, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockAuthService)(nil).VerifyMFA), ctx, challenge, code)
+}
+
 // VerifyToken mocks base method.
 func (m *MockAuthService) VerifyToken(ctx context.Context, token string) (string, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +274,318 @@ func (mr *MockAuthServiceMockRecorder) VerifyToken(ctx, token interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockAuthService)(nil).VerifyToken), ctx, token)
 }
 
+// MockSocialLoginService is a mock of SocialLoginService interface.
+type MockSocialLoginService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSocialLoginServiceMockRecorder
+}
+
+// MockSocialLoginServiceMockRecorder is the mock recorder for MockSocialLoginService.
+type MockSocialLoginServiceMockRecorder struct {
+	mock *MockSocialLoginService
+}
+
+// NewMockSocialLoginService creates a new mock instance.
+func NewMockSocialLoginService(ctrl *gomock.Controller) *MockSocialLoginService {
+	mock := &MockSocialLoginService{ctrl: ctrl}
+	mock.recorder = &MockSocialLoginServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSocialLoginService) EXPECT() *MockSocialLoginServiceMockRecorder {
+	return m.recorder
+}
+
+// CompleteLogin mocks base method.
+func (m *MockSocialLoginService) CompleteLogin(ctx context.Context, provider, state, code string) (*models.User, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", ctx, provider, state, code)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockSocialLoginServiceMockRecorder) CompleteLogin(ctx, provider, state, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockSocialLoginService)(nil).CompleteLogin), ctx, provider, state, code)
+}
+
+// Providers mocks base method.
+func (m *MockSocialLoginService) Providers() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Providers")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Providers indicates an expected call of Providers.
+func (mr *MockSocialLoginServiceMockRecorder) Providers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Providers", reflect.TypeOf((*MockSocialLoginService)(nil).Providers))
+}
+
+// StartLogin mocks base method.
+func (m *MockSocialLoginService) StartLogin(ctx context.Context, provider string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartLogin", ctx, provider)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartLogin indicates an expected call of StartLogin.
+func (mr *MockSocialLoginServiceMockRecorder) StartLogin(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartLogin", reflect.TypeOf((*MockSocialLoginService)(nil).StartLogin), ctx, provider)
+}
+
+// MockProfileService is a mock of ProfileService interface.
+type MockProfileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileServiceMockRecorder
+}
+
+// MockProfileServiceMockRecorder is the mock recorder for MockProfileService.
+type MockProfileServiceMockRecorder struct {
+	mock *MockProfileService
+}
+
+// NewMockProfileService creates a new mock instance.
+func NewMockProfileService(ctrl *gomock.Controller) *MockProfileService {
+	mock := &MockProfileService{ctrl: ctrl}
+	mock.recorder = &MockProfileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileService) EXPECT() *MockProfileServiceMockRecorder {
+	return m.recorder
+}
+
+// GetMe mocks base method.
+func (m *MockProfileService) GetMe(ctx context.Context, userID uuid.UUID) (*models.User, *models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMe", ctx, userID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(*models.Profile)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMe indicates an expected call of GetMe.
+func (mr *MockProfileServiceMockRecorder) GetMe(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMe", reflect.TypeOf((*MockProfileService)(nil).GetMe), ctx, userID)
+}
+
+// GetProfile mocks base method.
+func (m *MockProfileService) GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, userID)
+	ret0, _ := ret[0].(*models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfileServiceMockRecorder) GetProfile(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileService)(nil).GetProfile), ctx, userID)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockProfileService) UpdateProfile(ctx context.Context, userID uuid.UUID, update models.ProfileUpdate) (*models.User, *models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, userID, update)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(*models.Profile)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockProfileServiceMockRecorder) UpdateProfile(ctx, userID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileService)(nil).UpdateProfile), ctx, userID, update)
+}
+
+// MockExportService is a mock of ExportService interface.
+type MockExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceMockRecorder
+}
+
+// MockExportServiceMockRecorder is the mock recorder for MockExportService.
+type MockExportServiceMockRecorder struct {
+	mock *MockExportService
+}
+
+// NewMockExportService creates a new mock instance.
+func NewMockExportService(ctrl *gomock.Controller) *MockExportService {
+	mock := &MockExportService{ctrl: ctrl}
+	mock.recorder = &MockExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportService) EXPECT() *MockExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExportService) Export(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID)
+	ret0, _ := ret[0].(*models.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportServiceMockRecorder) Export(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExportService)(nil).Export), ctx, userID)
+}
+
+// MockBodyweightService is a mock of BodyweightService interface.
+type MockBodyweightService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBodyweightServiceMockRecorder
+}
+
+// MockBodyweightServiceMockRecorder is the mock recorder for MockBodyweightService.
+type MockBodyweightServiceMockRecorder struct {
+	mock *MockBodyweightService
+}
+
+// NewMockBodyweightService creates a new mock instance.
+func NewMockBodyweightService(ctrl *gomock.Controller) *MockBodyweightService {
+	mock := &MockBodyweightService{ctrl: ctrl}
+	mock.recorder = &MockBodyweightServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBodyweightService) EXPECT() *MockBodyweightServiceMockRecorder {
+	return m.recorder
+}
+
+// ListBodyweight mocks base method.
+func (m *MockBodyweightService) ListBodyweight(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBodyweight", ctx, userID)
+	ret0, _ := ret[0].([]*models.BodyweightEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBodyweight indicates an expected call of ListBodyweight.
+func (mr *MockBodyweightServiceMockRecorder) ListBodyweight(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBodyweight", reflect.TypeOf((*MockBodyweightService)(nil).ListBodyweight), ctx, userID)
+}
+
+// LogBodyweight mocks base method.
+func (m *MockBodyweightService) LogBodyweight(ctx context.Context, userID uuid.UUID, weightKG float64, measuredAt *time.Time) (*models.BodyweightEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogBodyweight", ctx, userID, weightKG, measuredAt)
+	ret0, _ := ret[0].(*models.BodyweightEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogBodyweight indicates an expected call of LogBodyweight.
+func (mr *MockBodyweightServiceMockRecorder) LogBodyweight(ctx, userID, weightKG, measuredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogBodyweight", reflect.TypeOf((*MockBodyweightService)(nil).LogBodyweight), ctx, userID, weightKG, measuredAt)
+}
+
+// SessionMetrics mocks base method.
+func (m *MockBodyweightService) SessionMetrics(ctx context.Context, userID, sessionID uuid.UUID, kinds []string) (*models.SessionMetrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionMetrics", ctx, userID, sessionID, kinds)
+	ret0, _ := ret[0].(*models.SessionMetrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionMetrics indicates an expected call of SessionMetrics.
+func (mr *MockBodyweightServiceMockRecorder) SessionMetrics(ctx, userID, sessionID, kinds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionMetrics", reflect.TypeOf((*MockBodyweightService)(nil).SessionMetrics), ctx, userID, sessionID, kinds)
+}
+
+// MockEquipmentService is a mock of EquipmentService interface.
+type MockEquipmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEquipmentServiceMockRecorder
+}
+
+// MockEquipmentServiceMockRecorder is the mock recorder for MockEquipmentService.
+type MockEquipmentServiceMockRecorder struct {
+	mock *MockEquipmentService
+}
+
+// NewMockEquipmentService creates a new mock instance.
+func NewMockEquipmentService(ctrl *gomock.Controller) *MockEquipmentService {
+	mock := &MockEquipmentService{ctrl: ctrl}
+	mock.recorder = &MockEquipmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEquipmentService) EXPECT() *MockEquipmentServiceMockRecorder {
+	return m.recorder
+}
+
+// GetEquipment mocks base method.
+func (m *MockEquipmentService) GetEquipment(ctx context.Context, userID uuid.UUID) (*models.Equipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEquipment", ctx, userID)
+	ret0, _ := ret[0].(*models.Equipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEquipment indicates an expected call of GetEquipment.
+func (mr *MockEquipmentServiceMockRecorder) GetEquipment(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEquipment", reflect.TypeOf((*MockEquipmentService)(nil).GetEquipment), ctx, userID)
+}
+
+// PlateLoading mocks base method.
+func (m *MockEquipmentService) PlateLoading(ctx context.Context, userID uuid.UUID, targetKG float64) (*models.PlateLoading, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlateLoading", ctx, userID, targetKG)
+	ret0, _ := ret[0].(*models.PlateLoading)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlateLoading indicates an expected call of PlateLoading.
+func (mr *MockEquipmentServiceMockRecorder) PlateLoading(ctx, userID, targetKG interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlateLoading", reflect.TypeOf((*MockEquipmentService)(nil).PlateLoading), ctx, userID, targetKG)
+}
+
+// UpdateEquipment mocks base method.
+func (m *MockEquipmentService) UpdateEquipment(ctx context.Context, e *models.Equipment) (*models.Equipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEquipment", ctx, e)
+	ret0, _ := ret[0].(*models.Equipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEquipment indicates an expected call of UpdateEquipment.
+func (mr *MockEquipmentServiceMockRecorder) UpdateEquipment(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEquipment", reflect.TypeOf((*MockEquipmentService)(nil).UpdateEquipment), ctx, e)
+}
+
 // MockSessionService is a mock of SessionService interface.
 type MockSessionService struct {
 	ctrl     *gomock.Controller
@@ -107,34 +609,94 @@ func (m *MockSessionService) EXPECT() *MockSessionServiceMockRecorder {
 	return m.recorder
 }
 
+// AddPlannedSet mocks base method.
+func (m *MockSessionService) AddPlannedSet(ctx context.Context, userID uuid.UUID, planned *models.PlannedSet) (*models.PlannedSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPlannedSet", ctx, userID, planned)
+	ret0, _ := ret[0].(*models.PlannedSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPlannedSet indicates an expected call of AddPlannedSet.
+func (mr *MockSessionServiceMockRecorder) AddPlannedSet(ctx, userID, planned interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPlannedSet", reflect.TypeOf((*MockSessionService)(nil).AddPlannedSet), ctx, userID, planned)
+}
+
 // AddSet mocks base method.
-func (m *MockSessionService) AddSet(ctx context.Context, userID, sessionID, exerciseID uuid.UUID, setIndex, reps int, weight float64, rpe *int) (*models.Set, error) {
+func (m *MockSessionService) AddSet(ctx context.Context, userID uuid.UUID, set *models.Set) (*models.Set, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddSet", ctx, userID, sessionID, exerciseID, setIndex, reps, weight, rpe)
+	ret := m.ctrl.Call(m, "AddSet", ctx, userID, set)
 	ret0, _ := ret[0].(*models.Set)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddSet indicates an expected call of AddSet.
-func (mr *MockSessionServiceMockRecorder) AddSet(ctx, userID, sessionID, exerciseID, setIndex, reps, weight, rpe interface{}) *gomock.Call {
+func (mr *MockSessionServiceMockRecorder) AddSet(ctx, userID, set interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSet", reflect.TypeOf((*MockSessionService)(nil).AddSet), ctx, userID, set)
+}
+
+// CreateGroup mocks base method.
+func (m *MockSessionService) CreateGroup(ctx context.Context, userID, sessionID uuid.UUID, kind string, rounds, restSeconds *int) (*models.SetGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, userID, sessionID, kind, rounds, restSeconds)
+	ret0, _ := ret[0].(*models.SetGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockSessionServiceMockRecorder) CreateGroup(ctx, userID, sessionID, kind, rounds, restSeconds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSet", reflect.TypeOf((*MockSessionService)(nil).AddSet), ctx, userID, sessionID, exerciseID, setIndex, reps, weight, rpe)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockSessionService)(nil).CreateGroup), ctx, userID, sessionID, kind, rounds, restSeconds)
 }
 
 // CreateSession mocks base method.
-func (m *MockSessionService) CreateSession(ctx context.Context, userID uuid.UUID, performedAt *time.Time, sessionType, notes *string) (*models.Session, error) {
+func (m *MockSessionService) CreateSession(ctx context.Context, userID uuid.UUID, performedAt *time.Time, sessionType, notes *string, status string) (*models.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", ctx, userID, performedAt, sessionType, notes)
+	ret := m.ctrl.Call(m, "CreateSession", ctx, userID, performedAt, sessionType, notes, status)
 	ret0, _ := ret[0].(*models.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSession indicates an expected call of CreateSession.
-func (mr *MockSessionServiceMockRecorder) CreateSession(ctx, userID, performedAt, sessionType, notes interface{}) *gomock.Call {
+func (mr *MockSessionServiceMockRecorder) CreateSession(ctx, userID, performedAt, sessionType, notes, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionService)(nil).CreateSession), ctx, userID, performedAt, sessionType, notes, status)
+}
+
+// FinishSession mocks base method.
+func (m *MockSessionService) FinishSession(ctx context.Context, userID, sessionID uuid.UUID, status string) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSession", ctx, userID, sessionID, status)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishSession indicates an expected call of FinishSession.
+func (mr *MockSessionServiceMockRecorder) FinishSession(ctx, userID, sessionID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionService)(nil).CreateSession), ctx, userID, performedAt, sessionType, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSession", reflect.TypeOf((*MockSessionService)(nil).FinishSession), ctx, userID, sessionID, status)
+}
+
+// GetSession mocks base method.
+func (m *MockSessionService) GetSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockSessionServiceMockRecorder) GetSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionService)(nil).GetSession), ctx, userID, sessionID)
 }
 
 // ListSessions mocks base method.
@@ -152,6 +714,406 @@ func (mr *MockSessionServiceMockRecorder) ListSessions(ctx, userID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockSessionService)(nil).ListSessions), ctx, userID)
 }
 
+// StartSession mocks base method.
+func (m *MockSessionService) StartSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSession indicates an expected call of StartSession.
+func (mr *MockSessionServiceMockRecorder) StartSession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSession", reflect.TypeOf((*MockSessionService)(nil).StartSession), ctx, userID, sessionID)
+}
+
+// MockTemplateService is a mock of TemplateService interface.
+type MockTemplateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateServiceMockRecorder
+}
+
+// MockTemplateServiceMockRecorder is the mock recorder for MockTemplateService.
+type MockTemplateServiceMockRecorder struct {
+	mock *MockTemplateService
+}
+
+// NewMockTemplateService creates a new mock instance.
+func NewMockTemplateService(ctrl *gomock.Controller) *MockTemplateService {
+	mock := &MockTemplateService{ctrl: ctrl}
+	mock.recorder = &MockTemplateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateService) EXPECT() *MockTemplateServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSessionFromTemplate mocks base method.
+func (m *MockTemplateService) CreateSessionFromTemplate(ctx context.Context, userID, templateID uuid.UUID, performedAt *time.Time, sessionType, notes *string, status string) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSessionFromTemplate", ctx, userID, templateID, performedAt, sessionType, notes, status)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSessionFromTemplate indicates an expected call of CreateSessionFromTemplate.
+func (mr *MockTemplateServiceMockRecorder) CreateSessionFromTemplate(ctx, userID, templateID, performedAt, sessionType, notes, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionFromTemplate", reflect.TypeOf((*MockTemplateService)(nil).CreateSessionFromTemplate), ctx, userID, templateID, performedAt, sessionType, notes, status)
+}
+
+// ListTemplates mocks base method.
+func (m *MockTemplateService) ListTemplates(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", ctx, userID)
+	ret0, _ := ret[0].([]*models.SessionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockTemplateServiceMockRecorder) ListTemplates(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockTemplateService)(nil).ListTemplates), ctx, userID)
+}
+
+// SaveTemplate mocks base method.
+func (m *MockTemplateService) SaveTemplate(ctx context.Context, userID, sessionID uuid.UUID, name string) (*models.SessionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTemplate", ctx, userID, sessionID, name)
+	ret0, _ := ret[0].(*models.SessionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveTemplate indicates an expected call of SaveTemplate.
+func (mr *MockTemplateServiceMockRecorder) SaveTemplate(ctx, userID, sessionID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTemplate", reflect.TypeOf((*MockTemplateService)(nil).SaveTemplate), ctx, userID, sessionID, name)
+}
+
+// MockAdherenceService is a mock of AdherenceService interface.
+type MockAdherenceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdherenceServiceMockRecorder
+}
+
+// MockAdherenceServiceMockRecorder is the mock recorder for MockAdherenceService.
+type MockAdherenceServiceMockRecorder struct {
+	mock *MockAdherenceService
+}
+
+// NewMockAdherenceService creates a new mock instance.
+func NewMockAdherenceService(ctrl *gomock.Controller) *MockAdherenceService {
+	mock := &MockAdherenceService{ctrl: ctrl}
+	mock.recorder = &MockAdherenceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdherenceService) EXPECT() *MockAdherenceServiceMockRecorder {
+	return m.recorder
+}
+
+// SessionAdherence mocks base method.
+func (m *MockAdherenceService) SessionAdherence(ctx context.Context, userID, sessionID uuid.UUID) (*models.SessionAdherence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionAdherence", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*models.SessionAdherence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionAdherence indicates an expected call of SessionAdherence.
+func (mr *MockAdherenceServiceMockRecorder) SessionAdherence(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionAdherence", reflect.TypeOf((*MockAdherenceService)(nil).SessionAdherence), ctx, userID, sessionID)
+}
+
+// WeeklyAdherence mocks base method.
+func (m *MockAdherenceService) WeeklyAdherence(ctx context.Context, userID uuid.UUID, weeks int) ([]models.WeeklyAdherence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WeeklyAdherence", ctx, userID, weeks)
+	ret0, _ := ret[0].([]models.WeeklyAdherence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WeeklyAdherence indicates an expected call of WeeklyAdherence.
+func (mr *MockAdherenceServiceMockRecorder) WeeklyAdherence(ctx, userID, weeks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WeeklyAdherence", reflect.TypeOf((*MockAdherenceService)(nil).WeeklyAdherence), ctx, userID, weeks)
+}
+
+// MockCalendarService is a mock of CalendarService interface.
+type MockCalendarService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarServiceMockRecorder
+}
+
+// MockCalendarServiceMockRecorder is the mock recorder for MockCalendarService.
+type MockCalendarServiceMockRecorder struct {
+	mock *MockCalendarService
+}
+
+// NewMockCalendarService creates a new mock instance.
+func NewMockCalendarService(ctrl *gomock.Controller) *MockCalendarService {
+	mock := &MockCalendarService{ctrl: ctrl}
+	mock.recorder = &MockCalendarServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarService) EXPECT() *MockCalendarServiceMockRecorder {
+	return m.recorder
+}
+
+// Calendar mocks base method.
+func (m *MockCalendarService) Calendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.CalendarDay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Calendar", ctx, userID, from, to)
+	ret0, _ := ret[0].([]models.CalendarDay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Calendar indicates an expected call of Calendar.
+func (mr *MockCalendarServiceMockRecorder) Calendar(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Calendar", reflect.TypeOf((*MockCalendarService)(nil).Calendar), ctx, userID, from, to)
+}
+
+// Feed mocks base method.
+func (m *MockCalendarService) Feed(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Feed", ctx, token)
+	ret0, _ := ret[0].(*models.CalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Feed indicates an expected call of Feed.
+func (mr *MockCalendarServiceMockRecorder) Feed(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Feed", reflect.TypeOf((*MockCalendarService)(nil).Feed), ctx, token)
+}
+
+// IssueFeedToken mocks base method.
+func (m *MockCalendarService) IssueFeedToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueFeedToken", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueFeedToken indicates an expected call of IssueFeedToken.
+func (mr *MockCalendarServiceMockRecorder) IssueFeedToken(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueFeedToken", reflect.TypeOf((*MockCalendarService)(nil).IssueFeedToken), ctx, userID)
+}
+
+// Reschedule mocks base method.
+func (m *MockCalendarService) Reschedule(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, userID, sessionID, at)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockCalendarServiceMockRecorder) Reschedule(ctx, userID, sessionID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockCalendarService)(nil).Reschedule), ctx, userID, sessionID, at)
+}
+
+// RevokeFeedToken mocks base method.
+func (m *MockCalendarService) RevokeFeedToken(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFeedToken", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFeedToken indicates an expected call of RevokeFeedToken.
+func (mr *MockCalendarServiceMockRecorder) RevokeFeedToken(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFeedToken", reflect.TypeOf((*MockCalendarService)(nil).RevokeFeedToken), ctx, userID)
+}
+
+// MockStatsService is a mock of StatsService interface.
+type MockStatsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsServiceMockRecorder
+}
+
+// MockStatsServiceMockRecorder is the mock recorder for MockStatsService.
+type MockStatsServiceMockRecorder struct {
+	mock *MockStatsService
+}
+
+// NewMockStatsService creates a new mock instance.
+func NewMockStatsService(ctrl *gomock.Controller) *MockStatsService {
+	mock := &MockStatsService{ctrl: ctrl}
+	mock.recorder = &MockStatsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsService) EXPECT() *MockStatsServiceMockRecorder {
+	return m.recorder
+}
+
+// Consistency mocks base method.
+func (m *MockStatsService) Consistency(ctx context.Context, userID uuid.UUID, weeks int) (*models.ConsistencyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consistency", ctx, userID, weeks)
+	ret0, _ := ret[0].(*models.ConsistencyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consistency indicates an expected call of Consistency.
+func (mr *MockStatsServiceMockRecorder) Consistency(ctx, userID, weeks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consistency", reflect.TypeOf((*MockStatsService)(nil).Consistency), ctx, userID, weeks)
+}
+
+// MockHistoryService is a mock of HistoryService interface.
+type MockHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryServiceMockRecorder
+}
+
+// MockHistoryServiceMockRecorder is the mock recorder for MockHistoryService.
+type MockHistoryServiceMockRecorder struct {
+	mock *MockHistoryService
+}
+
+// NewMockHistoryService creates a new mock instance.
+func NewMockHistoryService(ctrl *gomock.Controller) *MockHistoryService {
+	mock := &MockHistoryService{ctrl: ctrl}
+	mock.recorder = &MockHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryService) EXPECT() *MockHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// ExerciseHistory mocks base method.
+func (m *MockHistoryService) ExerciseHistory(ctx context.Context, userID, exerciseID uuid.UUID, window string, kinds []string) (*models.ExerciseHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExerciseHistory", ctx, userID, exerciseID, window, kinds)
+	ret0, _ := ret[0].(*models.ExerciseHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExerciseHistory indicates an expected call of ExerciseHistory.
+func (mr *MockHistoryServiceMockRecorder) ExerciseHistory(ctx, userID, exerciseID, window, kinds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExerciseHistory", reflect.TypeOf((*MockHistoryService)(nil).ExerciseHistory), ctx, userID, exerciseID, window, kinds)
+}
+
+// MockDashboardService is a mock of DashboardService interface.
+type MockDashboardService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDashboardServiceMockRecorder
+}
+
+// MockDashboardServiceMockRecorder is the mock recorder for MockDashboardService.
+type MockDashboardServiceMockRecorder struct {
+	mock *MockDashboardService
+}
+
+// NewMockDashboardService creates a new mock instance.
+func NewMockDashboardService(ctrl *gomock.Controller) *MockDashboardService {
+	mock := &MockDashboardService{ctrl: ctrl}
+	mock.recorder = &MockDashboardServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDashboardService) EXPECT() *MockDashboardServiceMockRecorder {
+	return m.recorder
+}
+
+// Dashboard mocks base method.
+func (m *MockDashboardService) Dashboard(ctx context.Context, userID uuid.UUID) (*models.Dashboard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dashboard", ctx, userID)
+	ret0, _ := ret[0].(*models.Dashboard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dashboard indicates an expected call of Dashboard.
+func (mr *MockDashboardServiceMockRecorder) Dashboard(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dashboard", reflect.TypeOf((*MockDashboardService)(nil).Dashboard), ctx, userID)
+}
+
+// MockWorkloadService is a mock of WorkloadService interface.
+type MockWorkloadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkloadServiceMockRecorder
+}
+
+// MockWorkloadServiceMockRecorder is the mock recorder for MockWorkloadService.
+type MockWorkloadServiceMockRecorder struct {
+	mock *MockWorkloadService
+}
+
+// NewMockWorkloadService creates a new mock instance.
+func NewMockWorkloadService(ctrl *gomock.Controller) *MockWorkloadService {
+	mock := &MockWorkloadService{ctrl: ctrl}
+	mock.recorder = &MockWorkloadServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkloadService) EXPECT() *MockWorkloadServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckIn mocks base method.
+func (m *MockWorkloadService) CheckIn(ctx context.Context, userID uuid.UUID, sleep, soreness, stress int) (*models.ReadinessCheckIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIn", ctx, userID, sleep, soreness, stress)
+	ret0, _ := ret[0].(*models.ReadinessCheckIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIn indicates an expected call of CheckIn.
+func (mr *MockWorkloadServiceMockRecorder) CheckIn(ctx, userID, sleep, soreness, stress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIn", reflect.TypeOf((*MockWorkloadService)(nil).CheckIn), ctx, userID, sleep, soreness, stress)
+}
+
+// Workload mocks base method.
+func (m *MockWorkloadService) Workload(ctx context.Context, userID uuid.UUID) (*models.Workload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Workload", ctx, userID)
+	ret0, _ := ret[0].(*models.Workload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Workload indicates an expected call of Workload.
+func (mr *MockWorkloadServiceMockRecorder) Workload(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Workload", reflect.TypeOf((*MockWorkloadService)(nil).Workload), ctx, userID)
+}
+
 // MockPlanService is a mock of PlanService interface.
 type MockPlanService struct {
 	ctrl     *gomock.Controller
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/alexanderramin/kalistheniks/internal/config"
 	apiHandlers "github.com/alexanderramin/kalistheniks/internal/handlers/api"
 	authHandlers "github.com/alexanderramin/kalistheniks/internal/handlers/auth"
 	handlerMiddleware "github.com/alexanderramin/kalistheniks/internal/handlers/middleware"
@@ -21,7 +22,7 @@ func Router(app *App) http.Handler {
 	// Chi built-in middleware
 	r.Use(middleware.RealIP)                    // Extract real IP from proxies
 	r.Use(middleware.RequestID)                 // Generate unique request IDs
-	r.Use(middleware.Logger)                    // Log HTTP requests
+	r.Use(handlerMiddleware.Logger("token"))    // Log HTTP requests, masking link and feed tokens
 	r.Use(middleware.Recoverer)                 // Recover from panics
 	r.Use(middleware.Timeout(60 * time.Second)) // Request timeout protection
 	r.Use(middleware.Compress(5))               // gzip compression for responses
@@ -40,7 +41,7 @@ func Router(app *App) http.Handler {
 	// CORS middleware
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:8080"}, // Adjust for your frontend
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
@@ -50,21 +51,83 @@ func Router(app *App) http.Handler {
 	// Global rate limiting: 100 requests per minute per IP
 	r.Use(httprate.LimitByIP(100, 1*time.Minute))
 
-	auth := authHandlers.New(app.AuthService)
-	api := apiHandlers.New(app.SessionService, app.PlanService)
+	auth := authHandlers.New(app.AuthService, app.SocialLoginService, app.Config.DuplicateSignupPolicy)
+	api := apiHandlers.New(app.SessionService, app.PlanService, app.ProfileService, app.ExportService, app.BodyweightService, app.EquipmentService, app.TemplateService, app.AdherenceService, app.CalendarService, app.StatsService, app.HistoryService, app.DashboardService, app.WorkloadService)
 	authMw := handlerMiddleware.NewAuth(app.AuthService)
 
 	r.Get("/health", auth.Health)
-	r.Post("/signup", auth.Signup)
-	r.Post("/login", auth.Login)
+	r.Get("/verify-email", auth.VerifyEmail)
+	r.Get("/confirm-email", auth.ConfirmEmailChange)
+	r.Get("/auth/oidc", auth.ListProviders)
+	r.Get("/calendar.ics", api.CalendarFeed)
 
-	r.Group(func(protected chi.Router) {
-		protected.Use(authMw.RequireAuth)
-		protected.Get("/sessions", api.ListSessions)
-		protected.Post("/sessions", api.CreateSession)
-		protected.Post("/sessions/{id}/sets", api.CreateSet)
-		protected.Get("/plan/next", api.NextPlan)
+	// Credential endpoints get a much stricter per-IP limit to slow down guessing
+	r.Group(func(credentials chi.Router) {
+		credentials.Use(httprate.LimitByIP(authRateLimit(app.Config), 1*time.Minute))
+		credentials.Post("/signup", auth.Signup)
+		credentials.Post("/login", auth.Login)
+		credentials.Post("/login/mfa", auth.LoginMFA)
+		credentials.Post("/password/forgot", auth.ForgotPassword)
+		credentials.Post("/password/reset", auth.ResetPassword)
+		credentials.Get("/auth/oidc/{provider}", auth.StartOIDCLogin)
+		credentials.Get("/auth/oidc/{provider}/callback", auth.OIDCCallback)
+	})
+
+	r.Group(func(authenticated chi.Router) {
+		authenticated.Use(authMw.RequireAuth)
+		authenticated.Post("/verify-email/resend", auth.ResendVerification)
+		authenticated.Post("/me/2fa/enroll", auth.EnrollTOTP)
+		authenticated.Post("/me/2fa/confirm", auth.ConfirmTOTP)
+		authenticated.Post("/me/2fa/disable", auth.DisableTOTP)
+		authenticated.Post("/me/password", auth.ChangePassword)
+		authenticated.Post("/me/email", auth.ChangeEmail)
+		authenticated.Delete("/me", auth.DeleteAccount)
+		authenticated.Get("/me/export", api.ExportMe)
+
+		authenticated.Group(func(protected chi.Router) {
+			protected.Use(authMw.RequireVerifiedEmail(app.Config.EmailVerificationPolicy))
+			protected.Get("/me", api.GetMe)
+			protected.Patch("/me", api.UpdateMe)
+			protected.Get("/me/bodyweight", api.ListBodyweight)
+			protected.Post("/me/bodyweight", api.LogBodyweight)
+			protected.Get("/me/equipment", api.GetEquipment)
+			protected.Put("/me/equipment", api.UpdateEquipment)
+			protected.Get("/tools/plates", api.PlateLoading)
+			protected.Get("/sessions", api.ListSessions)
+			protected.Post("/sessions", api.CreateSession)
+			protected.Get("/sessions/{id}", api.GetSession)
+			protected.Post("/sessions/{id}/sets", api.CreateSet)
+			protected.Post("/sessions/{id}/groups", api.CreateGroup)
+			protected.Post("/sessions/{id}/planned-sets", api.CreatePlannedSet)
+			protected.Post("/sessions/{id}/start", api.StartSession)
+			protected.Post("/sessions/{id}/finish", api.FinishSession)
+			protected.Post("/sessions/{id}/reschedule", api.RescheduleSession)
+			protected.Post("/sessions/{id}/template", api.SaveTemplate)
+			protected.Get("/templates", api.ListTemplates)
+			protected.Get("/sessions/{id}/metrics", api.SessionMetrics)
+			protected.Get("/sessions/{id}/adherence", api.SessionAdherence)
+			protected.Get("/adherence/weekly", api.WeeklyAdherence)
+			protected.Get("/calendar", api.GetCalendar)
+			protected.Post("/me/calendar-feed", api.IssueCalendarFeed)
+			protected.Delete("/me/calendar-feed", api.RevokeCalendarFeed)
+			protected.Get("/stats/consistency", api.Consistency)
+			protected.Get("/exercises/{id}/history", api.ExerciseHistory)
+			protected.Get("/dashboard", api.GetDashboard)
+			protected.Post("/readiness", api.CheckIn)
+			protected.Get("/workload", api.GetWorkload)
+			protected.Get("/plan/next", api.NextPlan)
+		})
 	})
 
 	return r
 }
+
+// defaultAuthRateLimit applies when the config leaves the credential rate limit unset.
+const defaultAuthRateLimit = 10
+
+func authRateLimit(cfg config.Config) int {
+	if cfg.AuthRateLimit > 0 {
+		return cfg.AuthRateLimit
+	}
+	return defaultAuthRateLimit
+}
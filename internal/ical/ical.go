@@ -0,0 +1,88 @@
+// Package ical writes iCalendar feeds as described in RFC 5545. It covers what a subscribed calendar
+// needs to show a list of timed events, not the whole standard.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line allowed before it has to be folded, line break excluded.
+const maxLineOctets = 75
+
+const timeLayout = "20060102T150405Z"
+
+// Calendar is a feed of events.
+type Calendar struct {
+	ProdID string // identifies the product that wrote the feed, e.g. "-//Example//App//EN"
+	Name   string // shown by calendar apps that support X-WR-CALNAME
+	Events []Event
+}
+
+// Event is a single timed event. Calendar apps replace an event they already know when another with the
+// same UID arrives, so a UID must stay the same for as long as the event exists.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+}
+
+// Encode writes the calendar to w. Times are written in UTC and stamp is used as every event's DTSTAMP.
+func Encode(w io.Writer, c Calendar, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", c.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp.UTC().Format(timeLayout))
+		line("DTSTART", e.Start.UTC().Format(timeLayout))
+		line("DTEND", e.End.UTC().Format(timeLayout))
+		line("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeText(e.Description))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes a TEXT value: backslashes, semicolons and commas are backslash-escaped and line
+// breaks become \n.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// writeFolded writes a content line ended by CRLF. Lines longer than maxLineOctets are folded onto
+// continuation lines starting with a space, without splitting a UTF-8 sequence.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // the leading space counts toward the line
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,69 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	stamp := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("events in UTC with CRLF line endings", func(t *testing.T) {
+		var b strings.Builder
+		start := time.Date(2025, 3, 3, 18, 30, 0, 0, berlin)
+		require.NoError(t, Encode(&b, Calendar{ProdID: "-//Test//EN", Name: "Training", Events: []Event{
+			{UID: "abc@test", Start: start, End: start.Add(time.Hour), Summary: "Upper", Description: "Squat: 3 × 5"},
+		}}, stamp))
+
+		require.Equal(t, strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Test//EN",
+			"CALSCALE:GREGORIAN",
+			"X-WR-CALNAME:Training",
+			"BEGIN:VEVENT",
+			"UID:abc@test",
+			"DTSTAMP:20250301T120000Z",
+			"DTSTART:20250303T173000Z",
+			"DTEND:20250303T183000Z",
+			"SUMMARY:Upper",
+			"DESCRIPTION:Squat: 3 × 5",
+			"END:VEVENT",
+			"END:VCALENDAR",
+			"",
+		}, "\r\n"), b.String())
+	})
+
+	t.Run("text is escaped", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, Encode(&b, Calendar{ProdID: "-//Test//EN", Events: []Event{
+			{UID: "x", Summary: `a, b; c\d`, Description: "one\ntwo"},
+		}}, stamp))
+		require.Contains(t, b.String(), "SUMMARY:a\\, b\\; c\\\\d\r\n")
+		require.Contains(t, b.String(), "DESCRIPTION:one\\ntwo\r\n")
+		require.NotContains(t, b.String(), "X-WR-CALNAME")
+	})
+
+	t.Run("long lines are folded between characters", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, Encode(&b, Calendar{ProdID: "-//Test//EN", Events: []Event{
+			{UID: "x", Summary: strings.Repeat("ü", 100)},
+		}}, stamp))
+
+		var folded []string
+		for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+			require.LessOrEqual(t, len(line), maxLineOctets)
+			if strings.HasPrefix(line, " ") {
+				folded[len(folded)-1] += line[1:]
+				continue
+			}
+			folded = append(folded, line)
+		}
+		require.Contains(t, folded, "SUMMARY:"+strings.Repeat("ü", 100))
+	})
+}
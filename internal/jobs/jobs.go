@@ -0,0 +1,60 @@
+// Package jobs runs periodic maintenance tasks inside the API process.
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a task run on a fixed interval. Run should be idempotent: it runs once at startup and may
+// run on several instances at the same time.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Runner schedules jobs until its context is cancelled.
+type Runner struct {
+	logger *log.Logger
+	jobs   []Job
+	wg     sync.WaitGroup
+}
+
+func NewRunner(logger *log.Logger, jobs ...Job) *Runner {
+	return &Runner{logger: logger, jobs: jobs}
+}
+
+// Start runs every job once right away and then on its interval. It returns immediately; call Wait
+// after cancelling ctx to let running jobs finish.
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go func(job Job) {
+			defer r.wg.Done()
+			r.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Wait blocks until every job has stopped.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Printf("job %s failed: %v", job.Name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner(t *testing.T) {
+	t.Run("runs at start and on every tick until cancelled", func(t *testing.T) {
+		var runs atomic.Int32
+		ctx, cancel := context.WithCancel(context.Background())
+		r := NewRunner(log.New(&bytes.Buffer{}, "", 0), Job{
+			Name:     "count",
+			Interval: 10 * time.Millisecond,
+			Run: func(context.Context) error {
+				runs.Add(1)
+				return nil
+			},
+		})
+		r.Start(ctx)
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+		cancel()
+		r.Wait()
+		stopped := runs.Load()
+		time.Sleep(30 * time.Millisecond)
+		require.Equal(t, stopped, runs.Load())
+	})
+
+	t.Run("logs failures and keeps going", func(t *testing.T) {
+		var runs atomic.Int32
+		logs := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+		r := NewRunner(log.New(logs, "", 0), Job{
+			Name:     "flaky",
+			Interval: 10 * time.Millisecond,
+			Run: func(context.Context) error {
+				runs.Add(1)
+				return errors.New("boom")
+			},
+		})
+		r.Start(ctx)
+		require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		cancel()
+		r.Wait()
+		require.Contains(t, logs.String(), "job flaky failed: boom")
+	})
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// sendTimeout bounds a background delivery, which no longer has the request's deadline.
+const sendTimeout = time.Minute
+
+// AsyncMailer hands messages to another mailer in the background, so a slow or failing relay never
+// holds up a request or shows in its response. Delivery failures are logged.
+type AsyncMailer struct {
+	next   Mailer
+	logger *log.Logger
+	wg     sync.WaitGroup
+}
+
+func NewAsyncMailer(next Mailer, logger *log.Logger) *AsyncMailer {
+	return &AsyncMailer{next: next, logger: logger}
+}
+
+// Send checks the message and queues it; only an invalid message is reported.
+func (m *AsyncMailer) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+		defer cancel()
+		if err := m.next.Send(ctx, msg); err != nil {
+			m.logger.Printf("failed to send %q: %v", msg.Subject, err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until every queued message has been handed over.
+func (m *AsyncMailer) Wait() {
+	m.wg.Wait()
+}
@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email such as password reset links.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+func validate(msg Message) error {
+	if msg.To == "" || msg.Subject == "" {
+		return ErrInvalidMessage
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	t.Run("writes message", func(t *testing.T) {
+		var buf bytes.Buffer
+		m := NewLogMailer(&buf)
+
+		err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello", Body: "reset link"})
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "To: user@example.com")
+		require.Contains(t, buf.String(), "Subject: Hello")
+		require.Contains(t, buf.String(), "reset link")
+	})
+
+	t.Run("rejects message without recipient", func(t *testing.T) {
+		m := NewLogMailer(&bytes.Buffer{})
+		err := m.Send(context.Background(), Message{Subject: "Hello"})
+		require.ErrorIs(t, err, ErrInvalidMessage)
+	})
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Run("sends rendered message", func(t *testing.T) {
+		m := NewSMTPMailer("smtp.example.com", "587", "user", "pass", "noreply@example.com")
+		var gotAddr, gotFrom string
+		var gotTo []string
+		var gotMsg []byte
+		m.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+			gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+			return nil
+		}
+
+		err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Reset", Body: "line one\nline two"})
+		require.NoError(t, err)
+		require.Equal(t, "smtp.example.com:587", gotAddr)
+		require.Equal(t, "noreply@example.com", gotFrom)
+		require.Equal(t, []string{"user@example.com"}, gotTo)
+		require.Contains(t, string(gotMsg), "Subject: Reset\r\n")
+		require.Contains(t, string(gotMsg), "line one\r\nline two")
+	})
+
+	t.Run("strips header injection", func(t *testing.T) {
+		msg := buildMessage("noreply@example.com", Message{To: "user@example.com", Subject: "Hi\r\nBcc: evil@example.com"})
+		require.NotContains(t, string(msg), "\r\nBcc:")
+	})
+
+	t.Run("wraps transport error", func(t *testing.T) {
+		m := NewSMTPMailer("smtp.example.com", "25", "", "", "noreply@example.com")
+		m.sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+			return errors.New("connection refused")
+		}
+
+		err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Reset"})
+		require.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestAsyncMailer_Send(t *testing.T) {
+	t.Run("delivers in the background", func(t *testing.T) {
+		var buf bytes.Buffer
+		m := NewAsyncMailer(NewLogMailer(&buf), log.New(io.Discard, "", 0))
+
+		require.NoError(t, m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello"}))
+		m.Wait()
+		require.Contains(t, buf.String(), "To: user@example.com")
+	})
+
+	t.Run("logs failed deliveries instead of returning them", func(t *testing.T) {
+		var logged bytes.Buffer
+		smtpMailer := NewSMTPMailer("smtp.example.com", "587", "", "", "noreply@example.com")
+		smtpMailer.sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+			return errors.New("connection refused")
+		}
+		m := NewAsyncMailer(smtpMailer, log.New(&logged, "", 0))
+
+		require.NoError(t, m.Send(context.Background(), Message{To: "user@example.com", Subject: "Reset"}))
+		m.Wait()
+		require.Contains(t, logged.String(), "connection refused")
+	})
+
+	t.Run("rejects invalid message right away", func(t *testing.T) {
+		m := NewAsyncMailer(NewLogMailer(&bytes.Buffer{}), log.New(io.Discard, "", 0))
+		require.ErrorIs(t, m.Send(context.Background(), Message{Subject: "Hello"}), ErrInvalidMessage)
+	})
+}
@@ -7,13 +7,116 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID
-	Email        string
-	PasswordHash string
-	CreatedAt    time.Time
+	ID                  uuid.UUID
+	Email               string
+	PasswordHash        string
+	EmailVerifiedAt     *time.Time
+	FailedLoginAttempts int
+	LockedUntil         *time.Time
+	TOTPSecret          *string
+	TOTPEnabledAt       *time.Time
+	TokenVersion        int        // bumped to revoke all outstanding access tokens
+	DeletionScheduledAt *time.Time // set while a requested deletion is in its grace period
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// UserIdentity links a user to an account at an external OpenID provider.
+type UserIdentity struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Provider  string
+	Subject   string
+	Email     *string
+	CreatedAt time.Time
+}
+
+// Preferred unit systems.
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// How an exercise is loaded.
+const (
+	EquipmentBarbell    = "barbell"
+	EquipmentDumbbell   = "dumbbell"
+	EquipmentBodyweight = "bodyweight"
+)
+
+// What a set of an exercise records.
+const (
+	MeasurementReps     = "reps"     // repetitions, optionally with added load
+	MeasurementHold     = "hold"     // seconds a static position is held, e.g. a plank
+	MeasurementDuration = "duration" // seconds of continuous work, e.g. jump rope
+	MeasurementDistance = "distance" // metres covered, e.g. a farmer's carry
+)
+
+// The role a set plays in a session. Only working sets count toward progression, records and volume
+// unless asked otherwise.
+const (
+	SetKindWarmup  = "warmup"
+	SetKindWorking = "working"
+	SetKindDropset = "dropset"
+	SetKindAMRAP   = "amrap" // as many reps as possible
+	SetKindBackoff = "backoff"
+	SetKindFailure = "failure"
+)
+
+// Session lifecycle states. A session is planned ahead, in progress once started, and ends completed or
+// abandoned.
+const (
+	SessionPlanned    = "planned"
+	SessionInProgress = "in_progress"
+	SessionCompleted  = "completed"
+	SessionAbandoned  = "abandoned"
+)
+
+// How the sets of a group are performed.
+const (
+	GroupSuperset = "superset"  // two exercises back to back
+	GroupGiantSet = "giant_set" // three or more exercises back to back
+	GroupCircuit  = "circuit"
+	GroupEMOM     = "emom"  // every minute on the minute; a round per minute
+	GroupAMRAP    = "amrap" // as many rounds as possible in a time cap
+)
+
+// Training experience levels.
+const (
+	ExperienceBeginner     = "beginner"
+	ExperienceIntermediate = "intermediate"
+	ExperienceAdvanced     = "advanced"
+)
+
+// Profile holds body measurements and preferences. Measurements are stored metric regardless of Units.
+type Profile struct {
+	UserID       uuid.UUID
+	BodyweightKG *float64
+	HeightCM     *float64
+	BirthYear    *int
+	Experience   *string
+	Units        string
+	TimeZone     string
+	TrainingDays []string // lowercase weekday names, e.g. "monday"
 	UpdatedAt    time.Time
 }
 
+// ProfileUpdate carries the fields of a partial profile update; nil fields are left unchanged unless
+// the matching Clear flag asks for them to be emptied.
+type ProfileUpdate struct {
+	BodyweightKG *float64
+	HeightCM     *float64
+	BirthYear    *int
+	Experience   *string
+	Units        *string
+	TimeZone     *string
+	TrainingDays *[]string
+
+	ClearBodyweight bool
+	ClearHeight     bool
+	ClearBirthYear  bool
+}
+
 type Exercise struct {
 	ID              uuid.UUID
 	Name            string
@@ -21,6 +124,56 @@ type Exercise struct {
 	PrimaryMuscle   *string // TODO: enum
 	SecondaryMuscle *string // TODO: enum
 	IsActive        bool
+	// BodyweightFraction is the share of bodyweight moved per rep, e.g. 0.64 for push-ups; 0 for
+	// exercises where only the external load counts.
+	BodyweightFraction float64
+	// Equipment is how the load is added: plates on a barbell, a dumbbell, or plates on a dip belt
+	// or vest for bodyweight exercises.
+	Equipment string
+	// Measurement is what a set of the exercise records; see the Measurement constants.
+	Measurement string
+}
+
+// Equipment describes what a user has available to train with. Weights are in kilograms; plates are
+// denominations, each assumed to be available in as many pairs as needed.
+type Equipment struct {
+	UserID         uuid.UUID
+	BarKG          float64
+	PlatesKG       []float64
+	DumbbellsKG    []float64
+	HasRings       bool
+	HasPullUpBar   bool
+	HasParallettes bool
+	UpdatedAt      *time.Time // nil until the user saves their own equipment
+}
+
+// PlateLoading is how to load a bar for a target weight with the user's plates.
+type PlateLoading struct {
+	TargetKG   float64
+	AchievedKG float64 // closest weight the plates can make; equals TargetKG when exact
+	BarKG      float64
+	PerSideKG  []float64 // plates for each side, heaviest first
+}
+
+// BodyweightEntry is a single bodyweight measurement.
+type BodyweightEntry struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	MeasuredAt time.Time
+	WeightKG   float64
+	CreatedAt  time.Time
+}
+
+// ReadinessCheckIn is how a user feels on a day, each rated 1 to 5: Sleep from poor to great, Soreness
+// and Stress from none to severe.
+type ReadinessCheckIn struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Day       time.Time // midnight UTC of the day in the user's time zone
+	Sleep     int
+	Soreness  int
+	Stress    int
+	CreatedAt time.Time
 }
 
 type Session struct {
@@ -29,7 +182,175 @@ type Session struct {
 	PerformedAt time.Time
 	Notes       *string
 	SessionType *string // TODO: enum
-	Sets        []Set
+	Status      string  // see the Session constants
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	// RescheduledFrom is when a planned session that was moved was first scheduled for, and MissedCount
+	// how often it was moved because its day passed.
+	RescheduledFrom *time.Time
+	MissedCount     int
+	Sets            []Set // every set of the session, grouped or not
+	Groups          []SetGroup
+	PlannedSets     []PlannedSet // targets set before the session, in order
+}
+
+// PlannedSet is the target for one set of a session, set before it is trained. Actual sets are logged
+// separately and never change it.
+type PlannedSet struct {
+	ID              uuid.UUID
+	SessionID       uuid.UUID
+	Position        int
+	ExerciseID      uuid.UUID
+	Kind            string
+	Reps            int
+	WeightKG        float64
+	DurationSeconds *int
+	DistanceM       *float64
+	RestSeconds     *int // rest before the set
+	GroupID         *uuid.UUID
+	Round           *int
+}
+
+// CalendarDay is one day of a user's training calendar, in their time zone.
+type CalendarDay struct {
+	Date        time.Time // midnight at the start of the day
+	TrainingDay bool      // the weekday is one of the user's training days
+	Missed      bool      // the day is over and a session planned for it was never started
+	Sessions    []*Session
+}
+
+// CalendarFeed is what a calendar subscription shows: the user's upcoming planned sessions with the
+// exercises their planned sets refer to.
+type CalendarFeed struct {
+	Profile   *Profile
+	Sessions  []*Session // planned sessions with their planned sets, soonest first
+	Exercises map[uuid.UUID]Exercise
+}
+
+// ConsistencyStats describes how regularly a user trains. Weeks start on Monday in the user's time zone
+// and only completed sessions count.
+type ConsistencyStats struct {
+	CurrentStreakWeeks int // weeks in a row with a session, up to this week or, while it has none yet, last week
+	LongestStreakWeeks int
+	TotalSessions      int // every completed session, not just those in Weeks
+	// Weeks, SessionsPerWeek, AverageDurationSeconds and SessionsByWeekday cover the last weeks, this one
+	// included.
+	Weeks                  []WeeklySessions
+	SessionsPerWeek        float64
+	AverageDurationSeconds *int                 // nil when none of the sessions were timed
+	SessionsByWeekday      map[time.Weekday]int // every weekday is present
+}
+
+// WeeklySessions counts the completed sessions of one week.
+type WeeklySessions struct {
+	WeekStart time.Time
+	Sessions  int
+}
+
+// WeeklyVolume adds up the working sets logged in one week.
+type WeeklyVolume struct {
+	WeekStart time.Time
+	Sessions  int // sessions with at least one working set
+	Sets      int
+	Reps      int
+	VolumeKG  float64 // effective load × reps
+}
+
+// Dashboard gathers what the home screen shows in one place.
+type Dashboard struct {
+	LastSession        *Session // with its sets; nil before the first workout
+	NextSession        *Session // the soonest planned session with its planned sets; nil when none
+	Suggestion         *PlanSuggestion
+	ThisWeek           WeeklyVolume
+	LastWeek           WeeklyVolume
+	RecentRecords      []PersonalRecord // newest first
+	CurrentStreakWeeks int
+	LongestStreakWeeks int
+	// Exercises holds every exercise referenced by the sessions and records above.
+	Exercises map[uuid.UUID]Exercise
+}
+
+// Workload compares the training load of the last week with that of the last four. A session's load is
+// the sum of the RPE of its sets, warm-ups left out.
+type Workload struct {
+	AcuteLoad   float64           // the last 7 days
+	ChronicLoad float64           // weekly average over the last 28 days
+	Ratio       *float64          // acute over chronic; nil until there are three weeks of history
+	Readiness   *ReadinessCheckIn // today's check-in, if any
+	// ReadinessScore runs from 0, worst, to 1, best; nil without a check-in today.
+	ReadinessScore *float64
+	VolumeScale    float64 // what suggested volume is multiplied by; 1 leaves it as it is
+	Notes          string  // why volume is scaled down
+}
+
+// Adherence compares planned sets with the sets logged against them.
+type Adherence struct {
+	PlannedSets   int
+	CompletedSets int // planned sets matched by a logged set of the same exercise and kind
+	PlannedReps   int
+	ActualReps    int     // reps of the matching logged sets
+	PlannedLoadKG float64 // reps × weight summed over the planned sets
+	ActualLoadKG  float64 // reps × weight summed over the matching logged sets
+}
+
+// SessionAdherence is the adherence of one session to its plan.
+type SessionAdherence struct {
+	SessionID   uuid.UUID
+	PerformedAt time.Time
+	Status      string
+	Adherence   Adherence
+}
+
+// WeeklyAdherence adds up the adherence of the finished sessions with a plan in one week of the user's
+// time zone, starting on Monday.
+type WeeklyAdherence struct {
+	WeekStart time.Time
+	Sessions  int
+	Adherence Adherence
+}
+
+// SessionTemplate is the structure of a session saved for reuse: its groups and its sets in order.
+type SessionTemplate struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	SessionType *string
+	CreatedAt   time.Time
+	Groups      []TemplateGroup
+	Sets        []TemplateSet
+}
+
+// TemplateGroup is a set group of a template, identified by its index within the template.
+type TemplateGroup struct {
+	GroupIndex  int
+	Kind        string
+	Rounds      *int
+	RestSeconds *int
+}
+
+// TemplateSet is one set of a template. GroupIndex refers to a TemplateGroup of the same template.
+type TemplateSet struct {
+	Position        int
+	ExerciseID      uuid.UUID
+	Kind            string
+	Reps            int
+	WeightKG        float64
+	DurationSeconds *int
+	DistanceM       *float64
+	RestSeconds     *int
+	GroupIndex      *int
+	Round           *int
+}
+
+// SetGroup is a block of sets performed together, such as a superset or a circuit. Its sets are the
+// ones in Session.Sets with the group's ID.
+type SetGroup struct {
+	ID          uuid.UUID
+	SessionID   uuid.UUID
+	GroupIndex  int
+	Kind        string // see the Group constants
+	Rounds      *int   // planned rounds; nil when open-ended, e.g. an AMRAP block
+	RestSeconds *int   // rest target after each round
 }
 
 type Set struct {
@@ -37,15 +358,111 @@ type Set struct {
 	SessionID  uuid.UUID
 	ExerciseID uuid.UUID
 	SetIndex   int
-	Reps       int
+	Kind       string // see the SetKind constants
+	Reps       int    // 0 for sets measured by time or distance
 	WeightKG   float64
 	RPE        *int
+	// DurationSeconds is the hold or work time of hold and duration sets, optionally of distance sets.
+	DurationSeconds *int
+	DistanceM       *float64 // distance sets only
+	GroupID         *uuid.UUID
+	Round           *int // round within the group, from 1; nil for sets outside a group
+	CompletedAt     time.Time
+	// RestSeconds is the rest the client's timer counted down before the set, if it ran one.
+	RestSeconds *int
+	// ActualRestSeconds is the time between the end of the previous set of the session and the start
+	// of this one. It is worked out when a session is read and is nil for the first set.
+	ActualRestSeconds *int
+}
+
+// SetLoad is the load of one set with the athlete's bodyweight taken into account.
+type SetLoad struct {
+	SetID       uuid.UUID
+	ExerciseID  uuid.UUID
+	Kind        string
+	Reps        int
+	AddedKG     float64  // external load logged on the set
+	EffectiveKG float64  // AddedKG plus the exercise's share of bodyweight
+	Relative    *float64 // EffectiveKG / bodyweight; nil when bodyweight is unknown
+}
+
+// SessionMetrics summarises the load of a session using the bodyweight closest in time to it.
+type SessionMetrics struct {
+	SessionID    uuid.UUID
+	PerformedAt  time.Time
+	BodyweightKG *float64 // nil when the user has never recorded a bodyweight
+	TonnageKG    float64  // sum of EffectiveKG × reps over the sets of the counted kinds
+	Sets         []SetLoad
+}
+
+// PersonalRecord is the heaviest set logged for an exercise; ties go to the set with more reps.
+type PersonalRecord struct {
+	ExerciseID  uuid.UUID
+	WeightKG    float64
+	Reps        int
+	SessionID   uuid.UUID
+	PerformedAt time.Time
+}
+
+// History windows group an exercise's history by session, by week or by calendar month.
+const (
+	HistoryWindowSession = "session"
+	HistoryWindowWeek    = "week"
+	HistoryWindowMonth   = "month"
+)
+
+// ExerciseHistory is a time series of one exercise's training, oldest first.
+type ExerciseHistory struct {
+	ExerciseID  uuid.UUID
+	Measurement string // see the Measurement constants
+	Window      string // see the HistoryWindow constants
+	Points      []ExerciseHistoryPoint
+}
+
+// ExerciseHistoryPoint sums up the counted sets of an exercise within one session, week or month. Loads
+// include the exercise's share of the bodyweight closest in time to each session.
+type ExerciseHistoryPoint struct {
+	Start     time.Time  // when the session was performed, or midnight starting its week or month
+	SessionID *uuid.UUID // set for per-session points only
+	Sessions  int
+	Sets      int
+	// BestSet is the heaviest set, then the one with the most reps; for holds and timed work the longest
+	// one and for distance work the farthest.
+	BestSet       Set
+	BestSetLoadKG float64
+	TotalReps     int
+	VolumeKG      float64  // effective load × reps
+	TopE1RMKG     *float64 // nil when no set had a load and few enough reps to estimate from
+	AverageRPE    *float64 // nil when no set was rated
+}
+
+// DataExport is everything stored about a user, assembled for a data portability request.
+type DataExport struct {
+	GeneratedAt time.Time
+	User        *User
+	Profile     *Profile
+	Identities  []*UserIdentity
+	Bodyweight  []*BodyweightEntry
+	Equipment   *Equipment // nil when the user never saved any
+	Sessions    []*Session
+	Templates   []*SessionTemplate
+	Readiness   []*ReadinessCheckIn
+	Exercises   []Exercise // exercises referenced by the user's sets
+	Records     []PersonalRecord
 }
 
 // PlanSuggestion is a lightweight container for the progression endpoint.
 type PlanSuggestion struct {
 	ExerciseID uuid.UUID `json:"exercise_id"`
 	WeightKG   float64   `json:"weight_kg"`
+	Weight     float64   `json:"weight"` // WeightKG in the user's unit
+	Unit       string    `json:"unit"`
 	Reps       int       `json:"reps"`
-	Notes      string    `json:"notes,omitempty"`
+	// DurationSeconds is the target time for hold and duration exercises.
+	DurationSeconds *int     `json:"duration_seconds,omitempty"`
+	DistanceM       *float64 `json:"distance_m,omitempty"` // distance exercises only
+	RestSeconds     int      `json:"rest_seconds"`         // suggested rest before the set
+	// VolumeScale is set when the user's workload or readiness scaled the reps, time or distance down.
+	VolumeScale *float64 `json:"volume_scale,omitempty"`
+	Notes       string   `json:"notes,omitempty"`
 }
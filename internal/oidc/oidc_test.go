@@ -0,0 +1,108 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/oidc"
+	"github.com/alexanderramin/kalistheniks/internal/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:8080/auth/oidc/test/callback"
+
+func TestProvider_CodeFlow(t *testing.T) {
+	ctx := context.Background()
+	idp := oidctest.NewServer("client-1", "secret-1")
+	defer idp.Close()
+
+	login := func(t *testing.T, p *oidc.Provider, nonce, verifier string) (string, string) {
+		t.Helper()
+		authURL, err := p.AuthCodeURL(ctx, "state-1", nonce, oidc.S256Challenge(verifier))
+		require.NoError(t, err)
+		code, state, err := idp.Authorize(authURL)
+		require.NoError(t, err)
+		return code, state
+	}
+
+	t.Run("auth url carries pkce and nonce", func(t *testing.T) {
+		p := oidc.NewProvider(idp.Config(redirectURL), nil)
+		authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.S256Challenge("verifier"))
+		require.NoError(t, err)
+
+		u, err := url.Parse(authURL)
+		require.NoError(t, err)
+		q := u.Query()
+		require.Equal(t, "code", q.Get("response_type"))
+		require.Equal(t, "client-1", q.Get("client_id"))
+		require.Equal(t, redirectURL, q.Get("redirect_uri"))
+		require.Equal(t, "S256", q.Get("code_challenge_method"))
+		require.Equal(t, oidc.S256Challenge("verifier"), q.Get("code_challenge"))
+		require.Equal(t, "nonce-1", q.Get("nonce"))
+		require.Equal(t, "openid email profile", q.Get("scope"))
+	})
+
+	t.Run("exchanges code for verified identity", func(t *testing.T) {
+		idp.SetUser(oidctest.User{Subject: "abc", Email: "a@example.com", EmailVerified: true})
+		p := oidc.NewProvider(idp.Config(redirectURL), nil)
+		verifier, err := oidc.GenerateVerifier()
+		require.NoError(t, err)
+
+		code, state := login(t, p, "nonce-1", verifier)
+		require.Equal(t, "state-1", state)
+
+		identity, err := p.Exchange(ctx, code, verifier, "nonce-1")
+		require.NoError(t, err)
+		require.Equal(t, &oidc.Identity{Subject: "abc", Email: "a@example.com", EmailVerified: true}, identity)
+
+		_, err = p.Exchange(ctx, code, verifier, "nonce-1")
+		require.ErrorIs(t, err, oidc.ErrExchange, "codes are single use")
+	})
+
+	t.Run("wrong code verifier is rejected", func(t *testing.T) {
+		p := oidc.NewProvider(idp.Config(redirectURL), nil)
+		code, _ := login(t, p, "nonce-1", "right-verifier-right-verifier-right-verifier")
+
+		_, err := p.Exchange(ctx, code, "wrong-verifier-wrong-verifier-wrong-verifier", "nonce-1")
+		require.ErrorIs(t, err, oidc.ErrExchange)
+	})
+
+	t.Run("nonce mismatch is rejected", func(t *testing.T) {
+		p := oidc.NewProvider(idp.Config(redirectURL), nil)
+		code, _ := login(t, p, "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+
+		_, err := p.Exchange(ctx, code, "verifier-verifier-verifier-verifier-verifier", "nonce-2")
+		require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("public client sends client id in body", func(t *testing.T) {
+		public := oidctest.NewServer("public-client", "")
+		defer public.Close()
+		p := oidc.NewProvider(public.Config(redirectURL), nil)
+		verifier, err := oidc.GenerateVerifier()
+		require.NoError(t, err)
+
+		authURL, err := p.AuthCodeURL(ctx, "s", "n", oidc.S256Challenge(verifier))
+		require.NoError(t, err)
+		code, _, err := public.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = p.Exchange(ctx, code, verifier, "n")
+		require.NoError(t, err)
+	})
+
+	t.Run("issuer mismatch fails discovery", func(t *testing.T) {
+		cfg := idp.Config(redirectURL)
+		cfg.Issuer = idp.URL + "/"
+		p := oidc.NewProvider(cfg, nil)
+
+		_, err := p.AuthCodeURL(ctx, "s", "n", "c")
+		require.ErrorIs(t, err, oidc.ErrDiscovery)
+	})
+}
+
+func TestS256Challenge(t *testing.T) {
+	// RFC 7636 appendix B
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.S256Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,135 @@
+// Package plates works out which loads a set of plates, bar and dumbbells can actually make. The
+// functions are unit agnostic; callers pass every weight in the same unit.
+package plates
+
+import (
+	"math"
+	"sort"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+)
+
+// Defaults returns the bar and plates of a typical commercial gym in the given unit system, in
+// kilograms: a 20 kg bar with 25 to 1.25 kg plates, or a 45 lb bar with 45 to 2.5 lb plates.
+func Defaults(system string) (barKG float64, platesKG []float64) {
+	if system != models.UnitsImperial {
+		return 20, []float64{25, 20, 15, 10, 5, 2.5, 1.25}
+	}
+	lb := []float64{45, 35, 25, 10, 5, 2.5}
+	platesKG = make([]float64, len(lb))
+	for i, p := range lb {
+		platesKG[i] = units.ToKG(p, system)
+	}
+	return units.ToKG(45, system), platesKG
+}
+
+// Load returns the closest total to target that bar plus matching plates on each side can make, and
+// the plates for one side, heaviest first. Targets at or below the bar give the empty bar.
+func Load(target, bar float64, plates []float64) (float64, []float64) {
+	if target <= bar {
+		return bar, nil
+	}
+	side, perSide := Combine((target-bar)/2, plates)
+	return round(bar + 2*side), perSide
+}
+
+// Combine returns the closest sum to target that the plates can make, using each denomination as
+// often as needed, and the plates used, heaviest first. Ties go to the lighter sum and, for the
+// same sum, the fewest plates win.
+func Combine(target float64, plates []float64) (float64, []float64) {
+	t := toCents(target)
+	if t <= 0 {
+		return 0, nil
+	}
+	limit := t + maxCents(plates)
+	count, last := reachable(plates, limit)
+
+	best := 0
+	for d := 0; d <= t; d++ {
+		if count[t-d] >= 0 {
+			best = t - d
+			break
+		}
+		if t+d <= limit && count[t+d] >= 0 {
+			best = t + d
+			break
+		}
+	}
+
+	var used []float64
+	for i := best; i > 0; i -= last[i] {
+		used = append(used, float64(last[i])/100)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(used)))
+	return float64(best) / 100, used
+}
+
+// Sums lists every total up to max that the plates can make, ascending and starting at zero.
+func Sums(plates []float64, max float64) []float64 {
+	count, _ := reachable(plates, toCents(max))
+	var sums []float64
+	for i, c := range count {
+		if c >= 0 {
+			sums = append(sums, float64(i)/100)
+		}
+	}
+	return sums
+}
+
+// Nearest returns the option closest to target, preferring the lighter of two equally close ones.
+// Without options target is returned unchanged.
+func Nearest(target float64, options []float64) float64 {
+	if len(options) == 0 {
+		return target
+	}
+	best := options[0]
+	for _, o := range options[1:] {
+		d, bd := math.Abs(o-target), math.Abs(best-target)
+		if d < bd || (d == bd && o < best) {
+			best = o
+		}
+	}
+	return best
+}
+
+// reachable runs an unbounded coin change over hundredths: count[i] is the fewest plates summing to
+// i, or -1 when no combination does, and last[i] is the plate added to reach it.
+func reachable(plates []float64, limit int) (count, last []int) {
+	count = make([]int, limit+1)
+	last = make([]int, limit+1)
+	for i := 1; i <= limit; i++ {
+		count[i] = -1
+	}
+	for i := 1; i <= limit; i++ {
+		for _, p := range plates {
+			c := toCents(p)
+			if c <= 0 || c > i || count[i-c] < 0 {
+				continue
+			}
+			if count[i] < 0 || count[i-c]+1 < count[i] {
+				count[i] = count[i-c] + 1
+				last[i] = c
+			}
+		}
+	}
+	return count, last
+}
+
+func maxCents(plates []float64) int {
+	m := 0
+	for _, p := range plates {
+		if c := toCents(p); c > m {
+			m = c
+		}
+	}
+	return m
+}
+
+func toCents(v float64) int {
+	return int(math.Round(v * 100))
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
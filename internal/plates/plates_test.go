@@ -0,0 +1,62 @@
+package plates
+
+import (
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	standard := []float64{25, 20, 15, 10, 5, 2.5, 1.25}
+
+	t.Run("exact loading uses fewest plates", func(t *testing.T) {
+		total, perSide := Load(142.5, 20, standard)
+		require.Equal(t, 142.5, total)
+		require.Equal(t, []float64{25, 25, 10, 1.25}, perSide)
+	})
+
+	t.Run("limited plates snap to the closest total", func(t *testing.T) {
+		total, perSide := Load(82.5, 20, []float64{5, 1.25})
+		require.Equal(t, 82.5, total)
+		require.Equal(t, []float64{5, 5, 5, 5, 5, 5, 1.25}, perSide)
+
+		total, _ = Load(83, 20, []float64{5})
+		require.Equal(t, 80.0, total, "ties go to the lighter load")
+	})
+
+	t.Run("non-greedy combinations are found", func(t *testing.T) {
+		total, perSide := Load(32, 20, []float64{5, 3})
+		require.Equal(t, 32.0, total)
+		require.Equal(t, []float64{3, 3}, perSide)
+	})
+
+	t.Run("target below the bar", func(t *testing.T) {
+		total, perSide := Load(15, 20, standard)
+		require.Equal(t, 20.0, total)
+		require.Empty(t, perSide)
+	})
+
+	t.Run("no plates", func(t *testing.T) {
+		total, _ := Load(60, 20, nil)
+		require.Equal(t, 20.0, total)
+	})
+}
+
+func TestSumsAndNearest(t *testing.T) {
+	require.Equal(t, []float64{0, 5, 7.5, 10}, Sums([]float64{5, 7.5}, 11))
+	require.Equal(t, 10.0, Nearest(11, []float64{5, 10, 15}))
+	require.Equal(t, 10.0, Nearest(12.5, []float64{15, 10}))
+	require.Equal(t, 7.0, Nearest(7, nil))
+}
+
+func TestDefaults(t *testing.T) {
+	bar, plates := Defaults(models.UnitsMetric)
+	require.Equal(t, 20.0, bar)
+	require.Contains(t, plates, 1.25)
+
+	bar, plates = Defaults(models.UnitsImperial)
+	require.Equal(t, 45.0, units.FromKG(bar, models.UnitsImperial))
+	require.Equal(t, 45.0, units.FromKG(plates[0], models.UnitsImperial))
+}
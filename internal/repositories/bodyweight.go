@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type BodyweightRepository struct {
+	db *sql.DB
+}
+
+func NewBodyweightRepository(db *sql.DB) *BodyweightRepository {
+	return &BodyweightRepository{db: db}
+}
+
+func (r *BodyweightRepository) Create(ctx context.Context, userID uuid.UUID, weightKG float64, measuredAt time.Time) (*models.BodyweightEntry, error) {
+	const q = `
+INSERT INTO bodyweight_entries (user_id, weight_kg, measured_at)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, measured_at, weight_kg, created_at`
+
+	var e models.BodyweightEntry
+	err := r.db.QueryRowContext(ctx, q, userID, weightKG, measuredAt).
+		Scan(&e.ID, &e.UserID, &e.MeasuredAt, &e.WeightKG, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// List returns the user's entries, most recent measurement first.
+func (r *BodyweightRepository) List(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error) {
+	const q = `
+SELECT id, user_id, measured_at, weight_kg, created_at
+FROM bodyweight_entries
+WHERE user_id = $1
+ORDER BY measured_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.BodyweightEntry{}
+	for rows.Next() {
+		var e models.BodyweightEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.MeasuredAt, &e.WeightKG, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Nearest returns the entry measured closest to at, before or after. It returns sql.ErrNoRows when the
+// user has not logged any bodyweight.
+func (r *BodyweightRepository) Nearest(ctx context.Context, userID uuid.UUID, at time.Time) (*models.BodyweightEntry, error) {
+	const q = `
+SELECT id, user_id, measured_at, weight_kg, created_at
+FROM bodyweight_entries
+WHERE user_id = $1
+ORDER BY ABS(EXTRACT(EPOCH FROM (measured_at - $2::timestamptz))), measured_at DESC
+LIMIT 1`
+
+	var e models.BodyweightEntry
+	err := r.db.QueryRowContext(ctx, q, userID, at).
+		Scan(&e.ID, &e.UserID, &e.MeasuredAt, &e.WeightKG, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
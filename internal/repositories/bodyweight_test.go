@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyweightRepository(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(testDB)
+	repo := NewBodyweightRepository(testDB)
+	user, err := users.Create(ctx, "bodyweight@example.com", "hash")
+	require.NoError(t, err)
+	defer truncateUsers(t)
+
+	t.Run("no entries", func(t *testing.T) {
+		entries, err := repo.List(ctx, user.ID)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		_, err = repo.Nearest(ctx, user.ID, time.Now())
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("lists newest first and finds nearest", func(t *testing.T) {
+		base := time.Date(2030, 3, 1, 8, 0, 0, 0, time.UTC)
+		for i, weight := range []float64{80, 81, 82} {
+			_, err := repo.Create(ctx, user.ID, weight, base.AddDate(0, 0, 7*i))
+			require.NoError(t, err)
+		}
+
+		entries, err := repo.List(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, 82.0, entries[0].WeightKG)
+
+		nearest, err := repo.Nearest(ctx, user.ID, base.AddDate(0, 0, 5))
+		require.NoError(t, err)
+		require.Equal(t, 81.0, nearest.WeightKG, "a later entry can be the closest")
+
+		nearest, err = repo.Nearest(ctx, user.ID, base.AddDate(1, 0, 0))
+		require.NoError(t, err)
+		require.Equal(t, 82.0, nearest.WeightKG)
+	})
+
+	t.Run("rejects non-positive weight", func(t *testing.T) {
+		_, err := repo.Create(ctx, user.ID, 0, time.Now())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type EquipmentRepository struct {
+	db *sql.DB
+}
+
+func NewEquipmentRepository(db *sql.DB) *EquipmentRepository {
+	return &EquipmentRepository{db: db}
+}
+
+// equipmentColumns lists the user_equipment columns in the order scanEquipment expects.
+const equipmentColumns = `user_id, bar_kg, plates_kg, dumbbells_kg, has_rings, has_pullup_bar, has_parallettes, updated_at`
+
+// Get returns the user's saved equipment, or sql.ErrNoRows when they never saved any.
+func (r *EquipmentRepository) Get(ctx context.Context, userID uuid.UUID) (*models.Equipment, error) {
+	q := `SELECT ` + equipmentColumns + ` FROM user_equipment WHERE user_id = $1`
+	return scanEquipment(r.db.QueryRowContext(ctx, q, userID))
+}
+
+// Save replaces the user's equipment.
+func (r *EquipmentRepository) Save(ctx context.Context, e *models.Equipment) (*models.Equipment, error) {
+	q := `
+INSERT INTO user_equipment (user_id, bar_kg, plates_kg, dumbbells_kg, has_rings, has_pullup_bar, has_parallettes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id) DO UPDATE
+SET bar_kg = EXCLUDED.bar_kg,
+    plates_kg = EXCLUDED.plates_kg,
+    dumbbells_kg = EXCLUDED.dumbbells_kg,
+    has_rings = EXCLUDED.has_rings,
+    has_pullup_bar = EXCLUDED.has_pullup_bar,
+    has_parallettes = EXCLUDED.has_parallettes,
+    updated_at = NOW()
+RETURNING ` + equipmentColumns
+
+	row := r.db.QueryRowContext(ctx, q, e.UserID, e.BarKG, pq.Array(nonNil(e.PlatesKG)), pq.Array(nonNil(e.DumbbellsKG)),
+		e.HasRings, e.HasPullUpBar, e.HasParallettes)
+	return scanEquipment(row)
+}
+
+func scanEquipment(row *sql.Row) (*models.Equipment, error) {
+	var e models.Equipment
+	var plates, dumbbells pq.Float64Array
+	if err := row.Scan(&e.UserID, &e.BarKG, &plates, &dumbbells, &e.HasRings, &e.HasPullUpBar, &e.HasParallettes, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	e.PlatesKG = nonNil(plates)
+	e.DumbbellsKG = nonNil(dumbbells)
+	return &e, nil
+}
+
+func nonNil(v []float64) []float64 {
+	if v == nil {
+		return []float64{}
+	}
+	return v
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEquipmentRepository(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(testDB)
+	repo := NewEquipmentRepository(testDB)
+	user, err := users.Create(ctx, "equipment@example.com", "hash")
+	require.NoError(t, err)
+	defer truncateUsers(t)
+
+	_, err = repo.Get(ctx, user.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	saved, err := repo.Save(ctx, &models.Equipment{UserID: user.ID, BarKG: 15, PlatesKG: []float64{10, 1.25}, HasPullUpBar: true})
+	require.NoError(t, err)
+	require.Equal(t, []float64{10, 1.25}, saved.PlatesKG)
+	require.Equal(t, []float64{}, saved.DumbbellsKG)
+	require.NotNil(t, saved.UpdatedAt)
+
+	_, err = repo.Save(ctx, &models.Equipment{UserID: user.ID, BarKG: 20, DumbbellsKG: []float64{12.5, 15}})
+	require.NoError(t, err)
+	got, err := repo.Get(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, 20.0, got.BarKG)
+	require.Empty(t, got.PlatesKG, "save replaces the whole profile")
+	require.Equal(t, []float64{12.5, 15}, got.DumbbellsKG)
+	require.False(t, got.HasPullUpBar)
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ExerciseRepository struct {
+	db *sql.DB
+}
+
+func NewExerciseRepository(db *sql.DB) *ExerciseRepository {
+	return &ExerciseRepository{db: db}
+}
+
+// FindByIDs returns the exercises with the given IDs keyed by ID. Unknown IDs are left out.
+func (r *ExerciseRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Exercise, error) {
+	const q = `
+SELECT id, name, body_part, primary_muscle, secondary_muscle, is_active, bodyweight_fraction, equipment, measurement
+FROM exercises
+WHERE id = ANY($1::uuid[])`
+
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = id.String()
+	}
+	rows, err := r.db.QueryContext(ctx, q, pq.Array(strIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := make(map[uuid.UUID]models.Exercise, len(ids))
+	for rows.Next() {
+		var e models.Exercise
+		if err := rows.Scan(&e.ID, &e.Name, &e.BodyPart, &e.PrimaryMuscle, &e.SecondaryMuscle, &e.IsActive, &e.BodyweightFraction, &e.Equipment, &e.Measurement); err != nil {
+			return nil, err
+		}
+		exercises[e.ID] = e
+	}
+	return exercises, rows.Err()
+}
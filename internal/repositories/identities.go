@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// FindUser returns the user linked to the provider subject, or sql.ErrNoRows when none is.
+func (r *IdentityRepository) FindUser(ctx context.Context, provider, subject string) (*models.User, error) {
+	const q = `
+SELECT ` + userColumns + `
+FROM users
+WHERE id = (SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2)`
+
+	return scanUser(r.db.QueryRowContext(ctx, q, provider, subject))
+}
+
+// Link attaches a provider identity to an existing user.
+func (r *IdentityRepository) Link(ctx context.Context, userID uuid.UUID, provider, subject, email string) error {
+	const q = `
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES ($1, $2, $3, NULLIF($4, ''))`
+
+	_, err := r.db.ExecContext(ctx, q, userID, provider, subject, email)
+	return err
+}
+
+// CreateUser creates a user and its first identity in one transaction. emailVerified marks the address
+// as verified when the provider vouches for it.
+func (r *IdentityRepository) CreateUser(ctx context.Context, email, passwordHash string, emailVerified bool, provider, subject string) (*models.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const create = `
+INSERT INTO users (email, password_hash, email_verified_at)
+VALUES ($1, $2, CASE WHEN $3 THEN NOW() END)
+RETURNING ` + userColumns
+
+	user, err := scanUser(tx.QueryRowContext(ctx, create, email, passwordHash, emailVerified))
+	if err != nil {
+		return nil, err
+	}
+
+	const link = `
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4)`
+
+	if _, err := tx.ExecContext(ctx, link, user.ID, provider, subject, email); err != nil {
+		return nil, err
+	}
+	return user, tx.Commit()
+}
+
+// ListByUser returns the identities linked to a user, oldest first.
+func (r *IdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error) {
+	const q = `
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []*models.UserIdentity
+	for rows.Next() {
+		var i models.UserIdentity
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Provider, &i.Subject, &i.Email, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, &i)
+	}
+	return identities, rows.Err()
+}
+
+// SaveLoginState stores the PKCE verifier and nonce of an authorization request under the hashed state.
+// Expired states left behind by abandoned logins are cleared out on the way.
+func (r *IdentityRepository) SaveLoginState(ctx context.Context, stateHash, provider, codeVerifier, nonce string, expiresAt time.Time) error {
+	const q = `
+WITH expired AS (
+    DELETE FROM oidc_login_states WHERE expires_at <= NOW()
+)
+INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, expires_at)
+VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.ExecContext(ctx, q, stateHash, provider, codeVerifier, nonce, expiresAt)
+	return err
+}
+
+// ConsumeLoginState deletes a pending authorization request and returns its verifier and nonce.
+// It returns sql.ErrNoRows when the state is unknown, expired, already used or belongs to another provider.
+func (r *IdentityRepository) ConsumeLoginState(ctx context.Context, stateHash, provider string) (string, string, error) {
+	const q = `
+DELETE FROM oidc_login_states
+WHERE state_hash = $1 AND provider = $2 AND expires_at > NOW()
+RETURNING code_verifier, nonce`
+
+	var verifier, nonce string
+	err := r.db.QueryRowContext(ctx, q, stateHash, provider).Scan(&verifier, &nonce)
+	return verifier, nonce, err
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityRepository_Users(t *testing.T) {
+	t.Run("creates user with identity and finds it", func(t *testing.T) {
+		repo := NewIdentityRepository(testDB)
+		ctx := context.Background()
+
+		user, err := repo.CreateUser(ctx, "social@example.com", "hash", true, "google", "sub-1")
+		require.NoError(t, err)
+		require.NotNil(t, user.EmailVerifiedAt)
+
+		found, err := repo.FindUser(ctx, "google", "sub-1")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, found.ID)
+
+		_, err = repo.FindUser(ctx, "github", "sub-1")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		truncateUsers(t)
+	})
+
+	t.Run("links identity to existing user once per provider", func(t *testing.T) {
+		repo := NewIdentityRepository(testDB)
+		users := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := users.Create(ctx, "link@example.com", "hash")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Link(ctx, user.ID, "google", "sub-2", "link@example.com"))
+		require.Error(t, repo.Link(ctx, user.ID, "google", "sub-3", ""))
+
+		identities, err := repo.ListByUser(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+		require.Equal(t, "sub-2", identities[0].Subject)
+		truncateUsers(t)
+	})
+}
+
+func TestIdentityRepository_LoginState(t *testing.T) {
+	t.Run("state is consumed once and only for its provider", func(t *testing.T) {
+		repo := NewIdentityRepository(testDB)
+		ctx := context.Background()
+		require.NoError(t, repo.SaveLoginState(ctx, "statehash", "google", "verifier", "nonce", time.Now().Add(time.Minute)))
+
+		_, _, err := repo.ConsumeLoginState(ctx, "statehash", "github")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		verifier, nonce, err := repo.ConsumeLoginState(ctx, "statehash", "google")
+		require.NoError(t, err)
+		require.Equal(t, "verifier", verifier)
+		require.Equal(t, "nonce", nonce)
+
+		_, _, err = repo.ConsumeLoginState(ctx, "statehash", "google")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("expired state is rejected", func(t *testing.T) {
+		repo := NewIdentityRepository(testDB)
+		ctx := context.Background()
+		require.NoError(t, repo.SaveLoginState(ctx, "expiredhash", "google", "verifier", "nonce", time.Now().Add(-time.Minute)))
+
+		_, _, err := repo.ConsumeLoginState(ctx, "expiredhash", "google")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("abandoned states are cleared by later logins", func(t *testing.T) {
+		repo := NewIdentityRepository(testDB)
+		ctx := context.Background()
+		require.NoError(t, repo.SaveLoginState(ctx, "abandonedhash", "google", "verifier", "nonce", time.Now().Add(-time.Minute)))
+		require.NoError(t, repo.SaveLoginState(ctx, "freshhash", "google", "verifier", "nonce", time.Now().Add(time.Minute)))
+
+		var n int
+		require.NoError(t, testDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM oidc_login_states WHERE state_hash = 'abandonedhash'`).Scan(&n))
+		require.Zero(t, n)
+	})
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type ReadinessRepository struct {
+	db *sql.DB
+}
+
+func NewReadinessRepository(db *sql.DB) *ReadinessRepository {
+	return &ReadinessRepository{db: db}
+}
+
+// Upsert stores the check-in for its day, replacing one already made that day.
+func (r *ReadinessRepository) Upsert(ctx context.Context, c *models.ReadinessCheckIn) (*models.ReadinessCheckIn, error) {
+	const q = `
+INSERT INTO readiness_checkins (user_id, day, sleep, soreness, stress)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, day) DO UPDATE
+SET sleep = EXCLUDED.sleep, soreness = EXCLUDED.soreness, stress = EXCLUDED.stress, created_at = NOW()
+RETURNING id, user_id, day, sleep, soreness, stress, created_at`
+
+	var out models.ReadinessCheckIn
+	err := r.db.QueryRowContext(ctx, q, c.UserID, c.Day.Format(time.DateOnly), c.Sleep, c.Soreness, c.Stress).
+		Scan(&out.ID, &out.UserID, &out.Day, &out.Sleep, &out.Soreness, &out.Stress, &out.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetForDay returns the user's check-in for a day. It returns sql.ErrNoRows when they did not check in.
+func (r *ReadinessRepository) GetForDay(ctx context.Context, userID uuid.UUID, day time.Time) (*models.ReadinessCheckIn, error) {
+	const q = `
+SELECT id, user_id, day, sleep, soreness, stress, created_at
+FROM readiness_checkins
+WHERE user_id = $1 AND day = $2`
+
+	var c models.ReadinessCheckIn
+	err := r.db.QueryRowContext(ctx, q, userID, day.Format(time.DateOnly)).
+		Scan(&c.ID, &c.UserID, &c.Day, &c.Sleep, &c.Soreness, &c.Stress, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListForUser returns every check-in of the user, oldest first.
+func (r *ReadinessRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]*models.ReadinessCheckIn, error) {
+	const q = `
+SELECT id, user_id, day, sleep, soreness, stress, created_at
+FROM readiness_checkins
+WHERE user_id = $1
+ORDER BY day`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkIns := []*models.ReadinessCheckIn{}
+	for rows.Next() {
+		var c models.ReadinessCheckIn
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Day, &c.Sleep, &c.Soreness, &c.Stress, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		checkIns = append(checkIns, &c)
+	}
+	return checkIns, rows.Err()
+}
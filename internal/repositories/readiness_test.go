@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessRepository(t *testing.T) {
+	ctx := context.Background()
+	users := NewUserRepository(testDB)
+	repo := NewReadinessRepository(testDB)
+	user, err := users.Create(ctx, "readiness@example.com", "hash")
+	require.NoError(t, err)
+	defer truncateUsers(t)
+	day := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("no check-in", func(t *testing.T) {
+		_, err := repo.GetForDay(ctx, user.ID, day)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("checking in again the same day replaces it", func(t *testing.T) {
+		_, err := repo.Upsert(ctx, &models.ReadinessCheckIn{UserID: user.ID, Day: day, Sleep: 2, Soreness: 4, Stress: 3})
+		require.NoError(t, err)
+		_, err = repo.Upsert(ctx, &models.ReadinessCheckIn{UserID: user.ID, Day: day, Sleep: 4, Soreness: 2, Stress: 1})
+		require.NoError(t, err)
+
+		c, err := repo.GetForDay(ctx, user.ID, day)
+		require.NoError(t, err)
+		require.Equal(t, 4, c.Sleep)
+		require.Equal(t, 1, c.Stress)
+		require.True(t, day.Equal(c.Day))
+
+		_, err = repo.GetForDay(ctx, user.ID, day.AddDate(0, 0, 1))
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("lists every check-in oldest first", func(t *testing.T) {
+		_, err := repo.Upsert(ctx, &models.ReadinessCheckIn{UserID: user.ID, Day: day.AddDate(0, 0, -1), Sleep: 3, Soreness: 3, Stress: 3})
+		require.NoError(t, err)
+
+		checkIns, err := repo.ListForUser(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, checkIns, 2)
+		require.True(t, day.AddDate(0, 0, -1).Equal(checkIns[0].Day))
+		require.True(t, day.Equal(checkIns[1].Day))
+	})
+
+	t.Run("rejects ratings out of range", func(t *testing.T) {
+		_, err := repo.Upsert(ctx, &models.ReadinessCheckIn{UserID: user.ID, Day: day, Sleep: 6, Soreness: 1, Stress: 1})
+		require.Error(t, err)
+	})
+}
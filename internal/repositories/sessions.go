@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/alexanderramin/kalistheniks/internal/models"
 	"github.com/google/uuid"
@@ -17,27 +18,151 @@ func NewSessionRepository(db *sql.DB) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
+// sessionColumns lists the sessions columns in the order scanSession expects.
+const sessionColumns = `id, user_id, performed_at, notes, session_type, status, started_at, finished_at, rescheduled_from,
+       missed_count`
+
+func scanSession(row *sql.Row) (*models.Session, error) {
+	var s models.Session
+	err := row.Scan(&s.ID, &s.UserID, &s.PerformedAt, &s.Notes, &s.SessionType, &s.Status, &s.StartedAt, &s.FinishedAt,
+		&s.RescheduledFrom, &s.MissedCount)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
 func (r *SessionRepository) Create(ctx context.Context, s *models.Session) (*models.Session, error) {
 	const q = `
-INSERT INTO sessions (user_id, performed_at, notes, session_type)
-VALUES ($1, $2, $3, $4)
-RETURNING id, user_id, performed_at, notes, session_type`
+INSERT INTO sessions (user_id, performed_at, notes, session_type, status, started_at)
+VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'completed'), $6)
+RETURNING ` + sessionColumns
+
+	created, err := scanSession(r.db.QueryRowContext(ctx, q, s.UserID, s.PerformedAt, s.Notes, s.SessionType, s.Status, s.StartedAt))
+	if err != nil {
+		return &models.Session{}, err
+	}
+	return created, nil
+}
+
+// CreateWithPlan stores a session together with its groups and planned sets in one transaction. The
+// groups keep the IDs they were given so the planned sets can refer to them.
+func (r *SessionRepository) CreateWithPlan(ctx context.Context, s *models.Session) (*models.Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const insertSession = `
+INSERT INTO sessions (user_id, performed_at, notes, session_type, status, started_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + sessionColumns
+
+	created, err := scanSession(tx.QueryRowContext(ctx, insertSession, s.UserID, s.PerformedAt, s.Notes, s.SessionType, s.Status, s.StartedAt))
+	if err != nil {
+		return nil, err
+	}
+
+	const insertGroup = `
+INSERT INTO set_groups (id, session_id, group_index, kind, rounds, rest_seconds)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + setGroupColumns
+
+	created.Groups = []models.SetGroup{}
+	for _, g := range s.Groups {
+		group, err := scanSetGroup(tx.QueryRowContext(ctx, insertGroup, g.ID, created.ID, g.GroupIndex, g.Kind, g.Rounds, g.RestSeconds))
+		if err != nil {
+			return nil, err
+		}
+		created.Groups = append(created.Groups, *group)
+	}
+
+	const insertPlanned = `
+INSERT INTO planned_sets (session_id, position, exercise_id, kind, reps, weight_kg, duration_seconds, distance_m,
+                          rest_seconds, group_id, round)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id`
+
+	created.Sets = []models.Set{}
+	created.PlannedSets = []models.PlannedSet{}
+	for _, p := range s.PlannedSets {
+		p.SessionID = created.ID
+		err := tx.QueryRowContext(ctx, insertPlanned, p.SessionID, p.Position, p.ExerciseID, p.Kind, p.Reps, p.WeightKG,
+			p.DurationSeconds, p.DistanceM, p.RestSeconds, p.GroupID, p.Round).Scan(&p.ID)
+		if err != nil {
+			return nil, err
+		}
+		created.PlannedSets = append(created.PlannedSets, p)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// StartSession moves a planned session to in progress. The start time also becomes the time the session
+// was performed. It returns sql.ErrNoRows when the user has no such planned session.
+func (r *SessionRepository) StartSession(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error) {
+	const q = `
+UPDATE sessions
+SET status = 'in_progress', started_at = $3, performed_at = $3
+WHERE id = $1 AND user_id = $2 AND status = 'planned'
+RETURNING ` + sessionColumns
+
+	return scanSession(r.db.QueryRowContext(ctx, q, sessionID, userID, at))
+}
+
+// FinishSession ends an in-progress session as completed or abandoned. It returns sql.ErrNoRows when the
+// user has no such session in progress.
+func (r *SessionRepository) FinishSession(ctx context.Context, userID, sessionID uuid.UUID, status string, at time.Time) (*models.Session, error) {
+	const q = `
+UPDATE sessions
+SET status = $3, finished_at = GREATEST($4, started_at)
+WHERE id = $1 AND user_id = $2 AND status = 'in_progress'
+RETURNING ` + sessionColumns
 
-	var created models.Session
-	err := r.db.QueryRowContext(ctx, q, s.UserID, s.PerformedAt, s.Notes, s.SessionType).
-		Scan(&created.ID, &created.UserID, &created.PerformedAt, &created.Notes, &created.SessionType)
-	return &created, err
+	return scanSession(r.db.QueryRowContext(ctx, q, sessionID, userID, status, at))
+}
+
+// CloseStaleSessions ends every session still in progress that started before the cutoff. Sessions with
+// sets are completed at their last set; sessions without any are abandoned.
+func (r *SessionRepository) CloseStaleSessions(ctx context.Context, startedBefore time.Time) (int64, error) {
+	const q = `
+UPDATE sessions s
+SET status = CASE WHEN last.at IS NULL THEN 'abandoned' ELSE 'completed' END,
+    finished_at = GREATEST(s.started_at, COALESCE(last.at, s.started_at))
+FROM (
+    SELECT ss.id, MAX(st.completed_at) AS at
+    FROM sessions ss
+    LEFT JOIN sets st ON st.session_id = ss.id
+    WHERE ss.status = 'in_progress' AND ss.started_at < $1
+    GROUP BY ss.id
+) last
+WHERE s.id = last.id`
+
+	res, err := r.db.ExecContext(ctx, q, startedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 func (r *SessionRepository) AddSet(ctx context.Context, set *models.Set) (*models.Set, error) {
 	const q = `
-INSERT INTO sets (session_id, exercise_id, set_index, reps, weight_kg, rpe)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, session_id, exercise_id, set_index, reps, weight_kg, rpe`
+INSERT INTO sets (session_id, exercise_id, set_index, kind, reps, weight_kg, rpe, duration_seconds, distance_m, group_id, round,
+                  completed_at, rest_seconds)
+VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), 'working'), $5, $6, $7, $8, $9, $10, $11, COALESCE($12, now()), $13)
+RETURNING id, session_id, exercise_id, set_index, kind, reps, weight_kg, rpe, duration_seconds, distance_m, group_id, round,
+          completed_at, rest_seconds`
 
+	var completedAt *time.Time
+	if !set.CompletedAt.IsZero() {
+		completedAt = &set.CompletedAt
+	}
 	var out models.Set
-	err := r.db.QueryRowContext(ctx, q, set.SessionID, set.ExerciseID, set.SetIndex, set.Reps, set.WeightKG, set.RPE).
-		Scan(&out.ID, &out.SessionID, &out.ExerciseID, &out.SetIndex, &out.Reps, &out.WeightKG, &out.RPE)
+	err := r.db.QueryRowContext(ctx, q, set.SessionID, set.ExerciseID, set.SetIndex, set.Kind, set.Reps, set.WeightKG, set.RPE, set.DurationSeconds, set.DistanceM, set.GroupID, set.Round, completedAt, set.RestSeconds).
+		Scan(&out.ID, &out.SessionID, &out.ExerciseID, &out.SetIndex, &out.Kind, &out.Reps, &out.WeightKG, &out.RPE, &out.DurationSeconds, &out.DistanceM, &out.GroupID, &out.Round, &out.CompletedAt, &out.RestSeconds)
 	return &out, err
 }
 
@@ -47,8 +172,9 @@ func (r *SessionRepository) ListWithSets(ctx context.Context, userID uuid.UUID)
 	}
 
 	const q = `
-SELECT s.id, s.user_id, s.performed_at, s.notes, s.session_type,
-       st.id, st.session_id, st.exercise_id, st.set_index, st.reps, st.weight_kg, st.rpe
+SELECT s.id, s.user_id, s.performed_at, s.notes, s.session_type, s.status, s.started_at, s.finished_at,
+       s.rescheduled_from, s.missed_count, st.id, st.session_id, st.exercise_id, st.set_index, st.kind, st.reps, st.weight_kg, st.rpe,
+       st.duration_seconds, st.distance_m, st.group_id, st.round, st.completed_at, st.rest_seconds
 FROM sessions s
 LEFT JOIN sets st ON st.session_id = s.id
 WHERE s.user_id = $1
@@ -69,13 +195,21 @@ ORDER BY s.performed_at DESC, st.set_index ASC`
 		var setSessionID sql.NullString
 		var exerciseID sql.NullString
 		var setIndex sql.NullInt64
+		var kind sql.NullString
 		var reps sql.NullInt64
 		var weight sql.NullFloat64
 		var rpe sql.NullInt64
+		var duration sql.NullInt64
+		var distance sql.NullFloat64
+		var groupID uuid.NullUUID
+		var round sql.NullInt64
+		var completedAt sql.NullTime
+		var rest sql.NullInt64
 
 		err = rows.Scan(
-			&s.ID, &s.UserID, &s.PerformedAt, &notes, &sessionType,
-			&setID, &setSessionID, &exerciseID, &setIndex, &reps, &weight, &rpe,
+			&s.ID, &s.UserID, &s.PerformedAt, &notes, &sessionType, &s.Status, &s.StartedAt, &s.FinishedAt,
+			&s.RescheduledFrom, &s.MissedCount, &setID, &setSessionID, &exerciseID, &setIndex, &kind, &reps, &weight, &rpe,
+			&duration, &distance, &groupID, &round, &completedAt, &rest,
 		)
 		if err != nil {
 			return nil, err
@@ -91,12 +225,19 @@ ORDER BY s.performed_at DESC, st.set_index ASC`
 		session, ok := sessions[s.ID.String()]
 		if !ok {
 			session = &models.Session{
-				ID:          s.ID,
-				UserID:      s.UserID,
-				PerformedAt: s.PerformedAt,
-				Notes:       s.Notes,
-				SessionType: s.SessionType,
-				Sets:        []models.Set{},
+				ID:              s.ID,
+				UserID:          s.UserID,
+				PerformedAt:     s.PerformedAt,
+				Notes:           s.Notes,
+				SessionType:     s.SessionType,
+				Status:          s.Status,
+				StartedAt:       s.StartedAt,
+				FinishedAt:      s.FinishedAt,
+				RescheduledFrom: s.RescheduledFrom,
+				MissedCount:     s.MissedCount,
+				Sets:            []models.Set{},
+				Groups:          []models.SetGroup{},
+				PlannedSets:     []models.PlannedSet{},
 			}
 			sessions[s.ID.String()] = session
 		}
@@ -118,69 +259,660 @@ ORDER BY s.performed_at DESC, st.set_index ASC`
 				}
 
 				set := models.Set{
-					ID:         idParsed,
-					SessionID:  sid,
-					ExerciseID: eid,
-					SetIndex:   int(setIndex.Int64),
-					Reps:       int(reps.Int64),
-					WeightKG:   weight.Float64,
+					ID:          idParsed,
+					SessionID:   sid,
+					ExerciseID:  eid,
+					SetIndex:    int(setIndex.Int64),
+					Kind:        kind.String,
+					Reps:        int(reps.Int64),
+					WeightKG:    weight.Float64,
+					CompletedAt: completedAt.Time,
 				}
 				if rpe.Valid {
 					value := int(rpe.Int64)
 					set.RPE = &value
 				}
+				if duration.Valid {
+					value := int(duration.Int64)
+					set.DurationSeconds = &value
+				}
+				if distance.Valid {
+					set.DistanceM = &distance.Float64
+				}
+				if groupID.Valid {
+					set.GroupID = &groupID.UUID
+				}
+				if round.Valid {
+					value := int(round.Int64)
+					set.Round = &value
+				}
+				if rest.Valid {
+					value := int(rest.Int64)
+					set.RestSeconds = &value
+				}
 				session.Sets = append(session.Sets, set)
 			}
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	const groupsQuery = `
+SELECT g.id, g.session_id, g.group_index, g.kind, g.rounds, g.rest_seconds
+FROM set_groups g
+JOIN sessions s ON g.session_id = s.id
+WHERE s.user_id = $1
+ORDER BY g.group_index`
+
+	groups, err := r.listGroups(ctx, groupsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if session, ok := sessions[g.SessionID.String()]; ok {
+			session.Groups = append(session.Groups, g)
+		}
+	}
+
+	const plannedQuery = `
+SELECT p.id, p.session_id, p.position, p.exercise_id, p.kind, p.reps, p.weight_kg, p.duration_seconds, p.distance_m,
+       p.rest_seconds, p.group_id, p.round
+FROM planned_sets p
+JOIN sessions s ON p.session_id = s.id
+WHERE s.user_id = $1
+ORDER BY p.position`
+
+	planned, err := r.listPlannedSets(ctx, plannedQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range planned {
+		if session, ok := sessions[p.SessionID.String()]; ok {
+			session.PlannedSets = append(session.PlannedSets, p)
+		}
+	}
+
 	result := make([]*models.Session, 0, len(sessions))
 	for _, sess := range sessions {
 		result = append(result, sess)
 	}
-	return result, rows.Err()
+	return result, nil
+}
+
+// GetWithSets returns one of the user's sessions with its sets in set order. It returns sql.ErrNoRows
+// when the session does not exist or belongs to someone else.
+func (r *SessionRepository) GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	const sessionQuery = `
+SELECT ` + sessionColumns + `
+FROM sessions
+WHERE id = $1 AND user_id = $2`
+
+	s, err := scanSession(r.db.QueryRowContext(ctx, sessionQuery, sessionID, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	const setsQuery = `
+SELECT ` + setColumns + `
+FROM sets
+WHERE session_id = $1
+ORDER BY set_index, created_at`
+
+	s.Sets, err = r.listSets(ctx, setsQuery, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	const groupsQuery = `
+SELECT ` + setGroupColumns + `
+FROM set_groups
+WHERE session_id = $1
+ORDER BY group_index`
+
+	s.Groups, err = r.listGroups(ctx, groupsQuery, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	const plannedQuery = `
+SELECT ` + plannedSetColumns + `
+FROM planned_sets
+WHERE session_id = $1
+ORDER BY position`
+
+	s.PlannedSets, err = r.listPlannedSets(ctx, plannedQuery, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListPlannedSessions returns the user's sessions performed since the given time that were planned set
+// by set and have since been completed or abandoned, oldest first, with their sets and planned sets.
+func (r *SessionRepository) ListPlannedSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error) {
+	const sessionsQuery = `
+SELECT ` + sessionColumns + `
+FROM sessions s
+WHERE s.user_id = $1
+  AND s.performed_at >= $2
+  AND s.status IN ('completed', 'abandoned')
+  AND EXISTS (SELECT 1 FROM planned_sets p WHERE p.session_id = s.id)
+ORDER BY s.performed_at, s.id`
+
+	rows, err := r.db.QueryContext(ctx, sessionsQuery, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions, err := scanSessions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return sessions, nil
+	}
+	byID := make(map[uuid.UUID]*models.Session, len(sessions))
+	ids := make([]uuid.UUID, len(sessions))
+	for i, s := range sessions {
+		s.Sets = []models.Set{}
+		s.PlannedSets = []models.PlannedSet{}
+		byID[s.ID] = s
+		ids[i] = s.ID
+	}
+
+	const setsQuery = `
+SELECT ` + setColumns + `
+FROM sets
+WHERE session_id = ANY($1::uuid[])
+ORDER BY set_index, created_at`
+
+	sets, err := r.listSets(ctx, setsQuery, uuidArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		byID[set.SessionID].Sets = append(byID[set.SessionID].Sets, set)
+	}
+
+	if err := r.attachPlannedSets(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ListUpcomingPlanned returns the user's planned sessions scheduled from the given time on, soonest first,
+// with their planned sets.
+func (r *SessionRepository) ListUpcomingPlanned(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Session, error) {
+	const q = `
+SELECT ` + sessionColumns + `
+FROM sessions
+WHERE user_id = $1 AND status = 'planned' AND performed_at >= $2
+ORDER BY performed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, q, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions, err := scanSessions(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		s.PlannedSets = []models.PlannedSet{}
+	}
+	if err := r.attachPlannedSets(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ListCompleted returns the user's completed sessions without their sets, oldest first.
+func (r *SessionRepository) ListCompleted(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	const q = `
+SELECT ` + sessionColumns + `
+FROM sessions
+WHERE user_id = $1 AND status = 'completed'
+ORDER BY performed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSessions(rows)
+}
+
+// ListExerciseHistory returns the user's sessions in progress or completed with sets of an exercise,
+// oldest first, each with only the sets of that exercise.
+func (r *SessionRepository) ListExerciseHistory(ctx context.Context, userID, exerciseID uuid.UUID) ([]*models.Session, error) {
+	const sessionsQuery = `
+SELECT ` + sessionColumns + `
+FROM sessions s
+WHERE s.user_id = $1
+  AND s.status IN ('in_progress', 'completed')
+  AND EXISTS (SELECT 1 FROM sets st WHERE st.session_id = s.id AND st.exercise_id = $2)
+ORDER BY s.performed_at, s.id`
+
+	rows, err := r.db.QueryContext(ctx, sessionsQuery, userID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions, err := scanSessions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return sessions, nil
+	}
+	byID := make(map[uuid.UUID]*models.Session, len(sessions))
+	ids := make([]uuid.UUID, len(sessions))
+	for i, s := range sessions {
+		s.Sets = []models.Set{}
+		byID[s.ID] = s
+		ids[i] = s.ID
+	}
+
+	const setsQuery = `
+SELECT ` + setColumns + `
+FROM sets
+WHERE session_id = ANY($1::uuid[]) AND exercise_id = $2
+ORDER BY set_index, created_at`
+
+	sets, err := r.listSets(ctx, setsQuery, uuidArray(ids), exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		byID[set.SessionID].Sets = append(byID[set.SessionID].Sets, set)
+	}
+	return sessions, nil
+}
+
+// ListTrainedSince returns the user's sessions in progress or completed since the given time, oldest
+// first, with their sets.
+func (r *SessionRepository) ListTrainedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error) {
+	const sessionsQuery = `
+SELECT ` + sessionColumns + `
+FROM sessions s
+WHERE s.user_id = $1
+  AND s.performed_at >= $2
+  AND s.status IN ('in_progress', 'completed')
+ORDER BY s.performed_at, s.id`
+
+	rows, err := r.db.QueryContext(ctx, sessionsQuery, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions, err := scanSessions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return sessions, nil
+	}
+	byID := make(map[uuid.UUID]*models.Session, len(sessions))
+	ids := make([]uuid.UUID, len(sessions))
+	for i, s := range sessions {
+		s.Sets = []models.Set{}
+		byID[s.ID] = s
+		ids[i] = s.ID
+	}
+
+	const setsQuery = `
+SELECT ` + setColumns + `
+FROM sets
+WHERE session_id = ANY($1::uuid[])
+ORDER BY set_index, created_at`
+
+	sets, err := r.listSets(ctx, setsQuery, uuidArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		byID[set.SessionID].Sets = append(byID[set.SessionID].Sets, set)
+	}
+	return sessions, nil
+}
+
+// attachPlannedSets loads the planned sets of the given sessions into them, in plan order.
+func (r *SessionRepository) attachPlannedSets(ctx context.Context, sessions []*models.Session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Session, len(sessions))
+	ids := make([]uuid.UUID, len(sessions))
+	for i, s := range sessions {
+		byID[s.ID] = s
+		ids[i] = s.ID
+	}
+
+	const q = `
+SELECT ` + plannedSetColumns + `
+FROM planned_sets
+WHERE session_id = ANY($1::uuid[])
+ORDER BY position`
+
+	planned, err := r.listPlannedSets(ctx, q, uuidArray(ids))
+	if err != nil {
+		return err
+	}
+	for _, p := range planned {
+		byID[p.SessionID].PlannedSets = append(byID[p.SessionID].PlannedSets, p)
+	}
+	return nil
+}
+
+// AddPlannedSet appends a planned set to the end of the session's plan.
+func (r *SessionRepository) AddPlannedSet(ctx context.Context, p *models.PlannedSet) (*models.PlannedSet, error) {
+	const q = `
+INSERT INTO planned_sets (session_id, position, exercise_id, kind, reps, weight_kg, duration_seconds, distance_m,
+                          rest_seconds, group_id, round)
+VALUES ($1, (SELECT COALESCE(MAX(position) + 1, 0) FROM planned_sets WHERE session_id = $1), $2, $3, $4, $5, $6, $7,
+        $8, $9, $10)
+RETURNING ` + plannedSetColumns
+
+	var out models.PlannedSet
+	err := r.db.QueryRowContext(ctx, q, p.SessionID, p.ExerciseID, p.Kind, p.Reps, p.WeightKG, p.DurationSeconds,
+		p.DistanceM, p.RestSeconds, p.GroupID, p.Round).
+		Scan(&out.ID, &out.SessionID, &out.Position, &out.ExerciseID, &out.Kind, &out.Reps, &out.WeightKG,
+			&out.DurationSeconds, &out.DistanceM, &out.RestSeconds, &out.GroupID, &out.Round)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBetween returns the user's sessions performed, or planned, from from up to but not including to,
+// in time order and without their sets.
+func (r *SessionRepository) ListBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Session, error) {
+	const q = `
+SELECT ` + sessionColumns + `
+FROM sessions
+WHERE user_id = $1 AND performed_at >= $2 AND performed_at < $3
+ORDER BY performed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, q, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSessions(rows)
+}
+
+// ListOverduePlanned returns every user's planned sessions scheduled before the cutoff, oldest first.
+func (r *SessionRepository) ListOverduePlanned(ctx context.Context, before time.Time) ([]*models.Session, error) {
+	const q = `
+SELECT ` + sessionColumns + `
+FROM sessions
+WHERE status = 'planned' AND performed_at < $1
+ORDER BY user_id, performed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, q, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSessions(rows)
+}
+
+// Reschedule moves a planned session to a new time, remembering the time it was first scheduled for. It
+// returns sql.ErrNoRows when the user has no such planned session.
+func (r *SessionRepository) Reschedule(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error) {
+	const q = `
+UPDATE sessions
+SET rescheduled_from = COALESCE(rescheduled_from, performed_at),
+    performed_at = $3
+WHERE id = $1 AND user_id = $2 AND status = 'planned'
+RETURNING ` + sessionColumns
+
+	return scanSession(r.db.QueryRowContext(ctx, q, sessionID, userID, at))
+}
+
+// RescheduleMissed moves a missed session like Reschedule and counts the miss, but only while it is still
+// planned for plannedAt: a session moved or started since it was found missed is left alone and
+// sql.ErrNoRows returned, so concurrent runs count each miss once.
+func (r *SessionRepository) RescheduleMissed(ctx context.Context, userID, sessionID uuid.UUID, plannedAt, at time.Time) (*models.Session, error) {
+	const q = `
+UPDATE sessions
+SET rescheduled_from = COALESCE(rescheduled_from, performed_at),
+    performed_at = $4,
+    missed_count = missed_count + 1
+WHERE id = $1 AND user_id = $2 AND status = 'planned' AND performed_at = $3
+RETURNING ` + sessionColumns
+
+	return scanSession(r.db.QueryRowContext(ctx, q, sessionID, userID, plannedAt, at))
+}
+
+// AbandonPlanned gives up on a missed session that is still planned for plannedAt. It returns
+// sql.ErrNoRows when the user has no such planned session, or it was moved since.
+func (r *SessionRepository) AbandonPlanned(ctx context.Context, userID, sessionID uuid.UUID, plannedAt, at time.Time) (*models.Session, error) {
+	const q = `
+UPDATE sessions
+SET status = 'abandoned', finished_at = $4
+WHERE id = $1 AND user_id = $2 AND status = 'planned' AND performed_at = $3
+RETURNING ` + sessionColumns
+
+	return scanSession(r.db.QueryRowContext(ctx, q, sessionID, userID, plannedAt, at))
+}
+
+// CreateGroup adds a set group at the end of the session's groups.
+func (r *SessionRepository) CreateGroup(ctx context.Context, g *models.SetGroup) (*models.SetGroup, error) {
+	const q = `
+INSERT INTO set_groups (session_id, group_index, kind, rounds, rest_seconds)
+VALUES ($1, (SELECT COALESCE(MAX(group_index) + 1, 0) FROM set_groups WHERE session_id = $1), $2, $3, $4)
+RETURNING ` + setGroupColumns
+
+	return scanSetGroup(r.db.QueryRowContext(ctx, q, g.SessionID, g.Kind, g.Rounds, g.RestSeconds))
+}
+
+// GetGroup returns a group of the given session, or sql.ErrNoRows when the session has no such group.
+func (r *SessionRepository) GetGroup(ctx context.Context, sessionID, groupID uuid.UUID) (*models.SetGroup, error) {
+	const q = `
+SELECT ` + setGroupColumns + `
+FROM set_groups
+WHERE id = $1 AND session_id = $2`
+
+	return scanSetGroup(r.db.QueryRowContext(ctx, q, groupID, sessionID))
 }
 
+func scanSessions(rows *sql.Rows) ([]*models.Session, error) {
+	sessions := []*models.Session{}
+	for rows.Next() {
+		var s models.Session
+		err := rows.Scan(&s.ID, &s.UserID, &s.PerformedAt, &s.Notes, &s.SessionType, &s.Status, &s.StartedAt, &s.FinishedAt,
+			&s.RescheduledFrom, &s.MissedCount)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, rows.Err()
+}
+
+// setGroupColumns lists the set_groups columns in the order scanSetGroup and listGroups expect.
+const setGroupColumns = `id, session_id, group_index, kind, rounds, rest_seconds`
+
+func scanSetGroup(row *sql.Row) (*models.SetGroup, error) {
+	var g models.SetGroup
+	if err := row.Scan(&g.ID, &g.SessionID, &g.GroupIndex, &g.Kind, &g.Rounds, &g.RestSeconds); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *SessionRepository) listGroups(ctx context.Context, q string, arg any) ([]models.SetGroup, error) {
+	rows, err := r.db.QueryContext(ctx, q, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.SetGroup{}
+	for rows.Next() {
+		var g models.SetGroup
+		if err := rows.Scan(&g.ID, &g.SessionID, &g.GroupIndex, &g.Kind, &g.Rounds, &g.RestSeconds); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// setColumns lists the sets columns in the order listSets expects.
+const setColumns = `id, session_id, exercise_id, set_index, kind, reps, weight_kg, rpe, duration_seconds, distance_m,
+       group_id, round, completed_at, rest_seconds`
+
+func (r *SessionRepository) listSets(ctx context.Context, q string, args ...any) ([]models.Set, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []models.Set{}
+	for rows.Next() {
+		var set models.Set
+		if err := rows.Scan(&set.ID, &set.SessionID, &set.ExerciseID, &set.SetIndex, &set.Kind, &set.Reps, &set.WeightKG, &set.RPE, &set.DurationSeconds, &set.DistanceM, &set.GroupID, &set.Round, &set.CompletedAt, &set.RestSeconds); err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	return sets, rows.Err()
+}
+
+// plannedSetColumns lists the planned_sets columns in the order listPlannedSets expects.
+const plannedSetColumns = `id, session_id, position, exercise_id, kind, reps, weight_kg, duration_seconds, distance_m,
+       rest_seconds, group_id, round`
+
+func (r *SessionRepository) listPlannedSets(ctx context.Context, q string, arg any) ([]models.PlannedSet, error) {
+	rows, err := r.db.QueryContext(ctx, q, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	planned := []models.PlannedSet{}
+	for rows.Next() {
+		var p models.PlannedSet
+		err := rows.Scan(&p.ID, &p.SessionID, &p.Position, &p.ExerciseID, &p.Kind, &p.Reps, &p.WeightKG, &p.DurationSeconds,
+			&p.DistanceM, &p.RestSeconds, &p.GroupID, &p.Round)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, p)
+	}
+	return planned, rows.Err()
+}
+
+// GetLastSet returns the user's most recent working set of a session in progress or completed; warm-ups
+// and other kinds are skipped so they do not steer progression.
 func (r *SessionRepository) GetLastSet(ctx context.Context, userID uuid.UUID) (*models.Set, error) {
 	const q = `
-SELECT st.id, st.session_id, st.exercise_id, st.set_index, st.reps, st.weight_kg, st.rpe
+SELECT st.id, st.session_id, st.exercise_id, st.set_index, st.kind, st.reps, st.weight_kg, st.rpe, st.duration_seconds, st.distance_m,
+       st.group_id, st.round, st.completed_at, st.rest_seconds
 FROM sets st
 JOIN sessions s ON st.session_id = s.id
-WHERE s.user_id = $1
-ORDER BY st.created_at DESC NULLS LAST, st.set_index DESC
+WHERE s.user_id = $1 AND st.kind = 'working'
+  AND s.status IN ('in_progress', 'completed')
+ORDER BY st.completed_at DESC, st.set_index DESC
 LIMIT 1`
 
 	var set models.Set
 	err := r.db.QueryRowContext(ctx, q, userID).
-		Scan(&set.ID, &set.SessionID, &set.ExerciseID, &set.SetIndex, &set.Reps, &set.WeightKG, &set.RPE)
+		Scan(&set.ID, &set.SessionID, &set.ExerciseID, &set.SetIndex, &set.Kind, &set.Reps, &set.WeightKG, &set.RPE, &set.DurationSeconds, &set.DistanceM, &set.GroupID, &set.Round, &set.CompletedAt, &set.RestSeconds)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, err
 	}
 	return &set, err
 }
 
+// GetLastExerciseSet returns the user's most recent working set of an exercise in a session in progress or
+// completed, or sql.ErrNoRows when they have never trained it.
+func (r *SessionRepository) GetLastExerciseSet(ctx context.Context, userID, exerciseID uuid.UUID) (*models.Set, error) {
+	const q = `
+SELECT st.id, st.session_id, st.exercise_id, st.set_index, st.kind, st.reps, st.weight_kg, st.rpe, st.duration_seconds, st.distance_m,
+       st.group_id, st.round, st.completed_at, st.rest_seconds
+FROM sets st
+JOIN sessions s ON st.session_id = s.id
+WHERE s.user_id = $1 AND st.exercise_id = $2 AND st.kind = 'working'
+  AND s.status IN ('in_progress', 'completed')
+ORDER BY st.completed_at DESC, st.set_index DESC
+LIMIT 1`
+
+	var set models.Set
+	err := r.db.QueryRowContext(ctx, q, userID, exerciseID).
+		Scan(&set.ID, &set.SessionID, &set.ExerciseID, &set.SetIndex, &set.Kind, &set.Reps, &set.WeightKG, &set.RPE, &set.DurationSeconds, &set.DistanceM, &set.GroupID, &set.Round, &set.CompletedAt, &set.RestSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// GetLastSession returns the user's most recent session that was actually trained, skipping planned and
+// abandoned ones.
 func (r *SessionRepository) GetLastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
 	const q = `
-SELECT id, user_id, performed_at, notes, session_type
+SELECT ` + sessionColumns + `
 FROM sessions
-WHERE user_id = $1
+WHERE user_id = $1 AND status IN ('in_progress', 'completed')
 ORDER BY performed_at DESC
 LIMIT 1`
 
-	var s models.Session
-	var notes sql.NullString
-	var sessionType sql.NullString
-	err := r.db.QueryRowContext(ctx, q, userID).Scan(&s.ID, &s.UserID, &s.PerformedAt, &notes, &sessionType)
-	if errors.Is(err, sql.ErrNoRows) {
+	return scanSession(r.db.QueryRowContext(ctx, q, userID))
+}
+
+// ListExercisesForUser returns the exercises the user has logged at least one set of, by name.
+func (r *SessionRepository) ListExercisesForUser(ctx context.Context, userID uuid.UUID) ([]models.Exercise, error) {
+	const q = `
+SELECT e.id, e.name, e.body_part, e.primary_muscle, e.secondary_muscle, e.is_active, e.bodyweight_fraction, e.equipment, e.measurement
+FROM exercises e
+WHERE EXISTS (
+    SELECT 1
+    FROM sets st
+    JOIN sessions s ON st.session_id = s.id
+    WHERE st.exercise_id = e.id AND s.user_id = $1
+)
+ORDER BY e.name`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
 		return nil, err
 	}
-	if notes.Valid {
-		s.Notes = &notes.String
+	defer rows.Close()
+
+	var exercises []models.Exercise
+	for rows.Next() {
+		var e models.Exercise
+		if err := rows.Scan(&e.ID, &e.Name, &e.BodyPart, &e.PrimaryMuscle, &e.SecondaryMuscle, &e.IsActive, &e.BodyweightFraction, &e.Equipment, &e.Measurement); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, e)
 	}
-	if sessionType.Valid {
-		s.SessionType = &sessionType.String
+	return exercises, rows.Err()
+}
+
+// GetStatus returns the status of one of the user's sessions, or sql.ErrNoRows when the user has no
+// such session.
+func (r *SessionRepository) GetStatus(ctx context.Context, userID, sessionID uuid.UUID) (string, error) {
+	const q = `
+SELECT status
+FROM sessions
+WHERE id = $1 AND user_id = $2`
+
+	var status string
+	if err := r.db.QueryRowContext(ctx, q, sessionID, userID).Scan(&status); err != nil {
+		return "", err
 	}
-	return &s, err
+	return status, nil
 }
 
 func (r *SessionRepository) SessionBelongsToUser(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
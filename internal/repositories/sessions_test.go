@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -94,6 +95,31 @@ func (s *SessionRepositorySuite) TestSessionRepository_AddSet() {
 		require.Equal(t, 0.0, addedSet.WeightKG)
 		require.NotNil(t, addedSet.RPE)
 		require.Equal(t, 8, *addedSet.RPE)
+		require.Nil(t, addedSet.DurationSeconds)
+		require.Nil(t, addedSet.DistanceM)
+	})
+
+	s.T().Run("stores duration and distance", func(t *testing.T) {
+		createdSession, err := s.sessionRepo.Create(context.Background(), &models.Session{PerformedAt: time.Now().UTC(), UserID: s.user.ID})
+		require.NoError(t, err)
+		seconds := 95
+		meters := 40.5
+		addedSet, err := s.sessionRepo.AddSet(context.Background(), &models.Set{
+			SessionID:       createdSession.ID,
+			ExerciseID:      s.exerciseID,
+			DurationSeconds: &seconds,
+			DistanceM:       &meters,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, addedSet.Reps)
+		require.Equal(t, 95, *addedSet.DurationSeconds)
+		require.Equal(t, 40.5, *addedSet.DistanceM)
+
+		got, err := s.sessionRepo.GetWithSets(context.Background(), s.user.ID, createdSession.ID)
+		require.NoError(t, err)
+		require.Len(t, got.Sets, 1)
+		require.Equal(t, 95, *got.Sets[0].DurationSeconds)
+		require.Equal(t, 40.5, *got.Sets[0].DistanceM)
 	})
 
 	s.T().Run("invalid session ID raises error", func(t *testing.T) {
@@ -204,6 +230,43 @@ func (s *SessionRepositorySuite) TestSessionRepository_GetLastSet() {
 		require.NoError(t, err)
 		require.Equal(t, 8, lastSet.Reps)
 		require.Equal(t, 1, lastSet.SetIndex)
+		require.Equal(t, models.SetKindWorking, lastSet.Kind)
+	})
+
+	s.T().Run("skips sets that are not working sets", func(t *testing.T) {
+		s.truncateSessions()
+		createdSession, err := s.sessionRepo.Create(context.Background(), &models.Session{PerformedAt: time.Now().UTC(), UserID: s.user.ID})
+		require.NoError(t, err)
+		for i, kind := range []string{models.SetKindWorking, models.SetKindBackoff, models.SetKindWarmup} {
+			_, err = s.sessionRepo.AddSet(context.Background(), &models.Set{
+				SessionID:  createdSession.ID,
+				ExerciseID: s.exerciseID,
+				SetIndex:   i,
+				Kind:       kind,
+				Reps:       5 + i,
+			})
+			require.NoError(t, err)
+		}
+
+		lastSet, err := s.sessionRepo.GetLastSet(context.Background(), s.user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, lastSet.SetIndex)
+		require.Equal(t, models.SetKindWorking, lastSet.Kind)
+	})
+
+	s.T().Run("a set logged late goes by when it was completed", func(t *testing.T) {
+		s.truncateSessions()
+		createdSession, err := s.sessionRepo.Create(context.Background(), &models.Session{PerformedAt: time.Now().UTC(), UserID: s.user.ID})
+		require.NoError(t, err)
+		_, err = s.sessionRepo.AddSet(context.Background(), &models.Set{SessionID: createdSession.ID, ExerciseID: s.exerciseID, SetIndex: 1, Reps: 8})
+		require.NoError(t, err)
+		_, err = s.sessionRepo.AddSet(context.Background(), &models.Set{SessionID: createdSession.ID, ExerciseID: s.exerciseID, SetIndex: 0, Reps: 10,
+			CompletedAt: time.Now().UTC().Add(-time.Hour)})
+		require.NoError(t, err)
+
+		lastSet, err := s.sessionRepo.GetLastSet(context.Background(), s.user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 8, lastSet.Reps)
 	})
 
 	s.T().Run("no sets returns nil", func(t *testing.T) {
@@ -291,12 +354,487 @@ func (s *SessionRepositorySuite) TestSessionRepository_SessionBelongsToUser() {
 	})
 }
 
+func (s *SessionRepositorySuite) TestSessionRepository_GetStatus() {
+	created, err := s.sessionRepo.Create(s.ctx, &models.Session{
+		PerformedAt: time.Now().UTC(),
+		UserID:      s.user.ID,
+		Status:      models.SessionPlanned,
+	})
+	s.Require().NoError(err)
+
+	status, err := s.sessionRepo.GetStatus(s.ctx, s.user.ID, created.ID)
+	s.Require().NoError(err)
+	s.Equal(models.SessionPlanned, status)
+
+	_, err = s.sessionRepo.GetStatus(s.ctx, uuid.New(), created.ID)
+	s.ErrorIs(err, sql.ErrNoRows)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_ListExercisesForUser() {
+	s.truncateSessions()
+	exercises, err := s.sessionRepo.ListExercisesForUser(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(exercises)
+
+	session, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: time.Now().UTC()})
+	s.Require().NoError(err)
+	for i := 0; i < 2; i++ {
+		_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, SetIndex: i, Reps: 10})
+		s.Require().NoError(err)
+	}
+
+	exercises, err = s.sessionRepo.ListExercisesForUser(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(exercises, 1, "each exercise is listed once")
+	s.Require().Equal("push-ups", exercises[0].Name)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_GetWithSets() {
+	s.truncateSessions()
+	session, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: time.Now().UTC()})
+	s.Require().NoError(err)
+	for _, i := range []int{1, 0} {
+		_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, SetIndex: i, Reps: 10})
+		s.Require().NoError(err)
+	}
+
+	got, err := s.sessionRepo.GetWithSets(s.ctx, s.user.ID, session.ID)
+	s.Require().NoError(err)
+	s.Require().Len(got.Sets, 2)
+	s.Require().Equal(0, got.Sets[0].SetIndex)
+
+	_, err = s.sessionRepo.GetWithSets(s.ctx, uuid.New(), session.ID)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "other users cannot read the session")
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_SetGroups() {
+	s.truncateSessions()
+	session, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: time.Now().UTC()})
+	s.Require().NoError(err)
+	rounds, rest := 3, 60
+
+	first, err := s.sessionRepo.CreateGroup(s.ctx, &models.SetGroup{SessionID: session.ID, Kind: models.GroupCircuit, Rounds: &rounds, RestSeconds: &rest})
+	s.Require().NoError(err)
+	s.Require().Equal(0, first.GroupIndex)
+	second, err := s.sessionRepo.CreateGroup(s.ctx, &models.SetGroup{SessionID: session.ID, Kind: models.GroupAMRAP})
+	s.Require().NoError(err)
+	s.Require().Equal(1, second.GroupIndex, "groups are appended")
+	s.Require().Nil(second.Rounds)
+
+	round := 2
+	set, err := s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, Reps: 8, GroupID: &first.ID, Round: &round})
+	s.Require().NoError(err)
+	s.Require().Equal(first.ID, *set.GroupID)
+	s.Require().Equal(2, *set.Round)
+
+	got, err := s.sessionRepo.GetGroup(s.ctx, session.ID, first.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(60, *got.RestSeconds)
+	_, err = s.sessionRepo.GetGroup(s.ctx, uuid.New(), first.ID)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "groups belong to their session")
+
+	detail, err := s.sessionRepo.GetWithSets(s.ctx, s.user.ID, session.ID)
+	s.Require().NoError(err)
+	s.Require().Len(detail.Groups, 2)
+	s.Require().Equal(first.ID, *detail.Sets[0].GroupID)
+
+	sessions, err := s.sessionRepo.ListWithSets(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Require().Len(sessions[0].Groups, 2)
+	s.Require().Equal(2, *sessions[0].Sets[0].Round)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_Lifecycle() {
+	s.truncateSessions()
+	planned, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: time.Now().UTC(), Status: models.SessionPlanned})
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionPlanned, planned.Status)
+	s.Require().Nil(planned.StartedAt)
+
+	_, err = s.sessionRepo.GetLastSession(s.ctx, s.user.ID)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "planned sessions are not training history")
+	_, err = s.sessionRepo.FinishSession(s.ctx, s.user.ID, planned.ID, models.SessionCompleted, time.Now().UTC())
+	s.Require().ErrorIs(err, sql.ErrNoRows, "a planned session cannot be finished")
+
+	startedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	started, err := s.sessionRepo.StartSession(s.ctx, s.user.ID, planned.ID, startedAt)
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionInProgress, started.Status)
+	s.Require().True(startedAt.Equal(*started.StartedAt))
+	s.Require().True(startedAt.Equal(started.PerformedAt))
+	_, err = s.sessionRepo.StartSession(s.ctx, s.user.ID, planned.ID, startedAt)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "a session starts once")
+
+	finished, err := s.sessionRepo.FinishSession(s.ctx, s.user.ID, planned.ID, models.SessionAbandoned, startedAt.Add(-time.Minute))
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionAbandoned, finished.Status)
+	s.Require().True(startedAt.Equal(*finished.FinishedAt), "a session never finishes before it started")
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_CloseStaleSessions() {
+	s.truncateSessions()
+	longAgo := time.Now().UTC().Add(-12 * time.Hour)
+	withSets, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: longAgo, Status: models.SessionInProgress, StartedAt: &longAgo})
+	s.Require().NoError(err)
+	lastCompleted := longAgo.Add(40 * time.Minute).Truncate(time.Second)
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: withSets.ID, ExerciseID: s.exerciseID, SetIndex: 1, Reps: 10, CompletedAt: lastCompleted})
+	s.Require().NoError(err)
+	// logged after the fact, so inserted last but completed first
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: withSets.ID, ExerciseID: s.exerciseID, SetIndex: 0, Reps: 10, CompletedAt: longAgo.Add(20 * time.Minute)})
+	s.Require().NoError(err)
+	empty, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: longAgo, Status: models.SessionInProgress, StartedAt: &longAgo})
+	s.Require().NoError(err)
+	now := time.Now().UTC()
+	fresh, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now, Status: models.SessionInProgress, StartedAt: &now})
+	s.Require().NoError(err)
+
+	closed, err := s.sessionRepo.CloseStaleSessions(s.ctx, now.Add(-6*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Equal(int64(2), closed)
+
+	got, err := s.sessionRepo.GetWithSets(s.ctx, s.user.ID, withSets.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionCompleted, got.Status)
+	s.Require().True(got.FinishedAt.Equal(lastCompleted), "completed at the last set")
+	got, err = s.sessionRepo.GetWithSets(s.ctx, s.user.ID, empty.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionAbandoned, got.Status)
+	got, err = s.sessionRepo.GetWithSets(s.ctx, s.user.ID, fresh.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionInProgress, got.Status)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_SetCompletionAndRest() {
+	s.truncateSessions()
+	session, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: time.Now().UTC()})
+	s.Require().NoError(err)
+
+	completed := time.Now().UTC().Add(-10 * time.Minute).Truncate(time.Second)
+	rest := 120
+	set, err := s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, Reps: 5, CompletedAt: completed, RestSeconds: &rest})
+	s.Require().NoError(err)
+	s.Require().True(completed.Equal(set.CompletedAt))
+	s.Require().Equal(120, *set.RestSeconds)
+
+	untimed, err := s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, SetIndex: 1, Reps: 5})
+	s.Require().NoError(err)
+	s.Require().WithinDuration(time.Now(), untimed.CompletedAt, time.Minute, "defaults to the insert time")
+	s.Require().Nil(untimed.RestSeconds)
+
+	detail, err := s.sessionRepo.GetWithSets(s.ctx, s.user.ID, session.ID)
+	s.Require().NoError(err)
+	s.Require().True(completed.Equal(detail.Sets[0].CompletedAt))
+	sessions, err := s.sessionRepo.ListWithSets(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(120, *sessions[0].Sets[0].RestSeconds)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_CreateWithPlan() {
+	s.truncateSessions()
+	groupID := uuid.New()
+	round, rest := 1, 90
+	created, err := s.sessionRepo.CreateWithPlan(s.ctx, &models.Session{
+		UserID:      s.user.ID,
+		PerformedAt: time.Now().UTC(),
+		Status:      models.SessionPlanned,
+		Groups:      []models.SetGroup{{ID: groupID, GroupIndex: 0, Kind: models.GroupSuperset}},
+		PlannedSets: []models.PlannedSet{
+			{Position: 0, ExerciseID: s.exerciseID, Kind: models.SetKindWarmup, Reps: 10},
+			{Position: 1, ExerciseID: s.exerciseID, Kind: models.SetKindWorking, Reps: 8, WeightKG: 10, RestSeconds: &rest, GroupID: &groupID, Round: &round},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionPlanned, created.Status)
+	s.Require().Equal(groupID, created.Groups[0].ID)
+	s.Require().Len(created.PlannedSets, 2)
+	s.Require().NotEqual(uuid.Nil, created.PlannedSets[1].ID)
+
+	detail, err := s.sessionRepo.GetWithSets(s.ctx, s.user.ID, created.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(detail.Sets, "planned sets are not logged sets")
+	s.Require().Len(detail.PlannedSets, 2)
+	s.Require().Equal(groupID, *detail.PlannedSets[1].GroupID)
+	s.Require().Equal(90, *detail.PlannedSets[1].RestSeconds)
+
+	sessions, err := s.sessionRepo.ListWithSets(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(sessions[0].PlannedSets, 2)
+
+	_, err = s.sessionRepo.CreateWithPlan(s.ctx, &models.Session{
+		UserID:      s.user.ID,
+		PerformedAt: time.Now().UTC(),
+		Status:      models.SessionPlanned,
+		PlannedSets: []models.PlannedSet{{Position: 0, ExerciseID: uuid.New(), Kind: models.SetKindWorking, Reps: 5}},
+	})
+	s.Require().Error(err)
+	sessions, err = s.sessionRepo.ListWithSets(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1, "a failed plan leaves no session behind")
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_PlannedSessions() {
+	s.truncateSessions()
+	now := time.Now().UTC()
+	planned, err := s.sessionRepo.CreateWithPlan(s.ctx, &models.Session{
+		UserID:      s.user.ID,
+		PerformedAt: now.Add(-time.Hour),
+		Status:      models.SessionInProgress,
+		PlannedSets: []models.PlannedSet{{Position: 0, ExerciseID: s.exerciseID, Kind: models.SetKindWorking, Reps: 5}},
+	})
+	s.Require().NoError(err)
+
+	added, err := s.sessionRepo.AddPlannedSet(s.ctx, &models.PlannedSet{SessionID: planned.ID, ExerciseID: s.exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 60})
+	s.Require().NoError(err)
+	s.Require().Equal(1, added.Position, "appended after the existing plan")
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: planned.ID, ExerciseID: s.exerciseID, Reps: 5})
+	s.Require().NoError(err)
+
+	unplanned, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now, Status: models.SessionCompleted})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: unplanned.ID, ExerciseID: s.exerciseID, Reps: 5})
+	s.Require().NoError(err)
+
+	sessions, err := s.sessionRepo.ListPlannedSessions(s.ctx, s.user.ID, now.Add(-24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Empty(sessions, "sessions in progress are not finished yet")
+
+	_, err = s.sessionRepo.FinishSession(s.ctx, s.user.ID, planned.ID, models.SessionCompleted, now)
+	s.Require().NoError(err)
+	sessions, err = s.sessionRepo.ListPlannedSessions(s.ctx, s.user.ID, now.Add(-24*time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1, "sessions without a plan are left out")
+	s.Require().Len(sessions[0].PlannedSets, 2)
+	s.Require().Len(sessions[0].Sets, 1)
+
+	sessions, err = s.sessionRepo.ListPlannedSessions(s.ctx, s.user.ID, now.Add(-time.Minute))
+	s.Require().NoError(err)
+	s.Require().Empty(sessions)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_Calendar() {
+	s.truncateSessions()
+	now := time.Now().UTC().Truncate(time.Second)
+	yesterday := now.Add(-24 * time.Hour)
+	missed, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: yesterday, Status: models.SessionPlanned})
+	s.Require().NoError(err)
+	done, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: yesterday.Add(time.Hour), Status: models.SessionCompleted})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(48 * time.Hour), Status: models.SessionPlanned})
+	s.Require().NoError(err)
+
+	between, err := s.sessionRepo.ListBetween(s.ctx, s.user.ID, yesterday, now)
+	s.Require().NoError(err)
+	s.Require().Len(between, 2)
+	s.Require().Equal(missed.ID, between[0].ID, "in time order")
+	s.Require().Equal(done.ID, between[1].ID)
+
+	overdue, err := s.sessionRepo.ListOverduePlanned(s.ctx, now)
+	s.Require().NoError(err)
+	s.Require().Len(overdue, 1)
+	s.Require().Equal(missed.ID, overdue[0].ID)
+
+	moved, err := s.sessionRepo.RescheduleMissed(s.ctx, s.user.ID, missed.ID, overdue[0].PerformedAt, now.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().True(yesterday.Equal(*moved.RescheduledFrom))
+	s.Require().Equal(1, moved.MissedCount)
+	_, err = s.sessionRepo.RescheduleMissed(s.ctx, s.user.ID, missed.ID, overdue[0].PerformedAt, now.Add(time.Hour))
+	s.Require().ErrorIs(err, sql.ErrNoRows, "a second run does not count the miss again")
+	moved, err = s.sessionRepo.Reschedule(s.ctx, s.user.ID, missed.ID, now.Add(2*time.Hour))
+	s.Require().NoError(err)
+	s.Require().True(yesterday.Equal(*moved.RescheduledFrom), "keeps the originally planned time")
+	s.Require().Equal(1, moved.MissedCount, "moving a session by hand is not a miss")
+	_, err = s.sessionRepo.Reschedule(s.ctx, s.user.ID, done.ID, now)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "only planned sessions move")
+
+	_, err = s.sessionRepo.AbandonPlanned(s.ctx, s.user.ID, missed.ID, overdue[0].PerformedAt, now)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "moved by hand since it was found missed")
+	abandoned, err := s.sessionRepo.AbandonPlanned(s.ctx, s.user.ID, missed.ID, moved.PerformedAt, now)
+	s.Require().NoError(err)
+	s.Require().Equal(models.SessionAbandoned, abandoned.Status)
+	_, err = s.sessionRepo.AbandonPlanned(s.ctx, s.user.ID, missed.ID, moved.PerformedAt, now)
+	s.Require().ErrorIs(err, sql.ErrNoRows)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_ListUpcomingPlanned() {
+	s.truncateSessions()
+	now := time.Now().UTC()
+	later, err := s.sessionRepo.CreateWithPlan(s.ctx, &models.Session{
+		UserID:      s.user.ID,
+		PerformedAt: now.Add(48 * time.Hour),
+		Status:      models.SessionPlanned,
+		PlannedSets: []models.PlannedSet{
+			{Position: 0, ExerciseID: s.exerciseID, Kind: models.SetKindWorking, Reps: 5},
+			{Position: 1, ExerciseID: s.exerciseID, Kind: models.SetKindWorking, Reps: 3},
+		},
+	})
+	s.Require().NoError(err)
+	soon, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(time.Hour), Status: models.SessionPlanned})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(-48 * time.Hour), Status: models.SessionPlanned})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(2 * time.Hour), Status: models.SessionCompleted})
+	s.Require().NoError(err)
+
+	sessions, err := s.sessionRepo.ListUpcomingPlanned(s.ctx, s.user.ID, now)
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2, "only planned sessions from the cutoff on")
+	s.Require().Equal(soon.ID, sessions[0].ID)
+	s.Require().Empty(sessions[0].PlannedSets)
+	s.Require().Equal(later.ID, sessions[1].ID)
+	s.Require().Len(sessions[1].PlannedSets, 2)
+	s.Require().Equal(3, sessions[1].PlannedSets[1].Reps)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_ListCompleted() {
+	s.truncateSessions()
+	now := time.Now().UTC()
+	recent, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now, Status: models.SessionCompleted})
+	s.Require().NoError(err)
+	older, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(-24 * time.Hour), Status: models.SessionCompleted})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now, Status: models.SessionPlanned})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now, Status: models.SessionInProgress, StartedAt: &now})
+	s.Require().NoError(err)
+
+	sessions, err := s.sessionRepo.ListCompleted(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2)
+	s.Require().Equal(older.ID, sessions[0].ID, "oldest first")
+	s.Require().Equal(recent.ID, sessions[1].ID)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_ListExerciseHistory() {
+	s.truncateSessions()
+	var other uuid.UUID
+	s.Require().NoError(testDB.QueryRowContext(s.ctx, `INSERT INTO exercises (name) VALUES ('history dips') RETURNING id`).Scan(&other))
+	now := time.Now().UTC()
+	later, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now})
+	s.Require().NoError(err)
+	earlier, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(-24 * time.Hour)})
+	s.Require().NoError(err)
+	unrelated, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now})
+	s.Require().NoError(err)
+	for _, set := range []*models.Set{
+		{SessionID: later.ID, ExerciseID: s.exerciseID, Reps: 10},
+		{SessionID: later.ID, ExerciseID: other, SetIndex: 1, Reps: 8},
+		{SessionID: earlier.ID, ExerciseID: s.exerciseID, Reps: 12},
+		{SessionID: unrelated.ID, ExerciseID: other, Reps: 8},
+	} {
+		_, err := s.sessionRepo.AddSet(s.ctx, set)
+		s.Require().NoError(err)
+	}
+
+	sessions, err := s.sessionRepo.ListExerciseHistory(s.ctx, s.user.ID, s.exerciseID)
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2, "sessions without the exercise are left out")
+	s.Require().Equal(earlier.ID, sessions[0].ID, "oldest first")
+	s.Require().Len(sessions[1].Sets, 1, "only the exercise's sets")
+	s.Require().Equal(10, sessions[1].Sets[0].Reps)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_ListTrainedSince() {
+	s.truncateSessions()
+	now := time.Now().UTC()
+	recent, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(-time.Hour), Status: models.SessionCompleted})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.AddDate(0, 0, -40), Status: models.SessionCompleted})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: now.Add(-2 * time.Hour), Status: models.SessionPlanned})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: recent.ID, ExerciseID: s.exerciseID, Reps: 5})
+	s.Require().NoError(err)
+
+	sessions, err := s.sessionRepo.ListTrainedSince(s.ctx, s.user.ID, now.AddDate(0, 0, -28))
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1, "older and planned sessions are left out")
+	s.Require().Equal(recent.ID, sessions[0].ID)
+	s.Require().Len(sessions[0].Sets, 1)
+}
+
+func (s *SessionRepositorySuite) TestSessionRepository_GetLastExerciseSet() {
+	s.truncateSessions()
+	var otherID uuid.UUID
+	err := testDB.QueryRowContext(s.ctx, `INSERT INTO exercises (name) VALUES ('last-set-squat') RETURNING id`).Scan(&otherID)
+	s.Require().NoError(err)
+	session, err := s.sessionRepo.Create(s.ctx, &models.Session{UserID: s.user.ID, PerformedAt: time.Now().UTC()})
+	s.Require().NoError(err)
+
+	_, err = s.sessionRepo.GetLastExerciseSet(s.ctx, s.user.ID, s.exerciseID)
+	s.Require().ErrorIs(err, sql.ErrNoRows)
+
+	earlier := time.Now().UTC().Add(-time.Hour)
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, Reps: 12, CompletedAt: earlier})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: s.exerciseID, Kind: models.SetKindBackoff, Reps: 15})
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.AddSet(s.ctx, &models.Set{SessionID: session.ID, ExerciseID: otherID, Reps: 5})
+	s.Require().NoError(err)
+
+	last, err := s.sessionRepo.GetLastExerciseSet(s.ctx, s.user.ID, s.exerciseID)
+	s.Require().NoError(err)
+	s.Require().Equal(12, last.Reps, "only working sets of the exercise count")
+}
+
+func (s *SessionRepositorySuite) TestTemplateRepository() {
+	repo := NewTemplateRepository(testDB)
+	_, err := testDB.ExecContext(s.ctx, `TRUNCATE TABLE session_templates CASCADE`)
+	s.Require().NoError(err)
+
+	groupIndex, round, rest := 0, 2, 60
+	upper := "upper"
+	created, err := repo.Create(s.ctx, &models.SessionTemplate{
+		UserID:      s.user.ID,
+		Name:        "Upper A",
+		SessionType: &upper,
+		Groups:      []models.TemplateGroup{{GroupIndex: 0, Kind: models.GroupCircuit}},
+		Sets: []models.TemplateSet{
+			{Position: 0, ExerciseID: s.exerciseID, Kind: models.SetKindWarmup, Reps: 10},
+			{Position: 1, ExerciseID: s.exerciseID, Kind: models.SetKindWorking, Reps: 8, WeightKG: 12.5, RestSeconds: &rest, GroupIndex: &groupIndex, Round: &round},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().NotEqual(uuid.Nil, created.ID)
+
+	got, err := repo.Get(s.ctx, s.user.ID, created.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("Upper A", got.Name)
+	s.Require().Equal(created.Groups, got.Groups)
+	s.Require().Equal(created.Sets, got.Sets)
+
+	_, err = repo.Get(s.ctx, uuid.New(), created.ID)
+	s.Require().ErrorIs(err, sql.ErrNoRows, "templates belong to their user")
+
+	_, err = repo.Create(s.ctx, &models.SessionTemplate{UserID: s.user.ID, Name: "Empty"})
+	s.Require().NoError(err)
+	templates, err := repo.List(s.ctx, s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(templates, 2)
+	s.Require().Equal("Empty", templates[0].Name, "newest first")
+	s.Require().Empty(templates[0].Sets)
+	s.Require().Len(templates[1].Sets, 2)
+}
+
+func (s *SessionRepositorySuite) TestExerciseRepository_FindByIDs() {
+	repo := NewExerciseRepository(testDB)
+	_, err := testDB.ExecContext(s.ctx, `UPDATE exercises SET bodyweight_fraction = 0.64 WHERE id = $1`, s.exerciseID)
+	s.Require().NoError(err)
+
+	found, err := repo.FindByIDs(s.ctx, []uuid.UUID{s.exerciseID, uuid.New()})
+	s.Require().NoError(err)
+	s.Require().Len(found, 1, "unknown IDs are left out")
+	s.Require().Equal(0.64, found[s.exerciseID].BodyweightFraction)
+	s.Require().Equal(models.EquipmentBarbell, found[s.exerciseID].Equipment)
+	s.Require().Equal(models.MeasurementReps, found[s.exerciseID].Measurement)
+}
+
 func ptrToString(s string) *string {
 	return &s
 }
 
 func (s *SessionRepositorySuite) truncateSessions() {
 	s.T().Helper()
-	_, err := testDB.Exec("TRUNCATE TABLE sets RESTART IDENTITY CASCADE; TRUNCATE TABLE sessions RESTART IDENTITY CASCADE")
+	_, err := testDB.Exec("TRUNCATE TABLE sets RESTART IDENTITY CASCADE; TRUNCATE TABLE set_groups RESTART IDENTITY CASCADE; TRUNCATE TABLE sessions RESTART IDENTITY CASCADE")
 	require.NoError(s.T(), err)
 }
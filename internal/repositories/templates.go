@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Create stores a template with its groups and sets in one transaction.
+func (r *TemplateRepository) Create(ctx context.Context, t *models.SessionTemplate) (*models.SessionTemplate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const insertTemplate = `
+INSERT INTO session_templates (user_id, name, session_type)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, name, session_type, created_at`
+
+	var created models.SessionTemplate
+	err = tx.QueryRowContext(ctx, insertTemplate, t.UserID, t.Name, t.SessionType).
+		Scan(&created.ID, &created.UserID, &created.Name, &created.SessionType, &created.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	const insertGroup = `
+INSERT INTO template_groups (template_id, group_index, kind, rounds, rest_seconds)
+VALUES ($1, $2, $3, $4, $5)`
+
+	for _, g := range t.Groups {
+		if _, err := tx.ExecContext(ctx, insertGroup, created.ID, g.GroupIndex, g.Kind, g.Rounds, g.RestSeconds); err != nil {
+			return nil, err
+		}
+	}
+
+	const insertSet = `
+INSERT INTO template_sets (template_id, position, exercise_id, kind, reps, weight_kg, duration_seconds, distance_m,
+                           rest_seconds, group_index, round)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	for _, set := range t.Sets {
+		_, err := tx.ExecContext(ctx, insertSet, created.ID, set.Position, set.ExerciseID, set.Kind, set.Reps, set.WeightKG,
+			set.DurationSeconds, set.DistanceM, set.RestSeconds, set.GroupIndex, set.Round)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	created.Groups = append([]models.TemplateGroup{}, t.Groups...)
+	created.Sets = append([]models.TemplateSet{}, t.Sets...)
+	return &created, nil
+}
+
+// Get returns one of the user's templates with its groups and sets. It returns sql.ErrNoRows when the
+// template does not exist or belongs to someone else.
+func (r *TemplateRepository) Get(ctx context.Context, userID, templateID uuid.UUID) (*models.SessionTemplate, error) {
+	const q = `
+SELECT id, user_id, name, session_type, created_at
+FROM session_templates
+WHERE id = $1 AND user_id = $2`
+
+	var t models.SessionTemplate
+	err := r.db.QueryRowContext(ctx, q, templateID, userID).
+		Scan(&t.ID, &t.UserID, &t.Name, &t.SessionType, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachContents(ctx, []*models.SessionTemplate{&t}); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// List returns the user's templates, newest first, with their groups and sets.
+func (r *TemplateRepository) List(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error) {
+	const q = `
+SELECT id, user_id, name, session_type, created_at
+FROM session_templates
+WHERE user_id = $1
+ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []*models.SessionTemplate{}
+	for rows.Next() {
+		var t models.SessionTemplate
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.SessionType, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.attachContents(ctx, templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// attachContents loads the groups and sets of the given templates.
+func (r *TemplateRepository) attachContents(ctx context.Context, templates []*models.SessionTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.SessionTemplate, len(templates))
+	ids := make([]uuid.UUID, len(templates))
+	for i, t := range templates {
+		t.Groups = []models.TemplateGroup{}
+		t.Sets = []models.TemplateSet{}
+		byID[t.ID] = t
+		ids[i] = t.ID
+	}
+
+	const groupsQuery = `
+SELECT template_id, group_index, kind, rounds, rest_seconds
+FROM template_groups
+WHERE template_id = ANY($1::uuid[])
+ORDER BY group_index`
+
+	rows, err := r.db.QueryContext(ctx, groupsQuery, uuidArray(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var templateID uuid.UUID
+		var g models.TemplateGroup
+		if err := rows.Scan(&templateID, &g.GroupIndex, &g.Kind, &g.Rounds, &g.RestSeconds); err != nil {
+			return err
+		}
+		byID[templateID].Groups = append(byID[templateID].Groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	const setsQuery = `
+SELECT template_id, position, exercise_id, kind, reps, weight_kg, duration_seconds, distance_m, rest_seconds,
+       group_index, round
+FROM template_sets
+WHERE template_id = ANY($1::uuid[])
+ORDER BY position`
+
+	setRows, err := r.db.QueryContext(ctx, setsQuery, uuidArray(ids))
+	if err != nil {
+		return err
+	}
+	defer setRows.Close()
+	for setRows.Next() {
+		var templateID uuid.UUID
+		var s models.TemplateSet
+		err := setRows.Scan(&templateID, &s.Position, &s.ExerciseID, &s.Kind, &s.Reps, &s.WeightKG, &s.DurationSeconds,
+			&s.DistanceM, &s.RestSeconds, &s.GroupIndex, &s.Round)
+		if err != nil {
+			return err
+		}
+		byID[templateID].Sets = append(byID[templateID].Sets, s)
+	}
+	return setRows.Err()
+}
+
+// uuidArray passes IDs as a Postgres array parameter, to be cast with ::uuid[].
+func uuidArray(ids []uuid.UUID) any {
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = id.String()
+	}
+	return pq.Array(strIDs)
+}
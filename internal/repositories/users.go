@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/alexanderramin/kalistheniks/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type UserRepository struct {
@@ -17,39 +19,476 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// userColumns lists the users columns in the order scanUser expects.
+const userColumns = `id, email, password_hash, email_verified_at, failed_login_attempts, locked_until,
+totp_secret, totp_enabled_at, token_version, deletion_scheduled_at, created_at, updated_at`
+
+func scanUser(row *sql.Row) (*models.User, error) {
+	var u models.User
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.EmailVerifiedAt, &u.FailedLoginAttempts, &u.LockedUntil,
+		&u.TOTPSecret, &u.TOTPEnabledAt, &u.TokenVersion, &u.DeletionScheduledAt, &u.CreatedAt, &u.UpdatedAt)
+	return &u, err
+}
+
 func (r *UserRepository) Create(ctx context.Context, email, passwordHash string) (*models.User, error) {
 	const q = `
 INSERT INTO users (email, password_hash)
 VALUES ($1, $2)
-RETURNING id, email, password_hash, created_at, updated_at`
+RETURNING ` + userColumns
 
-	var u models.User
-	err := r.db.QueryRowContext(ctx, q, email, passwordHash).
-		Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
-	return &u, err
+	return scanUser(r.db.QueryRowContext(ctx, q, email, passwordHash))
 }
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	const q = `
-SELECT id, email, password_hash, created_at, updated_at
+SELECT ` + userColumns + `
 FROM users
 WHERE email = $1`
 
-	var u models.User
-	err := r.db.QueryRowContext(ctx, q, email).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+	u, err := scanUser(r.db.QueryRowContext(ctx, q, email))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, err
 	}
-	return &u, err
+	return u, err
 }
 
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	const q = `
-SELECT id, email, password_hash, created_at, updated_at
+SELECT ` + userColumns + `
 FROM users
 WHERE id = $1`
 
-	var u models.User
-	err := r.db.QueryRowContext(ctx, q, id).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
-	return &u, err
+	return scanUser(r.db.QueryRowContext(ctx, q, id))
+}
+
+// RecordFailedLogin increments the user's consecutive failed login counter and returns the new count.
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, userID uuid.UUID) (int, error) {
+	const q = `
+UPDATE users
+SET failed_login_attempts = failed_login_attempts + 1
+WHERE id = $1
+RETURNING failed_login_attempts`
+
+	var attempts int
+	err := r.db.QueryRowContext(ctx, q, userID).Scan(&attempts)
+	return attempts, err
+}
+
+func (r *UserRepository) LockUntil(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	const q = `
+UPDATE users
+SET locked_until = $2
+WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, userID, until)
+	return err
+}
+
+// ResetFailedLogins clears the failed login counter and any lockout after a successful login.
+func (r *UserRepository) ResetFailedLogins(ctx context.Context, userID uuid.UUID) error {
+	const q = `
+UPDATE users
+SET failed_login_attempts = 0, locked_until = NULL
+WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, userID)
+	return err
+}
+
+func (r *UserRepository) CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	const q = `
+INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)`
+
+	_, err := r.db.ExecContext(ctx, q, userID, tokenHash, expiresAt)
+	return err
+}
+
+// ResetPassword consumes an unused, unexpired reset token and updates the owner's password hash in one transaction.
+// It returns sql.ErrNoRows when the token is unknown, expired or already used.
+func (r *UserRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const consume = `
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id`
+
+	var userID uuid.UUID
+	if err := tx.QueryRowContext(ctx, consume, tokenHash).Scan(&userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	// Bumping the token version signs out every session; whoever had the old password may hold one.
+	const update = `
+UPDATE users
+SET password_hash = $2, token_version = token_version + 1, updated_at = NOW()
+WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, update, userID, passwordHash); err != nil {
+		return uuid.Nil, err
+	}
+
+	// Invalidate any other outstanding reset links for this user.
+	const revoke = `
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, revoke, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit()
+}
+
+func (r *UserRepository) CreateEmailVerification(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	const q = `
+INSERT INTO email_verification_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)`
+
+	_, err := r.db.ExecContext(ctx, q, userID, tokenHash, expiresAt)
+	return err
+}
+
+// VerifyEmail consumes an unused, unexpired verification token and marks the owner's email as verified.
+// It returns sql.ErrNoRows when the token is unknown, expired or already used.
+func (r *UserRepository) VerifyEmail(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const consume = `
+UPDATE email_verification_tokens
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id`
+
+	var userID uuid.UUID
+	if err := tx.QueryRowContext(ctx, consume, tokenHash).Scan(&userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	const update = `
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, update, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit()
+}
+
+// SetPendingTOTPSecret stores a secret awaiting confirmation. It never overwrites an enabled secret.
+func (r *UserRepository) SetPendingTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	const q = `
+UPDATE users
+SET totp_secret = $2, totp_last_step = NULL, updated_at = NOW()
+WHERE id = $1 AND totp_enabled_at IS NULL`
+
+	res, err := r.db.ExecContext(ctx, q, userID, secret)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// EnableTOTP turns on two-factor auth and replaces the user's recovery codes.
+func (r *UserRepository) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const enable = `
+UPDATE users
+SET totp_enabled_at = NOW(), totp_last_step = $2, updated_at = NOW()
+WHERE id = $1 AND totp_secret IS NOT NULL`
+
+	if _, err := tx.ExecContext(ctx, enable, userID, step); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	const insert = `
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+VALUES ($1, $2)`
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, insert, userID, hash); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DisableTOTP removes the secret and all recovery codes.
+func (r *UserRepository) DisableTOTP(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const disable = `
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = NOW()
+WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, disable, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumeTOTPStep records step as used and reports false if it (or a later step) was already used,
+// preventing a code from being replayed.
+func (r *UserRepository) ConsumeTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	const q = `
+UPDATE users
+SET totp_last_step = $2
+WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)`
+
+	res, err := r.db.ExecContext(ctx, q, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ConsumeRecoveryCode marks a matching unused recovery code as used and reports whether one existed.
+func (r *UserRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	const q = `
+UPDATE mfa_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	res, err := r.db.ExecContext(ctx, q, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ChangePassword replaces the password hash, revokes outstanding reset links and bumps the token version
+// so every existing access token stops working. It returns the new token version.
+func (r *UserRepository) ChangePassword(ctx context.Context, userID uuid.UUID, passwordHash string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const update = `
+UPDATE users
+SET password_hash = $2, token_version = token_version + 1, updated_at = NOW()
+WHERE id = $1
+RETURNING token_version`
+
+	var version int
+	if err := tx.QueryRowContext(ctx, update, userID, passwordHash).Scan(&version); err != nil {
+		return 0, err
+	}
+
+	const revoke = `
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, revoke, userID); err != nil {
+		return 0, err
+	}
+	return version, tx.Commit()
+}
+
+func (r *UserRepository) CreateEmailChange(ctx context.Context, userID uuid.UUID, newEmail, tokenHash string, expiresAt time.Time) error {
+	const q = `
+INSERT INTO email_change_requests (user_id, new_email, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.ExecContext(ctx, q, userID, newEmail, tokenHash, expiresAt)
+	return err
+}
+
+// ConfirmEmailChange consumes an unused, unexpired change token and switches the owner to the new, now
+// verified, address. It returns sql.ErrNoRows when the token is unknown, expired or already used.
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, tokenHash string) (*models.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const consume = `
+UPDATE email_change_requests
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id, new_email`
+
+	var userID uuid.UUID
+	var newEmail string
+	if err := tx.QueryRowContext(ctx, consume, tokenHash).Scan(&userID, &newEmail); err != nil {
+		return nil, err
+	}
+
+	const update = `
+UPDATE users
+SET email = $2, email_verified_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING ` + userColumns
+
+	user, err := scanUser(tx.QueryRowContext(ctx, update, userID, newEmail))
+	if err != nil {
+		return nil, err
+	}
+
+	// Other pending changes for this user are superseded.
+	const revoke = `
+UPDATE email_change_requests
+SET used_at = NOW()
+WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, revoke, userID); err != nil {
+		return nil, err
+	}
+	return user, tx.Commit()
+}
+
+// ScheduleDeletion marks the account for deletion at the given time and signs out every session.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	const q = `
+UPDATE users
+SET deletion_scheduled_at = $2, token_version = token_version + 1, updated_at = NOW()
+WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, userID, at)
+	return err
+}
+
+func (r *UserRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	const q = `
+UPDATE users
+SET deletion_scheduled_at = NULL, updated_at = NOW()
+WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, q, userID)
+	return err
+}
+
+// PurgeScheduledDeletions deletes accounts whose grace period ended before now. Everything owned by
+// the account goes with it through ON DELETE CASCADE. It returns the number of accounts deleted.
+func (r *UserRepository) PurgeScheduledDeletions(ctx context.Context, now time.Time) (int64, error) {
+	const q = `
+DELETE FROM users
+WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1`
+
+	res, err := r.db.ExecContext(ctx, q, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// SetCalendarFeedToken stores the user's calendar feed token, replacing any earlier one.
+func (r *UserRepository) SetCalendarFeedToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	const q = `
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()`
+
+	_, err := r.db.ExecContext(ctx, q, userID, tokenHash)
+	return err
+}
+
+// DeleteCalendarFeedToken removes the user's calendar feed token. It returns sql.ErrNoRows when the user
+// has none.
+func (r *UserRepository) DeleteCalendarFeedToken(ctx context.Context, userID uuid.UUID) error {
+	const q = `DELETE FROM calendar_feeds WHERE user_id = $1`
+
+	res, err := r.db.ExecContext(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// FindByCalendarFeedToken returns the owner of a calendar feed token, or sql.ErrNoRows when the token is
+// unknown.
+func (r *UserRepository) FindByCalendarFeedToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	const q = `SELECT user_id FROM calendar_feeds WHERE token_hash = $1`
+
+	var userID uuid.UUID
+	err := r.db.QueryRowContext(ctx, q, tokenHash).Scan(&userID)
+	return userID, err
+}
+
+// GetProfile returns the user's profile, or defaults when none has been saved yet.
+func (r *UserRepository) GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
+	const q = `
+SELECT user_id, bodyweight_kg, height_cm, birth_year, experience, units, time_zone, training_days, updated_at
+FROM user_profiles
+WHERE user_id = $1`
+
+	p, err := scanProfile(r.db.QueryRowContext(ctx, q, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.Profile{UserID: userID, Units: models.UnitsMetric, TimeZone: "UTC", TrainingDays: []string{}}, nil
+	}
+	return p, err
+}
+
+// UpdateProfile applies the non-nil fields of update and empties the cleared ones, creating the profile
+// row on first use.
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, update models.ProfileUpdate) (*models.Profile, error) {
+	const q = `
+INSERT INTO user_profiles (user_id, bodyweight_kg, height_cm, birth_year, experience, units, time_zone, training_days)
+VALUES ($1, $2, $3, $4, $5, COALESCE($6, 'metric'), COALESCE($7, 'UTC'), COALESCE($8::text[], '{}'))
+ON CONFLICT (user_id) DO UPDATE SET
+    bodyweight_kg = CASE WHEN $9 THEN NULL ELSE COALESCE($2, user_profiles.bodyweight_kg) END,
+    height_cm = CASE WHEN $10 THEN NULL ELSE COALESCE($3, user_profiles.height_cm) END,
+    birth_year = CASE WHEN $11 THEN NULL ELSE COALESCE($4, user_profiles.birth_year) END,
+    experience = COALESCE($5, user_profiles.experience),
+    units = COALESCE($6, user_profiles.units),
+    time_zone = COALESCE($7, user_profiles.time_zone),
+    training_days = COALESCE($8::text[], user_profiles.training_days),
+    updated_at = NOW()
+RETURNING user_id, bodyweight_kg, height_cm, birth_year, experience, units, time_zone, training_days, updated_at`
+
+	var trainingDays any
+	if update.TrainingDays != nil {
+		trainingDays = pq.Array(*update.TrainingDays)
+	}
+	return scanProfile(r.db.QueryRowContext(ctx, q, userID, update.BodyweightKG, update.HeightCM, update.BirthYear,
+		update.Experience, update.Units, update.TimeZone, trainingDays,
+		update.ClearBodyweight, update.ClearHeight, update.ClearBirthYear))
+}
+
+func scanProfile(row *sql.Row) (*models.Profile, error) {
+	var p models.Profile
+	err := row.Scan(&p.UserID, &p.BodyweightKG, &p.HeightCM, &p.BirthYear, &p.Experience, &p.Units, &p.TimeZone,
+		pq.Array(&p.TrainingDays), &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
 }
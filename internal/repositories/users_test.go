@@ -2,8 +2,11 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
+	"github.com/alexanderramin/kalistheniks/internal/models"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -84,6 +87,297 @@ func TestUserRepository_FindByID(t *testing.T) {
 	})
 }
 
+func TestUserRepository_ResetPassword(t *testing.T) {
+	t.Run("consumes token and updates password", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		user, err := repo.Create(context.Background(), "reset@example.com", "oldhash")
+		require.NoError(t, err)
+		require.NoError(t, repo.CreatePasswordReset(context.Background(), user.ID, "tokenhash", time.Now().Add(time.Hour)))
+
+		userID, err := repo.ResetPassword(context.Background(), "tokenhash", "newhash")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, userID)
+
+		found, err := repo.FindByID(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "newhash", found.PasswordHash)
+
+		_, err = repo.ResetPassword(context.Background(), "tokenhash", "otherhash")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		truncateUsers(t)
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		user, err := repo.Create(context.Background(), "reset@example.com", "oldhash")
+		require.NoError(t, err)
+		require.NoError(t, repo.CreatePasswordReset(context.Background(), user.ID, "expiredhash", time.Now().Add(-time.Minute)))
+
+		_, err = repo.ResetPassword(context.Background(), "expiredhash", "newhash")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_VerifyEmail(t *testing.T) {
+	t.Run("marks email verified once", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		user, err := repo.Create(context.Background(), "verify@example.com", "hash")
+		require.NoError(t, err)
+		require.Nil(t, user.EmailVerifiedAt)
+		require.NoError(t, repo.CreateEmailVerification(context.Background(), user.ID, "verifyhash", time.Now().Add(time.Hour)))
+
+		userID, err := repo.VerifyEmail(context.Background(), "verifyhash")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, userID)
+
+		found, err := repo.FindByID(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.EmailVerifiedAt)
+
+		_, err = repo.VerifyEmail(context.Background(), "verifyhash")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_FailedLogins(t *testing.T) {
+	t.Run("counts failures, locks and resets", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		user, err := repo.Create(context.Background(), "lockout@example.com", "hash")
+		require.NoError(t, err)
+
+		attempts, err := repo.RecordFailedLogin(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, attempts)
+		attempts, err = repo.RecordFailedLogin(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+
+		require.NoError(t, repo.LockUntil(context.Background(), user.ID, time.Now().Add(time.Minute)))
+		found, err := repo.FindByID(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, found.FailedLoginAttempts)
+		require.NotNil(t, found.LockedUntil)
+
+		require.NoError(t, repo.ResetFailedLogins(context.Background(), user.ID))
+		found, err = repo.FindByID(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Zero(t, found.FailedLoginAttempts)
+		require.Nil(t, found.LockedUntil)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_TOTP(t *testing.T) {
+	t.Run("enables, consumes steps and recovery codes once, disables", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "totp@example.com", "hash")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.SetPendingTOTPSecret(ctx, user.ID, "SECRET"))
+		require.NoError(t, repo.EnableTOTP(ctx, user.ID, 100, []string{"code1", "code2"}))
+		found, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.TOTPEnabledAt)
+		require.ErrorIs(t, repo.SetPendingTOTPSecret(ctx, user.ID, "OTHER"), sql.ErrNoRows)
+
+		ok, err := repo.ConsumeTOTPStep(ctx, user.ID, 100)
+		require.NoError(t, err)
+		require.False(t, ok)
+		ok, err = repo.ConsumeTOTPStep(ctx, user.ID, 101)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repo.ConsumeRecoveryCode(ctx, user.ID, "code1")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = repo.ConsumeRecoveryCode(ctx, user.ID, "code1")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, repo.DisableTOTP(ctx, user.ID))
+		found, err = repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Nil(t, found.TOTPEnabledAt)
+		require.Nil(t, found.TOTPSecret)
+		ok, err = repo.ConsumeRecoveryCode(ctx, user.ID, "code2")
+		require.NoError(t, err)
+		require.False(t, ok)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_Profile(t *testing.T) {
+	t.Run("defaults, creates and partially updates", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "profile@example.com", "hash")
+		require.NoError(t, err)
+
+		profile, err := repo.GetProfile(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.UnitsMetric, profile.Units)
+		require.Equal(t, "UTC", profile.TimeZone)
+		require.Nil(t, profile.BodyweightKG)
+
+		bodyweight := 82.5
+		profile, err = repo.UpdateProfile(ctx, user.ID, models.ProfileUpdate{BodyweightKG: &bodyweight})
+		require.NoError(t, err)
+		require.Equal(t, 82.5, *profile.BodyweightKG)
+		require.Equal(t, models.UnitsMetric, profile.Units)
+
+		units := models.UnitsImperial
+		profile, err = repo.UpdateProfile(ctx, user.ID, models.ProfileUpdate{Units: &units})
+		require.NoError(t, err)
+		require.Equal(t, models.UnitsImperial, profile.Units)
+		require.Equal(t, 82.5, *profile.BodyweightKG, "fields not in the update are kept")
+
+		days := []string{"monday", "thursday"}
+		profile, err = repo.UpdateProfile(ctx, user.ID, models.ProfileUpdate{TrainingDays: &days})
+		require.NoError(t, err)
+		require.Equal(t, days, profile.TrainingDays)
+
+		profile, err = repo.UpdateProfile(ctx, user.ID, models.ProfileUpdate{ClearBodyweight: true})
+		require.NoError(t, err)
+		require.Nil(t, profile.BodyweightKG)
+		require.Equal(t, days, profile.TrainingDays)
+
+		found, err := repo.GetProfile(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, profile, found)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_CalendarFeed(t *testing.T) {
+	t.Run("replaces, finds and deletes the token", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "feed@example.com", "hash")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.SetCalendarFeedToken(ctx, user.ID, "firsthash"))
+		require.NoError(t, repo.SetCalendarFeedToken(ctx, user.ID, "secondhash"))
+		_, err = repo.FindByCalendarFeedToken(ctx, "firsthash")
+		require.ErrorIs(t, err, sql.ErrNoRows, "a new token replaces the old one")
+		userID, err := repo.FindByCalendarFeedToken(ctx, "secondhash")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, userID)
+
+		require.NoError(t, repo.DeleteCalendarFeedToken(ctx, user.ID))
+		_, err = repo.FindByCalendarFeedToken(ctx, "secondhash")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		require.ErrorIs(t, repo.DeleteCalendarFeedToken(ctx, user.ID), sql.ErrNoRows)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_AccountChanges(t *testing.T) {
+	t.Run("password change bumps token version and revokes reset links", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "change@example.com", "hash")
+		require.NoError(t, err)
+		require.Equal(t, 0, user.TokenVersion)
+		require.NoError(t, repo.CreatePasswordReset(ctx, user.ID, "reset-hash", time.Now().Add(time.Hour)))
+
+		version, err := repo.ChangePassword(ctx, user.ID, "newhash")
+		require.NoError(t, err)
+		require.Equal(t, 1, version)
+
+		found, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "newhash", found.PasswordHash)
+		require.Equal(t, 1, found.TokenVersion)
+		_, err = repo.ResetPassword(ctx, "reset-hash", "other")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		truncateUsers(t)
+	})
+
+	t.Run("email change is confirmed once", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "old@example.com", "hash")
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateEmailChange(ctx, user.ID, "new@example.com", "change-hash", time.Now().Add(time.Hour)))
+		require.NoError(t, repo.CreateEmailChange(ctx, user.ID, "other@example.com", "other-hash", time.Now().Add(time.Hour)))
+
+		changed, err := repo.ConfirmEmailChange(ctx, "change-hash")
+		require.NoError(t, err)
+		require.Equal(t, "new@example.com", changed.Email)
+		require.NotNil(t, changed.EmailVerifiedAt)
+
+		_, err = repo.ConfirmEmailChange(ctx, "change-hash")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.ConfirmEmailChange(ctx, "other-hash")
+		require.ErrorIs(t, err, sql.ErrNoRows, "confirming one change supersedes the others")
+		truncateUsers(t)
+	})
+
+	t.Run("expired email change", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "stale@example.com", "hash")
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateEmailChange(ctx, user.ID, "new@example.com", "stale-hash", time.Now().Add(-time.Minute)))
+
+		_, err = repo.ConfirmEmailChange(ctx, "stale-hash")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		truncateUsers(t)
+	})
+}
+
+func TestUserRepository_Deletion(t *testing.T) {
+	t.Run("cancelled deletion keeps the account", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		ctx := context.Background()
+		user, err := repo.Create(ctx, "keep@example.com", "hash")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.ScheduleDeletion(ctx, user.ID, time.Now().Add(-time.Minute)))
+		found, err := repo.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.DeletionScheduledAt)
+		require.Equal(t, 1, found.TokenVersion, "scheduling signs out every session")
+
+		require.NoError(t, repo.CancelDeletion(ctx, user.ID))
+		n, err := repo.PurgeScheduledDeletions(ctx, time.Now())
+		require.NoError(t, err)
+		require.Zero(t, n)
+		truncateUsers(t)
+	})
+
+	t.Run("purge removes due accounts and their data", func(t *testing.T) {
+		repo := NewUserRepository(testDB)
+		sessions := NewSessionRepository(testDB)
+		ctx := context.Background()
+		due, err := repo.Create(ctx, "due@example.com", "hash")
+		require.NoError(t, err)
+		later, err := repo.Create(ctx, "later@example.com", "hash")
+		require.NoError(t, err)
+		_, err = sessions.Create(ctx, &models.Session{UserID: due.ID, PerformedAt: time.Now().UTC()})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.ScheduleDeletion(ctx, due.ID, time.Now().Add(-time.Minute)))
+		require.NoError(t, repo.ScheduleDeletion(ctx, later.ID, time.Now().Add(time.Hour)))
+
+		n, err := repo.PurgeScheduledDeletions(ctx, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		_, err = repo.FindByID(ctx, due.ID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.FindByID(ctx, later.ID)
+		require.NoError(t, err)
+
+		var remaining int
+		require.NoError(t, testDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM sessions WHERE user_id = $1`, due.ID).Scan(&remaining))
+		require.Zero(t, remaining)
+		truncateUsers(t)
+	})
+}
+
 func truncateUsers(t *testing.T) {
 	t.Helper()
 	_, err := testDB.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
@@ -0,0 +1,59 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+// Measure compares a session's planned sets with its logged sets. Each planned set is matched, in plan
+// order, with the earliest logged set of the same exercise and kind not matched yet. Logged sets beyond
+// the plan are ignored. With an exercise given, only that exercise's sets count.
+func Measure(s *models.Session, exerciseID *uuid.UUID) models.Adherence {
+	logged := append([]models.Set{}, s.Sets...)
+	sort.SliceStable(logged, func(i, j int) bool { return logged[i].CompletedAt.Before(logged[j].CompletedAt) })
+	used := make([]bool, len(logged))
+
+	var a models.Adherence
+	for _, p := range s.PlannedSets {
+		if exerciseID != nil && p.ExerciseID != *exerciseID {
+			continue
+		}
+		a.PlannedSets++
+		a.PlannedReps += p.Reps
+		a.PlannedLoadKG += float64(p.Reps) * p.WeightKG
+		for i, set := range logged {
+			if used[i] || set.ExerciseID != p.ExerciseID || set.Kind != p.Kind {
+				continue
+			}
+			used[i] = true
+			a.CompletedSets++
+			a.ActualReps += set.Reps
+			a.ActualLoadKG += float64(set.Reps) * set.WeightKG
+			break
+		}
+	}
+	return a
+}
+
+// Add returns the sum of two adherence measurements.
+func Add(a, b models.Adherence) models.Adherence {
+	return models.Adherence{
+		PlannedSets:   a.PlannedSets + b.PlannedSets,
+		CompletedSets: a.CompletedSets + b.CompletedSets,
+		PlannedReps:   a.PlannedReps + b.PlannedReps,
+		ActualReps:    a.ActualReps + b.ActualReps,
+		PlannedLoadKG: a.PlannedLoadKG + b.PlannedLoadKG,
+		ActualLoadKG:  a.ActualLoadKG + b.ActualLoadKG,
+	}
+}
+
+// Ratio is actual over planned, or nil when nothing was planned.
+func Ratio(actual, planned float64) *float64 {
+	if planned == 0 {
+		return nil
+	}
+	r := actual / planned
+	return &r
+}
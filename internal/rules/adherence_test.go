@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasure(t *testing.T) {
+	squat, row := uuid.New(), uuid.New()
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	session := &models.Session{
+		PlannedSets: []models.PlannedSet{
+			{Position: 0, ExerciseID: squat, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20},
+			{Position: 1, ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+			{Position: 2, ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+			{Position: 3, ExerciseID: row, Kind: models.SetKindWorking, Reps: 10, WeightKG: 50},
+		},
+		Sets: []models.Set{
+			{ExerciseID: squat, Kind: models.SetKindWorking, Reps: 4, WeightKG: 100, CompletedAt: start.Add(10 * time.Minute)},
+			{ExerciseID: squat, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20, CompletedAt: start},
+			{ExerciseID: row, Kind: models.SetKindWorking, Reps: 10, WeightKG: 50, CompletedAt: start.Add(20 * time.Minute)},
+			{ExerciseID: row, Kind: models.SetKindWorking, Reps: 10, WeightKG: 50, CompletedAt: start.Add(25 * time.Minute)},
+		},
+	}
+
+	t.Run("whole session", func(t *testing.T) {
+		a := Measure(session, nil)
+		require.Equal(t, 4, a.PlannedSets)
+		require.Equal(t, 3, a.CompletedSets, "one working squat set was skipped")
+		require.Equal(t, 30, a.PlannedReps)
+		require.Equal(t, 24, a.ActualReps, "the extra row set is beyond the plan")
+		require.InDelta(t, 1700.0, a.PlannedLoadKG, 1e-9)
+		require.InDelta(t, 1100.0, a.ActualLoadKG, 1e-9)
+	})
+
+	t.Run("one exercise", func(t *testing.T) {
+		a := Measure(session, &squat)
+		require.Equal(t, models.Adherence{PlannedSets: 3, CompletedSets: 2, PlannedReps: 20, ActualReps: 14, PlannedLoadKG: 1200, ActualLoadKG: 600}, a)
+	})
+
+	t.Run("nothing planned", func(t *testing.T) {
+		require.Equal(t, models.Adherence{}, Measure(&models.Session{Sets: session.Sets}, nil))
+		require.Nil(t, Ratio(5, 0))
+	})
+}
+
+func TestProgressionStep(t *testing.T) {
+	re := New()
+	tests := []struct {
+		name string
+		a    models.Adherence
+		want Step
+	}{
+		{"too little planned", models.Adherence{PlannedSets: 2, PlannedReps: 10}, Progress},
+		{"plan completed", models.Adherence{PlannedSets: 6, CompletedSets: 6, PlannedReps: 30, ActualReps: 30}, Progress},
+		{"a set short", models.Adherence{PlannedSets: 6, CompletedSets: 5, PlannedReps: 30, ActualReps: 25}, Hold},
+		{"reps short", models.Adherence{PlannedSets: 6, CompletedSets: 6, PlannedReps: 30, ActualReps: 25}, Hold},
+		{"half the sets missed", models.Adherence{PlannedSets: 6, CompletedSets: 3, PlannedReps: 30, ActualReps: 15}, Deload},
+		{"timed plan completed", models.Adherence{PlannedSets: 3, CompletedSets: 3}, Progress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, re.ProgressionStep(tt.a))
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package rules
 
-import "context"
+import (
+	"context"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+)
 
 // RuleEngine will orchestrate workout and progression logic.
 type RuleEngine struct{}
@@ -15,3 +19,36 @@ func (re *RuleEngine) NextWorkout(ctx context.Context, userID string) (string, e
 	// TODO: implement rule evaluation based on user progress.
 	return "", nil
 }
+
+// Step is how far progression may move an exercise on.
+type Step int
+
+const (
+	Progress Step = iota // follow the normal progression
+	Hold                 // repeat the current target instead of adding to it
+	Deload               // back off the load
+)
+
+// minPlannedSets is how many planned sets an exercise needs before adherence steers its progression.
+const minPlannedSets = 3
+
+// ProgressionStep decides from the recent adherence to an exercise's plans whether it may progress.
+// Plans mostly completed but short on reps or sets hold progression; plans missed by a wide margin call
+// for a deload. Too little planned history leaves progression alone.
+func (re *RuleEngine) ProgressionStep(a models.Adherence) Step {
+	if a.PlannedSets < minPlannedSets {
+		return Progress
+	}
+	sets := float64(a.CompletedSets) / float64(a.PlannedSets)
+	reps := 1.0
+	if r := Ratio(float64(a.ActualReps), float64(a.PlannedReps)); r != nil {
+		reps = *r
+	}
+	switch {
+	case sets < 0.6 || reps < 0.7:
+		return Deload
+	case sets < 0.9 || reps < 0.9:
+		return Hold
+	}
+	return Progress
+}
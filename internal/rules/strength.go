@@ -0,0 +1,17 @@
+package rules
+
+// maxE1RMReps is the most reps a set may have to estimate a one-rep max from. Beyond it the estimate
+// says more about endurance than strength.
+const maxE1RMReps = 12
+
+// E1RM estimates the one-rep max from a set of reps at a load with the Epley formula. It returns 0 when
+// the set has no load or too many or too few reps to estimate from; a single rep is its own max.
+func E1RM(loadKG float64, reps int) float64 {
+	if loadKG <= 0 || reps < 1 || reps > maxE1RMReps {
+		return 0
+	}
+	if reps == 1 {
+		return loadKG
+	}
+	return loadKG * (1 + float64(reps)/30)
+}
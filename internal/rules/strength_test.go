@@ -0,0 +1,16 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE1RM(t *testing.T) {
+	require.Equal(t, 100.0, E1RM(100, 1), "a single is its own max")
+	require.InDelta(t, 116.67, E1RM(100, 5), 0.01)
+	require.InDelta(t, 140.0, E1RM(100, 12), 0.01)
+	require.Zero(t, E1RM(100, 13), "too many reps to estimate from")
+	require.Zero(t, E1RM(0, 5), "no load")
+	require.Zero(t, E1RM(100, 0))
+}
@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"math"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+)
+
+// Acute and chronic workload windows in days. The chronic load is a weekly average over its window so
+// that the two compare directly.
+const (
+	AcuteDays   = 7
+	ChronicDays = 28
+)
+
+// defaultSetRPE stands in for the RPE of a set logged without one.
+const defaultSetRPE = 7
+
+// SessionLoad is the sets × RPE load of a session: the RPE of every set but warm-ups, summed.
+func SessionLoad(sets []models.Set) float64 {
+	var load float64
+	for _, set := range sets {
+		if set.Kind == models.SetKindWarmup {
+			continue
+		}
+		rpe := defaultSetRPE
+		if set.RPE != nil {
+			rpe = *set.RPE
+		}
+		load += float64(rpe)
+	}
+	return load
+}
+
+// ReadinessScore turns a check-in into a score from 0, poor sleep with severe soreness and stress, to 1,
+// great sleep with neither.
+func ReadinessScore(c models.ReadinessCheckIn) float64 {
+	points := (c.Sleep - 1) + (5 - c.Soreness) + (5 - c.Stress)
+	return math.Round(float64(points)/12*100) / 100
+}
+
+// Workload ratios above these are a spike in training load, where injury risk climbs.
+const (
+	elevatedRatio = 1.3
+	spikeRatio    = 1.5
+)
+
+// Readiness scores below these call for lighter training.
+const (
+	lowReadiness     = 0.5
+	veryLowReadiness = 0.35
+)
+
+// minVolumeScale is the furthest volume is ever scaled down.
+const minVolumeScale = 0.6
+
+// VolumeScale decides how much of the usual volume to suggest from the workload ratio and today's
+// readiness score, either of which may be unknown. A spike in load or a poor check-in each take volume
+// down, together no further than minVolumeScale. The note says why; it is empty when volume is kept.
+func VolumeScale(ratio, readiness *float64) (float64, string) {
+	scale := 1.0
+	var note string
+	switch {
+	case ratio != nil && *ratio > spikeRatio:
+		scale *= 0.7
+		note = "Training load spiked this week; volume cut to recover."
+	case ratio != nil && *ratio > elevatedRatio:
+		scale *= 0.85
+		note = "Training load is climbing fast; volume trimmed."
+	}
+	switch {
+	case readiness != nil && *readiness < veryLowReadiness:
+		scale *= 0.7
+		note = joinNotes(note, "Readiness is very low today; volume cut.")
+	case readiness != nil && *readiness < lowReadiness:
+		scale *= 0.85
+		note = joinNotes(note, "Readiness is low today; volume trimmed.")
+	}
+	return math.Round(max(scale, minVolumeScale)*100) / 100, note
+}
+
+func joinNotes(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
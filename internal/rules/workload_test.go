@@ -0,0 +1,55 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionLoad(t *testing.T) {
+	rpe := func(v int) *int { return &v }
+	require.Equal(t, 24.0, SessionLoad([]models.Set{
+		{Kind: models.SetKindWarmup, RPE: rpe(4)},
+		{Kind: models.SetKindWorking, RPE: rpe(8)},
+		{Kind: models.SetKindWorking, RPE: rpe(9)},
+		{Kind: models.SetKindAMRAP},
+	}), "warm-ups are left out and a set without RPE counts as 7")
+	require.Zero(t, SessionLoad(nil))
+}
+
+func TestReadinessScore(t *testing.T) {
+	require.Equal(t, 1.0, ReadinessScore(models.ReadinessCheckIn{Sleep: 5, Soreness: 1, Stress: 1}))
+	require.Equal(t, 0.0, ReadinessScore(models.ReadinessCheckIn{Sleep: 1, Soreness: 5, Stress: 5}))
+	require.Equal(t, 0.5, ReadinessScore(models.ReadinessCheckIn{Sleep: 3, Soreness: 3, Stress: 3}))
+}
+
+func TestVolumeScale(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name      string
+		ratio     *float64
+		readiness *float64
+		scale     float64
+		note      string
+	}{
+		{name: "nothing known", scale: 1},
+		{name: "steady load and good readiness", ratio: f(1.1), readiness: f(0.8), scale: 1},
+		{name: "elevated load", ratio: f(1.4), scale: 0.85, note: "climbing fast"},
+		{name: "load spike", ratio: f(1.6), readiness: f(0.9), scale: 0.7, note: "spiked"},
+		{name: "low readiness", ratio: f(1), readiness: f(0.4), scale: 0.85, note: "Readiness is low"},
+		{name: "very low readiness", readiness: f(0.2), scale: 0.7, note: "very low"},
+		{name: "both together bottom out", ratio: f(2), readiness: f(0.1), scale: 0.6, note: "spiked this week; volume cut to recover. Readiness"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scale, note := VolumeScale(tt.ratio, tt.readiness)
+			require.Equal(t, tt.scale, scale)
+			if tt.note == "" {
+				require.Empty(t, note)
+				return
+			}
+			require.Contains(t, note, tt.note)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/mailer"
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	t "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword replaces the password after checking the current one. Every other session is signed out;
+// the returned token replaces the caller's. Wrong current passwords count towards the account lockout.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (string, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return "", ErrAccountLocked
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidCredentials, lockErr)
+		}
+		return "", ErrInvalidCredentials
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrHashPassword, err)
+	}
+	version, err := s.users.ChangePassword(ctx, user.ID, string(hashed))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrChangePassword, err)
+	}
+
+	// The change already happened; a failed notice must not turn it into an error.
+	_ = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: "The password for your Kalistheniks account was just changed and all other sessions were signed out.\n\n" +
+			"If this wasn't you, reset your password now:\n" + s.baseURL + "/forgot-password",
+	})
+
+	token, err := t.GenerateVersionedToken(user.ID, version, s.jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	return token, nil
+}
+
+// RequestEmailChange emails a confirmation link to the new address; the account keeps its current email
+// until the link is used. If the new address already belongs to an account, its owner is told instead,
+// so the endpoint cannot be used to probe for registered emails.
+func (s *AuthService) RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail string) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return ErrAccountLocked
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCredentials, lockErr)
+		}
+		return ErrInvalidCredentials
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+
+	existing, err := s.users.FindByEmail(ctx, newEmail)
+	switch {
+	case err == nil:
+		_ = s.mailer.Send(ctx, mailer.Message{
+			To:      existing.Email,
+			Subject: "Someone tried to use your email",
+			Body: "Someone tried to move another Kalistheniks account to this email address, " +
+				"but it already belongs to your account. No changes were made.",
+		})
+		return nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+
+	raw, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	if err := s.users.CreateEmailChange(ctx, user.ID, newEmail, t.HashOpaqueToken(raw), time.Now().Add(emailChangeTTL)); err != nil {
+		return fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+
+	msg := mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Confirm this address for your Kalistheniks account within %d hours using this link:\n%s\n\n"+
+			"If you didn't ask for this, you can ignore this email.",
+			int(emailChangeTTL.Hours()), s.link("/confirm-email", raw)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrSendMail, err)
+	}
+
+	// Let the current address know, in case someone else is signed in to the account.
+	_ = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Email change requested",
+		Body: "Someone asked to move your Kalistheniks account to a new email address. " +
+			"Nothing changes until the new address is confirmed.\n\n" +
+			"If this wasn't you, reset your password now:\n" + s.baseURL + "/forgot-password",
+	})
+	return nil
+}
+
+// ConfirmEmailChange switches the account to the address the token was sent to. Tokens are single use.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, changeToken string) error {
+	if changeToken == "" {
+		return ErrInvalidEmailChange
+	}
+	_, err := s.users.ConfirmEmailChange(ctx, t.HashOpaqueToken(changeToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidEmailChange
+	}
+	if isUniqueViolation(err) {
+		// Someone signed up with the new address after the change was requested.
+		return ErrEmailTaken
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrChangeEmail, err)
+	}
+	return nil
+}
+
+// DeleteAccount schedules the account for deletion after a grace period and signs out every session.
+// Signing in again before the returned time restores the account; after it, PurgeDeletedAccounts removes
+// the account and everything it owns.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return time.Time{}, ErrAccountLocked
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+			return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, lockErr)
+		}
+		return time.Time{}, ErrInvalidCredentials
+	}
+
+	deleteAt := time.Now().Add(accountDeletionGrace).UTC()
+	if err := s.users.ScheduleDeletion(ctx, user.ID, deleteAt); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrDeleteAccount, err)
+	}
+
+	_ = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your account will be deleted",
+		Body: fmt.Sprintf("Your Kalistheniks account and all of its training data will be deleted on %s.\n\n"+
+			"Changed your mind? Sign in before then to keep your account.",
+			deleteAt.Format("2 January 2006")),
+	})
+	return deleteAt, nil
+}
+
+// PurgeDeletedAccounts permanently deletes accounts whose grace period has ended.
+func (s *AuthService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	n, err := s.users.PurgeScheduledDeletions(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDeleteAccount, err)
+	}
+	return n, nil
+}
+
+// restoreAccount cancels a pending deletion; every successful sign-in goes through it.
+func restoreAccount(ctx context.Context, users UserRepository, user *models.User) error {
+	if user.DeletionScheduledAt == nil {
+		return nil
+	}
+	if err := users.CancelDeletion(ctx, user.ID); err != nil {
+		return fmt.Errorf("%w: %v", ErrDeleteAccount, err)
+	}
+	user.DeletionScheduledAt = nil
+	return nil
+}
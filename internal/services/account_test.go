@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	tok "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// accountPasswordHash is the bcrypt hash of "password123".
+const accountPasswordHash = "$2a$10$nzAyuLjvw2JKqKETtpFyvukYMwsMoAByVcziZ7RGnZUlQvehEJ8qq"
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "a@example.com", PasswordHash: accountPasswordHash, TokenVersion: 2}
+
+	t.Run("issues token with new version", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().ChangePassword(ctx, userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string) (int, error) {
+				require.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("NewPassword1!")))
+				return 3, nil
+			})
+
+		token, err := deps.svc.ChangePassword(ctx, userID, "password123", "NewPassword1!")
+		require.NoError(t, err)
+		subject, version, err := tok.ParseVersionedToken(token, "testsecret")
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), subject)
+		require.Equal(t, 3, version)
+		require.Contains(t, deps.mail.String(), "Your password was changed")
+	})
+
+	t.Run("wrong current password counts as failed login", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(1, nil)
+
+		_, err := deps.svc.ChangePassword(ctx, userID, "wrong", "NewPassword1!")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("locked account", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		lockedUntil := time.Now().Add(time.Minute)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, PasswordHash: accountPasswordHash, LockedUntil: &lockedUntil}, nil)
+
+		_, err := deps.svc.ChangePassword(ctx, userID, "password123", "NewPassword1!")
+		require.ErrorIs(t, err, ErrAccountLocked)
+	})
+}
+
+func TestAuthService_VerifyToken(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("current version", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		token, err := tok.GenerateVersionedToken(userID, 1, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, TokenVersion: 1}, nil)
+
+		subject, err := deps.svc.VerifyToken(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), subject)
+	})
+
+	t.Run("token from before password change", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		token, err := tok.GenerateVersionedToken(userID, 1, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, TokenVersion: 2}, nil)
+
+		_, err = deps.svc.VerifyToken(ctx, token)
+		require.ErrorIs(t, err, ErrTokenRevoked)
+	})
+
+	t.Run("deleted account", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		token, err := tok.GenerateToken(userID, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		_, err = deps.svc.VerifyToken(ctx, token)
+		require.ErrorIs(t, err, ErrTokenRevoked)
+	})
+
+	t.Run("garbage token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		_, err := deps.svc.VerifyToken(ctx, "not-a-jwt")
+		require.ErrorIs(t, err, ErrParseToken)
+	})
+}
+
+func TestAuthService_RequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "old@example.com", PasswordHash: accountPasswordHash}
+
+	t.Run("mails link to new address", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "new@example.com").Return(nil, sql.ErrNoRows)
+		deps.usersRepo.EXPECT().CreateEmailChange(ctx, userID, "new@example.com", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, _, _ string, expiresAt time.Time) error {
+				require.WithinDuration(t, time.Now().Add(emailChangeTTL), expiresAt, time.Minute)
+				return nil
+			})
+
+		require.NoError(t, deps.svc.RequestEmailChange(ctx, userID, "password123", "new@example.com"))
+		require.Contains(t, deps.mail.String(), "https://app.example.com/confirm-email?token=")
+		require.Contains(t, deps.mail.String(), "To: new@example.com")
+		require.Contains(t, deps.mail.String(), "To: old@example.com")
+	})
+
+	t.Run("taken address is concealed", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "taken@example.com").Return(&models.User{ID: uuid.New(), Email: "taken@example.com"}, nil)
+
+		require.NoError(t, deps.svc.RequestEmailChange(ctx, userID, "password123", "taken@example.com"))
+		require.NotContains(t, deps.mail.String(), "confirm-email")
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(1, nil)
+
+		err := deps.svc.RequestEmailChange(ctx, userID, "wrong", "new@example.com")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("same address", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+
+		err := deps.svc.RequestEmailChange(ctx, userID, "password123", "OLD@example.com")
+		require.ErrorIs(t, err, ErrSameEmail)
+	})
+}
+
+func TestAuthService_ConfirmEmailChange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().ConfirmEmailChange(ctx, tok.HashOpaqueToken("raw")).Return(&models.User{Email: "new@example.com"}, nil)
+		require.NoError(t, deps.svc.ConfirmEmailChange(ctx, "raw"))
+	})
+
+	t.Run("unknown or used token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().ConfirmEmailChange(ctx, tok.HashOpaqueToken("raw")).Return(nil, sql.ErrNoRows)
+		require.ErrorIs(t, deps.svc.ConfirmEmailChange(ctx, "raw"), ErrInvalidEmailChange)
+	})
+
+	t.Run("new address registered in the meantime", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().ConfirmEmailChange(ctx, tok.HashOpaqueToken("raw")).Return(nil, &pq.Error{Code: "23505"})
+		require.ErrorIs(t, deps.svc.ConfirmEmailChange(ctx, "raw"), ErrEmailTaken)
+	})
+}
+
+func TestAuthService_DeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "a@example.com", PasswordHash: accountPasswordHash}
+
+	t.Run("schedules deletion after grace period", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().ScheduleDeletion(ctx, userID, gomock.Any()).Return(nil)
+
+		deleteAt, err := deps.svc.DeleteAccount(ctx, userID, "password123")
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(accountDeletionGrace), deleteAt, time.Minute)
+		require.Contains(t, deps.mail.String(), "Your account will be deleted")
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(user, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(1, nil)
+
+		_, err := deps.svc.DeleteAccount(ctx, userID, "wrong")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("signing in restores the account", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		scheduled := time.Now().Add(24 * time.Hour)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "a@example.com").
+			Return(&models.User{ID: userID, PasswordHash: accountPasswordHash, DeletionScheduledAt: &scheduled}, nil)
+		deps.usersRepo.EXPECT().CancelDeletion(ctx, userID).Return(nil)
+
+		restored, _, err := deps.svc.Login(ctx, "a@example.com", "password123")
+		require.NoError(t, err)
+		require.Nil(t, restored.DeletionScheduledAt)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().PurgeScheduledDeletions(ctx, gomock.Any()).Return(int64(2), nil)
+
+		n, err := deps.svc.PurgeDeletedAccounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/rules"
+	"github.com/google/uuid"
+)
+
+type AdherenceSessionRepository interface {
+	GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error)
+	ListPlannedSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error)
+}
+
+// AdherenceService compares what users planned with what they logged.
+type AdherenceService struct {
+	sessions AdherenceSessionRepository
+	profiles ProfileRepository
+}
+
+func NewAdherenceService(sessions AdherenceSessionRepository, profiles ProfileRepository) *AdherenceService {
+	return &AdherenceService{sessions: sessions, profiles: profiles}
+}
+
+// SessionAdherence measures one of the user's sessions against its plan. A session without planned sets
+// has nothing to measure and reports zeros.
+func (s *AdherenceService) SessionAdherence(ctx context.Context, userID, sessionID uuid.UUID) (*models.SessionAdherence, error) {
+	session, err := s.sessions.GetWithSets(ctx, userID, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.SessionAdherence{
+		SessionID:   session.ID,
+		PerformedAt: session.PerformedAt,
+		Status:      session.Status,
+		Adherence:   rules.Measure(session, nil),
+	}, nil
+}
+
+// WeeklyAdherence adds up the adherence of the user's finished planned sessions for each of the last
+// weeks, oldest first and including the current week. Weeks start on Monday in the user's time zone;
+// weeks without planned sessions are reported with zeros.
+func (s *AdherenceService) WeeklyAdherence(ctx context.Context, userID uuid.UUID, weeks int) ([]models.WeeklyAdherence, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(profile)
+
+	first := weekStart(time.Now().In(loc)).AddDate(0, 0, -7*(weeks-1))
+	sessions, err := s.sessions.ListPlannedSessions(ctx, userID, first)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.WeeklyAdherence, weeks)
+	for i := range out {
+		out[i].WeekStart = first.AddDate(0, 0, 7*i)
+	}
+	for _, session := range sessions {
+		i := int(weekStart(session.PerformedAt.In(loc)).Sub(first).Hours()/24+0.5) / 7
+		if i < 0 || i >= weeks {
+			continue
+		}
+		out[i].Sessions++
+		out[i].Adherence = rules.Add(out[i].Adherence, rules.Measure(session, nil))
+	}
+	return out, nil
+}
+
+// weekStart returns midnight of the Monday on or before t, in t's location.
+func weekStart(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=adherence.go -destination=./mocks/adherence_mock.go -package=mocks
+func TestAdherenceService_SessionAdherence(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID, exerciseID := uuid.New(), uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*AdherenceService, *mocks.MockAdherenceSessionRepository) {
+		ctrl := gomock.NewController(t)
+		sessions := mocks.NewMockAdherenceSessionRepository(ctrl)
+		return NewAdherenceService(sessions, mocks.NewMockProfileRepository(ctrl)), sessions
+	}
+
+	t.Run("measures the session against its plan", func(t *testing.T) {
+		svc, sessions := newService(t)
+		sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{
+			ID:     sessionID,
+			Status: models.SessionCompleted,
+			PlannedSets: []models.PlannedSet{
+				{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+				{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+			},
+			Sets: []models.Set{{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100}},
+		}, nil)
+
+		a, err := svc.SessionAdherence(ctx, userID, sessionID)
+		require.NoError(t, err)
+		require.Equal(t, sessionID, a.SessionID)
+		require.Equal(t, models.Adherence{PlannedSets: 2, CompletedSets: 1, PlannedReps: 10, ActualReps: 5, PlannedLoadKG: 1000, ActualLoadKG: 500}, a.Adherence)
+	})
+
+	t.Run("someone else's session", func(t *testing.T) {
+		svc, sessions := newService(t)
+		sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.SessionAdherence(ctx, userID, sessionID)
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestAdherenceService_WeeklyAdherence(t *testing.T) {
+	ctx := context.Background()
+	userID, exerciseID := uuid.New(), uuid.New()
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockAdherenceSessionRepository(ctrl)
+	profiles := mocks.NewMockProfileRepository(ctrl)
+	svc := NewAdherenceService(sessions, profiles)
+
+	loc, err := time.LoadLocation("Pacific/Auckland")
+	require.NoError(t, err)
+	thisWeek := weekStart(time.Now().In(loc))
+	planned := func(at time.Time, done int) *models.Session {
+		s := &models.Session{PerformedAt: at.UTC(), Status: models.SessionCompleted}
+		for i := 0; i < 3; i++ {
+			s.PlannedSets = append(s.PlannedSets, models.PlannedSet{Position: i, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5})
+		}
+		for i := 0; i < done; i++ {
+			s.Sets = append(s.Sets, models.Set{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5})
+		}
+		return s
+	}
+
+	profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "Pacific/Auckland"}, nil)
+	sessions.EXPECT().ListPlannedSessions(ctx, userID, thisWeek.AddDate(0, 0, -14)).Return([]*models.Session{
+		planned(thisWeek.AddDate(0, 0, -14).Add(30*time.Minute), 3),
+		// Sunday night local time is still last week, though it is Sunday morning in UTC
+		planned(thisWeek.Add(-time.Hour), 2),
+		planned(thisWeek.Add(-2*time.Hour), 1),
+	}, nil)
+
+	weekly, err := svc.WeeklyAdherence(ctx, userID, 3)
+	require.NoError(t, err)
+	require.Len(t, weekly, 3)
+	require.Equal(t, thisWeek.AddDate(0, 0, -14), weekly[0].WeekStart)
+	require.Equal(t, 1, weekly[0].Sessions)
+	require.Equal(t, 3, weekly[0].Adherence.CompletedSets)
+	require.Equal(t, 2, weekly[1].Sessions)
+	require.Equal(t, 6, weekly[1].Adherence.PlannedSets)
+	require.Equal(t, 3, weekly[1].Adherence.CompletedSets)
+	require.Equal(t, 0, weekly[2].Sessions, "weeks without plans are reported")
+}
@@ -2,22 +2,70 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
+	"time"
 
+	"github.com/alexanderramin/kalistheniks/internal/mailer"
 	"github.com/alexanderramin/kalistheniks/internal/models"
 	t "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// passwordResetTTL bounds how long a reset link stays valid.
+	passwordResetTTL = time.Hour
+	// emailVerificationTTL bounds how long a verification link stays valid.
+	emailVerificationTTL = 48 * time.Hour
+	// emailChangeTTL bounds how long a link confirming a new address stays valid.
+	emailChangeTTL = 24 * time.Hour
+	// accountDeletionGrace is how long a deleted account can still be restored by signing in.
+	accountDeletionGrace = 30 * 24 * time.Hour
+
+	// maxFailedLogins is the number of consecutive failures allowed before an account is locked.
+	maxFailedLogins = 5
+	// baseLockout is the first lockout duration; it doubles with every further failure up to maxLockout.
+	baseLockout = time.Minute
+	maxLockout  = time.Hour
+)
+
+// dummyPasswordHash is compared against when no real hash is available so that unknown emails
+// and locked accounts take as long to reject as a wrong password. It must use bcrypt.DefaultCost.
+const dummyPasswordHash = "$2a$10$K2O45jxr4ShKBLxiqPjYE.kWr4IRbQD9Sd7Yrup8Y9I1LehnC0Mg2"
+
 type UserRepository interface {
 	Create(ctx context.Context, email, passwordHash string) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error)
+	CreateEmailVerification(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	VerifyEmail(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	RecordFailedLogin(ctx context.Context, userID uuid.UUID) (int, error)
+	LockUntil(ctx context.Context, userID uuid.UUID, until time.Time) error
+	ResetFailedLogins(ctx context.Context, userID uuid.UUID) error
+	SetPendingTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID uuid.UUID) error
+	ConsumeTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, passwordHash string) (int, error)
+	CreateEmailChange(ctx context.Context, userID uuid.UUID, newEmail, tokenHash string, expiresAt time.Time) error
+	ConfirmEmailChange(ctx context.Context, tokenHash string) (*models.User, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	PurgeScheduledDeletions(ctx context.Context, now time.Time) (int64, error)
 }
 
 type AuthService struct {
 	users     UserRepository
+	mailer    mailer.Mailer
 	jwtSecret string
+	baseURL   string
 }
 
 // TODO: move errors to relevant packages
@@ -28,12 +76,35 @@ var (
 	ErrGenerateToken      = errors.New("failed to generate token")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrParseToken         = errors.New("failed to parse token")
+	ErrInvalidResetToken  = errors.New("invalid or expired reset token")
+	ErrResetPassword      = errors.New("failed to reset password")
+	ErrSendMail           = errors.New("failed to send mail")
+	ErrInvalidVerifyToken = errors.New("invalid or expired verification token")
+	ErrAlreadyVerified    = errors.New("email already verified")
+	ErrVerifyEmail        = errors.New("failed to verify email")
+	ErrAccountLocked      = errors.New("account temporarily locked")
+	ErrResetFailedLogins  = errors.New("failed to reset failed logins")
+	ErrEmailTaken         = errors.New("email already registered")
+	ErrMFARequired        = errors.New("second factor required")
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnrolled     = errors.New("two-factor authentication not enrolled")
+	ErrUpdateMFA          = errors.New("failed to update two-factor settings")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrChangePassword     = errors.New("failed to change password")
+	ErrInvalidEmailChange = errors.New("invalid or expired email change token")
+	ErrChangeEmail        = errors.New("failed to change email")
+	ErrSameEmail          = errors.New("new email matches the current one")
+	ErrDeleteAccount      = errors.New("failed to delete account")
 )
 
-func NewAuthService(users UserRepository, jwtSecret string) *AuthService {
+// NewAuthService creates the auth service. baseURL is the public client URL used to build links in emails.
+func NewAuthService(users UserRepository, mail mailer.Mailer, jwtSecret, baseURL string) *AuthService {
 	return &AuthService{
 		users:     users,
+		mailer:    mail,
 		jwtSecret: jwtSecret,
+		baseURL:   baseURL,
 	}
 }
 
@@ -43,39 +114,313 @@ func (s *AuthService) Signup(ctx context.Context, email, password string) (*mode
 		return nil, "", fmt.Errorf("%w: %v", ErrHashPassword, err)
 	}
 
+	// Hashing happens before the lookup so duplicate and new signups cost the same.
+	existing, err := s.users.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+		// Tell the real owner instead of the caller; delivery errors are deliberately not surfaced.
+		_ = s.notifyDuplicateSignup(ctx, existing)
+		return nil, "", ErrEmailTaken
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+
 	user, err := s.users.Create(ctx, email, string(hashed))
+	if isUniqueViolation(err) {
+		// Another signup for the same email won the race; answer as if it had been found above.
+		if existing, err := s.users.FindByEmail(ctx, email); err == nil {
+			_ = s.notifyDuplicateSignup(ctx, existing)
+		}
+		return nil, "", ErrEmailTaken
+	}
 	if err != nil {
 		return nil, "", fmt.Errorf("%w: %v", ErrCreateUser, err)
 	}
 
-	token, err := t.GenerateToken(user.ID, s.jwtSecret)
+	// A failed delivery must not block the new account; the user can ask for another link.
+	_ = s.sendVerification(ctx, user)
+
+	token, err := t.GenerateVersionedToken(user.ID, user.TokenVersion, s.jwtSecret)
 	if err != nil {
 		return nil, "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
 	}
 	return user, token, nil
 }
 
+// Login checks credentials and returns an access token. When the account has two-factor auth enabled it
+// instead returns a short-lived challenge token together with ErrMFARequired; see VerifyMFA.
 func (s *AuthService) Login(ctx context.Context, email, password string) (*models.User, string, error) {
 	user, err := s.users.FindByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Unknown emails are indistinguishable from wrong passwords, including in response time.
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+		return nil, "", ErrInvalidCredentials
+	}
 	if err != nil {
 		return nil, "", fmt.Errorf("%w: %v", ErrFindUser, err)
 	}
 
+	// While locked, don't check the real password so guesses made during the lockout are worthless. The
+	// response matches a wrong password so the lockout doesn't reveal that the email is registered; the
+	// owner hears about it by email instead.
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+		return nil, "", ErrInvalidCredentials
+	}
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidCredentials, lockErr)
+		}
 		return nil, "", ErrInvalidCredentials
 	}
 
-	token, err := t.GenerateToken(user.ID, s.jwtSecret)
+	if user.TOTPEnabledAt != nil {
+		// The password step passed; hand back a challenge instead of an access token. Failures keep
+		// counting until VerifyMFA succeeds, or alternating logins with code guesses would never lock.
+		challenge, err := t.GenerateMFAChallenge(user.ID, s.jwtSecret)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
+		}
+		return user, challenge, ErrMFARequired
+	}
+
+	if err := s.resetFailedLogins(ctx, user); err != nil {
+		return nil, "", err
+	}
+	if err := restoreAccount(ctx, s.users, user); err != nil {
+		return nil, "", err
+	}
+	token, err := t.GenerateVersionedToken(user.ID, user.TokenVersion, s.jwtSecret)
 	if err != nil {
 		return nil, "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
 	}
 	return user, token, nil
 }
 
-func (s *AuthService) VerifyToken(_ context.Context, token string) (string, error) {
-	userID, err := t.ParseToken(token, s.jwtSecret)
+// recordFailedLogin counts a failed attempt and locks the account with exponential backoff
+// once maxFailedLogins is reached, telling the owner by email.
+func (s *AuthService) recordFailedLogin(ctx context.Context, user *models.User) error {
+	attempts, err := s.users.RecordFailedLogin(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if attempts < maxFailedLogins {
+		return nil
+	}
+	until := time.Now().Add(lockoutDuration(attempts))
+	if err := s.users.LockUntil(ctx, user.ID, until); err != nil {
+		return err
+	}
+	// Delivery errors are deliberately not surfaced; the lock is what matters.
+	_ = s.notifyLockout(ctx, user, until)
+	return nil
+}
+
+// resetFailedLogins clears the failure counter and any lock once a login has fully succeeded.
+func (s *AuthService) resetFailedLogins(ctx context.Context, user *models.User) error {
+	if user.FailedLoginAttempts == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	if err := s.users.ResetFailedLogins(ctx, user.ID); err != nil {
+		return fmt.Errorf("%w: %v", ErrResetFailedLogins, err)
+	}
+	return nil
+}
+
+// lockoutDuration returns how long to lock an account after the given number of consecutive failures.
+func lockoutDuration(attempts int) time.Duration {
+	d := baseLockout
+	for i := maxFailedLogins; i < attempts; i++ {
+		d *= 2
+		if d >= maxLockout {
+			return maxLockout
+		}
+	}
+	return d
+}
+
+// VerifyToken validates an access token and rejects tokens revoked by a password change or whose
+// account no longer exists.
+func (s *AuthService) VerifyToken(ctx context.Context, token string) (string, error) {
+	subject, version, err := t.ParseVersionedToken(token, s.jwtSecret)
 	if err != nil {
 		return "", fmt.Errorf("%w: %v", ErrParseToken, err)
 	}
-	return userID, nil
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrParseToken, err)
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrTokenRevoked
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if version != user.TokenVersion {
+		return "", ErrTokenRevoked
+	}
+	return subject, nil
+}
+
+// RequestPasswordReset emails a single-use reset link. Unknown addresses are ignored so callers
+// cannot learn which emails are registered.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.users.FindByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	// Failing from here on would only ever happen for registered emails, so it is not surfaced; the user
+	// can ask again.
+	_ = s.sendPasswordReset(ctx, user)
+	return nil
+}
+
+func (s *AuthService) sendPasswordReset(ctx context.Context, user *models.User) error {
+	raw, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	if err := s.users.CreatePasswordReset(ctx, user.ID, t.HashOpaqueToken(raw), time.Now().Add(passwordResetTTL)); err != nil {
+		return fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password for your account.\n\n"+
+			"Use this link within %d minutes to choose a new password:\n%s\n\n"+
+			"If this wasn't you, you can ignore this email.",
+			int(passwordResetTTL.Minutes()), s.link("/reset-password", raw)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrSendMail, err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a token from RequestPasswordReset. Tokens are single use.
+func (s *AuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	if resetToken == "" {
+		return ErrInvalidResetToken
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrHashPassword, err)
+	}
+
+	_, err = s.users.ResetPassword(ctx, t.HashOpaqueToken(resetToken), string(hashed))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrResetPassword, err)
+	}
+	return nil
+}
+
+// VerifyEmail marks the owner of a verification token as verified. Tokens are single use.
+func (s *AuthService) VerifyEmail(ctx context.Context, verifyToken string) error {
+	if verifyToken == "" {
+		return ErrInvalidVerifyToken
+	}
+	_, err := s.users.VerifyEmail(ctx, t.HashOpaqueToken(verifyToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidVerifyToken
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerifyEmail, err)
+	}
+	return nil
+}
+
+// ResendVerification sends a fresh verification link to an unverified user.
+func (s *AuthService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrAlreadyVerified
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// IsEmailVerified reports whether the user has confirmed their email address.
+func (s *AuthService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	return user.EmailVerifiedAt != nil, nil
+}
+
+func (s *AuthService) sendVerification(ctx context.Context, user *models.User) error {
+	raw, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	if err := s.users.CreateEmailVerification(ctx, user.ID, t.HashOpaqueToken(raw), time.Now().Add(emailVerificationTTL)); err != nil {
+		return fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Welcome to Kalistheniks!\n\n"+
+			"Confirm your email address within %d hours using this link:\n%s",
+			int(emailVerificationTTL.Hours()), s.link("/verify-email", raw)),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrSendMail, err)
+	}
+	return nil
+}
+
+func (s *AuthService) notifyDuplicateSignup(ctx context.Context, user *models.User) error {
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Someone tried to sign up with your email",
+		Body: "Someone tried to create a Kalistheniks account with this email address, " +
+			"but you already have one.\n\n" +
+			"If it was you, log in instead or reset your password here:\n" +
+			s.baseURL + "/forgot-password\n\n" +
+			"If it wasn't you, you can ignore this email.",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrSendMail, err)
+	}
+	return nil
+}
+
+func (s *AuthService) notifyLockout(ctx context.Context, user *models.User, until time.Time) error {
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your account was temporarily locked",
+		Body: "There were too many failed attempts to log in to your Kalistheniks account, so logins are blocked until " +
+			until.UTC().Format(time.RFC1123) + ".\n\n" +
+			"If it wasn't you, someone may be guessing your password. You can reset it here:\n" +
+			s.baseURL + "/forgot-password",
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrSendMail, err)
+	}
+	return nil
+}
+
+// link builds a client URL carrying an opaque token as a query parameter.
+func (s *AuthService) link(path, rawToken string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(rawToken)
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate, such as an email that is
+// already registered.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
 }
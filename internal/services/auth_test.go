@@ -1,15 +1,23 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/alexanderramin/kalistheniks/internal/mailer"
 	"github.com/alexanderramin/kalistheniks/internal/models"
 	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	tok "github.com/alexanderramin/kalistheniks/internal/token"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 //go:generate mockgen -source=auth.go -destination=./mocks/services_mock.go -package=mocks AuthService
@@ -18,16 +26,19 @@ type authDeps struct {
 	ctrl      *gomock.Controller
 	svc       *AuthService
 	usersRepo *mocks.MockUserRepository
+	mail      *bytes.Buffer
 }
 
 func newAuthDeps(t *testing.T) authDeps {
 	ctrl := gomock.NewController(t)
 	t.Cleanup(ctrl.Finish)
 	usersRepo := mocks.NewMockUserRepository(ctrl)
+	mail := &bytes.Buffer{}
 	return authDeps{
 		ctrl:      ctrl,
-		svc:       &AuthService{users: usersRepo, jwtSecret: "testsecret"},
+		svc:       NewAuthService(usersRepo, mailer.NewLogMailer(mail), "testsecret", "https://app.example.com"),
 		usersRepo: usersRepo,
+		mail:      mail,
 	}
 }
 
@@ -40,7 +51,21 @@ func TestAuthService_Signup(t *testing.T) {
 
 	t.Run("successful signup", func(t *testing.T) {
 		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, email).Return(nil, sql.ErrNoRows)
 		deps.usersRepo.EXPECT().Create(ctx, email, gomock.Any()).Return(&models.User{ID: userID, Email: email}, nil)
+		deps.usersRepo.EXPECT().CreateEmailVerification(ctx, userID, gomock.Any(), gomock.Any()).Return(nil)
+		user, token, err := deps.svc.Signup(ctx, email, password)
+		require.NoError(t, err)
+		require.Equal(t, userID, user.ID)
+		require.NotEmpty(t, token)
+		require.Contains(t, deps.mail.String(), "https://app.example.com/verify-email?token=")
+	})
+
+	t.Run("verification mail failure does not block signup", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, email).Return(nil, sql.ErrNoRows)
+		deps.usersRepo.EXPECT().Create(ctx, email, gomock.Any()).Return(&models.User{ID: userID, Email: email}, nil)
+		deps.usersRepo.EXPECT().CreateEmailVerification(ctx, userID, gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 		user, token, err := deps.svc.Signup(ctx, email, password)
 		require.NoError(t, err)
 		require.Equal(t, userID, user.ID)
@@ -49,6 +74,7 @@ func TestAuthService_Signup(t *testing.T) {
 
 	t.Run("repository error", func(t *testing.T) {
 		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, email).Return(nil, sql.ErrNoRows)
 		deps.usersRepo.EXPECT().Create(ctx, email, gomock.Any()).Return(nil, errors.New("db error"))
 		user, token, err := deps.svc.Signup(ctx, email, password)
 		require.ErrorIs(t, err, ErrCreateUser)
@@ -57,6 +83,31 @@ func TestAuthService_Signup(t *testing.T) {
 		require.Empty(t, token)
 
 	})
+
+	t.Run("losing a signup race is a duplicate email", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		gomock.InOrder(
+			deps.usersRepo.EXPECT().FindByEmail(ctx, email).Return(nil, sql.ErrNoRows),
+			deps.usersRepo.EXPECT().Create(ctx, email, gomock.Any()).Return(nil, &pq.Error{Code: "23505"}),
+			deps.usersRepo.EXPECT().FindByEmail(ctx, email).Return(&models.User{ID: userID, Email: email}, nil),
+		)
+		user, token, err := deps.svc.Signup(ctx, email, password)
+		require.ErrorIs(t, err, ErrEmailTaken)
+		require.NotErrorIs(t, err, ErrCreateUser)
+		require.Empty(t, user)
+		require.Empty(t, token)
+		require.Contains(t, deps.mail.String(), "Someone tried to sign up with your email")
+	})
+
+	t.Run("duplicate email notifies owner", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, email).Return(&models.User{ID: userID, Email: email}, nil)
+		user, token, err := deps.svc.Signup(ctx, email, password)
+		require.ErrorIs(t, err, ErrEmailTaken)
+		require.Empty(t, user)
+		require.Empty(t, token)
+		require.Contains(t, deps.mail.String(), "Someone tried to sign up with your email")
+	})
 }
 
 func TestAuthService_Login(t *testing.T) {
@@ -81,13 +132,199 @@ func TestAuthService_Login(t *testing.T) {
 		require.Empty(t, user)
 		require.Empty(t, token)
 	})
+	t.Run("unknown email looks like wrong password", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(gomock.Any(), "nobody@example.com").Return(nil, sql.ErrNoRows)
+		user, token, err := deps.svc.Login(context.Background(), "nobody@example.com", "password123")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+		require.NotErrorIs(t, err, ErrFindUser)
+		require.Empty(t, user)
+		require.Empty(t, token)
+	})
+
 	t.Run("invalid password", func(t *testing.T) {
 		deps := newAuthDeps(t)
 		deps.usersRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&models.User{ID: userID, PasswordHash: hashedPassword}, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(gomock.Any(), userID).Return(1, nil)
 		user, token, err := deps.svc.Login(context.Background(), "test@example.com", "wrongpassword")
 		require.ErrorIs(t, err, ErrInvalidCredentials)
 		require.Empty(t, user)
 		require.Empty(t, token)
 
 	})
+
+	t.Run("locks account after too many failures", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&models.User{ID: userID, Email: "test@example.com", PasswordHash: hashedPassword, FailedLoginAttempts: 4}, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(gomock.Any(), userID).Return(maxFailedLogins, nil)
+		deps.usersRepo.EXPECT().LockUntil(gomock.Any(), userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, until time.Time) error {
+				require.WithinDuration(t, time.Now().Add(baseLockout), until, 5*time.Second)
+				return nil
+			})
+		_, _, err := deps.svc.Login(context.Background(), "test@example.com", "wrongpassword")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+		require.Contains(t, deps.mail.String(), "To: test@example.com")
+		require.Contains(t, deps.mail.String(), "temporarily locked")
+	})
+
+	t.Run("locked account rejects correct password", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		lockedUntil := time.Now().Add(time.Minute)
+		deps.usersRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&models.User{ID: userID, PasswordHash: hashedPassword, LockedUntil: &lockedUntil}, nil)
+		user, token, err := deps.svc.Login(context.Background(), "test@example.com", "password123")
+		require.ErrorIs(t, err, ErrInvalidCredentials, "a lockout must not reveal that the email exists")
+		require.Empty(t, user)
+		require.Empty(t, token)
+	})
+
+	t.Run("successful login clears failures", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		expired := time.Now().Add(-time.Minute)
+		deps.usersRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&models.User{ID: userID, PasswordHash: hashedPassword, FailedLoginAttempts: 6, LockedUntil: &expired}, nil)
+		deps.usersRepo.EXPECT().ResetFailedLogins(gomock.Any(), userID).Return(nil)
+		user, token, err := deps.svc.Login(context.Background(), "test@example.com", "password123")
+		require.NoError(t, err)
+		require.Equal(t, userID, user.ID)
+		require.NotEmpty(t, token)
+	})
+
+	t.Run("failing to clear failures is not a lookup error", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(gomock.Any(), "test@example.com").Return(&models.User{ID: userID, PasswordHash: hashedPassword, FailedLoginAttempts: 2}, nil)
+		deps.usersRepo.EXPECT().ResetFailedLogins(gomock.Any(), userID).Return(errors.New("connection reset"))
+		_, _, err := deps.svc.Login(context.Background(), "test@example.com", "password123")
+		require.ErrorIs(t, err, ErrResetFailedLogins)
+		require.NotErrorIs(t, err, ErrFindUser)
+	})
+}
+
+func TestDummyPasswordHash(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.DefaultCost, cost)
+}
+
+func TestLockoutDuration(t *testing.T) {
+	require.Equal(t, time.Minute, lockoutDuration(maxFailedLogins))
+	require.Equal(t, 2*time.Minute, lockoutDuration(maxFailedLogins+1))
+	require.Equal(t, 8*time.Minute, lockoutDuration(maxFailedLogins+3))
+	require.Equal(t, maxLockout, lockoutDuration(maxFailedLogins+20))
+}
+
+func TestAuthService_RequestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("stores hashed token and emails link", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		var storedHash string
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(&models.User{ID: userID, Email: "test@example.com"}, nil)
+		deps.usersRepo.EXPECT().CreatePasswordReset(ctx, userID, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string, expiresAt time.Time) error {
+				storedHash = hash
+				require.WithinDuration(t, time.Now().Add(passwordResetTTL), expiresAt, time.Minute)
+				return nil
+			})
+
+		err := deps.svc.RequestPasswordReset(ctx, "test@example.com")
+		require.NoError(t, err)
+
+		match := regexp.MustCompile(`https://app\.example\.com/reset-password\?token=(\S+)`).FindStringSubmatch(deps.mail.String())
+		require.Len(t, match, 2)
+		require.Equal(t, tok.HashOpaqueToken(match[1]), storedHash)
+	})
+
+	t.Run("unknown email is silently ignored", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "nobody@example.com").Return(nil, sql.ErrNoRows)
+
+		err := deps.svc.RequestPasswordReset(ctx, "nobody@example.com")
+		require.NoError(t, err)
+		require.Empty(t, deps.mail.String())
+	})
+
+	t.Run("failures for a registered email look like an unknown one", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(&models.User{ID: userID, Email: "test@example.com"}, nil)
+		deps.usersRepo.EXPECT().CreatePasswordReset(ctx, userID, gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		err := deps.svc.RequestPasswordReset(ctx, "test@example.com")
+		require.NoError(t, err)
+		require.Empty(t, deps.mail.String())
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(nil, errors.New("db error"))
+
+		err := deps.svc.RequestPasswordReset(ctx, "test@example.com")
+		require.ErrorIs(t, err, ErrFindUser)
+	})
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("resets password with valid token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().ResetPassword(ctx, tok.HashOpaqueToken("raw-token"), gomock.Any()).Return(uuid.New(), nil)
+
+		err := deps.svc.ResetPassword(ctx, "raw-token", "NewPassword1")
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid or used token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().ResetPassword(ctx, gomock.Any(), gomock.Any()).Return(uuid.Nil, sql.ErrNoRows)
+
+		err := deps.svc.ResetPassword(ctx, "raw-token", "NewPassword1")
+		require.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		err := deps.svc.ResetPassword(ctx, "", "NewPassword1")
+		require.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("verifies with valid token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().VerifyEmail(ctx, tok.HashOpaqueToken("raw-token")).Return(uuid.New(), nil)
+
+		require.NoError(t, deps.svc.VerifyEmail(ctx, "raw-token"))
+	})
+
+	t.Run("invalid or used token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().VerifyEmail(ctx, gomock.Any()).Return(uuid.Nil, sql.ErrNoRows)
+
+		require.ErrorIs(t, deps.svc.VerifyEmail(ctx, "raw-token"), ErrInvalidVerifyToken)
+	})
+}
+
+func TestAuthService_ResendVerification(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("sends new link to unverified user", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, Email: "test@example.com"}, nil)
+		deps.usersRepo.EXPECT().CreateEmailVerification(ctx, userID, gomock.Any(), gomock.Any()).Return(nil)
+
+		require.NoError(t, deps.svc.ResendVerification(ctx, userID))
+		require.Contains(t, deps.mail.String(), "To: test@example.com")
+	})
+
+	t.Run("already verified", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		verifiedAt := time.Now()
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, EmailVerifiedAt: &verifiedAt}, nil)
+
+		require.ErrorIs(t, deps.svc.ResendVerification(ctx, userID), ErrAlreadyVerified)
+	})
 }
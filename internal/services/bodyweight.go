@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type BodyweightRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, weightKG float64, measuredAt time.Time) (*models.BodyweightEntry, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error)
+	Nearest(ctx context.Context, userID uuid.UUID, at time.Time) (*models.BodyweightEntry, error)
+}
+
+type ExerciseRepository interface {
+	FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Exercise, error)
+}
+
+type MetricsSessionRepository interface {
+	GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error)
+}
+
+var (
+	ErrLogBodyweight   = errors.New("failed to log bodyweight")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrComputeMetrics  = errors.New("failed to compute metrics")
+)
+
+// BodyweightService keeps the bodyweight log and derives bodyweight-aware load metrics from it.
+type BodyweightService struct {
+	entries   BodyweightRepository
+	sessions  MetricsSessionRepository
+	exercises ExerciseRepository
+	profiles  ProfileRepository
+}
+
+func NewBodyweightService(entries BodyweightRepository, sessions MetricsSessionRepository, exercises ExerciseRepository, profiles ProfileRepository) *BodyweightService {
+	return &BodyweightService{entries: entries, sessions: sessions, exercises: exercises, profiles: profiles}
+}
+
+// LogBodyweight records a measurement; measuredAt defaults to now.
+func (s *BodyweightService) LogBodyweight(ctx context.Context, userID uuid.UUID, weightKG float64, measuredAt *time.Time) (*models.BodyweightEntry, error) {
+	when := time.Now().UTC()
+	if measuredAt != nil {
+		when = measuredAt.UTC()
+	}
+	entry, err := s.entries.Create(ctx, userID, weightKG, when)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLogBodyweight, err)
+	}
+	return entry, nil
+}
+
+func (s *BodyweightService) ListBodyweight(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error) {
+	return s.entries.List(ctx, userID)
+}
+
+// SessionMetrics computes effective load, tonnage and load relative to bodyweight for every set of a
+// session. Bodyweight comes from the log entry closest in time to the session, falling back to the
+// profile when nothing has been logged. Every set is listed, but only sets of the given kinds add to
+// the tonnage; no kinds means working sets only.
+func (s *BodyweightService) SessionMetrics(ctx context.Context, userID, sessionID uuid.UUID, kinds []string) (*models.SessionMetrics, error) {
+	if len(kinds) == 0 {
+		kinds = []string{models.SetKindWorking}
+	}
+
+	session, err := s.sessions.GetWithSets(ctx, userID, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrComputeMetrics, err)
+	}
+
+	bodyweight, err := s.bodyweightAt(ctx, userID, session.PerformedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(session.Sets))
+	for _, set := range session.Sets {
+		ids = append(ids, set.ExerciseID)
+	}
+	exercises, err := s.exercises.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrComputeMetrics, err)
+	}
+
+	metrics := &models.SessionMetrics{
+		SessionID:    session.ID,
+		PerformedAt:  session.PerformedAt,
+		BodyweightKG: bodyweight,
+		Sets:         make([]models.SetLoad, 0, len(session.Sets)),
+	}
+	for _, set := range session.Sets {
+		load := setLoad(set, exercises[set.ExerciseID].BodyweightFraction, bodyweight)
+		if slices.Contains(kinds, set.Kind) {
+			metrics.TonnageKG += load.EffectiveKG * float64(load.Reps)
+		}
+		metrics.Sets = append(metrics.Sets, load)
+	}
+	metrics.TonnageKG = round2(metrics.TonnageKG)
+	return metrics, nil
+}
+
+// bodyweightAt returns the bodyweight closest to at, or nil when the user never recorded one.
+func (s *BodyweightService) bodyweightAt(ctx context.Context, userID uuid.UUID, at time.Time) (*float64, error) {
+	entry, err := s.entries.Nearest(ctx, userID, at)
+	if err == nil {
+		return &entry.WeightKG, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %v", ErrComputeMetrics, err)
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindProfile, err)
+	}
+	return profile.BodyweightKG, nil
+}
+
+// setLoad adds the exercise's share of bodyweight to the external load. Without a known bodyweight
+// only the external load counts and no relative figure is given.
+func setLoad(set models.Set, fraction float64, bodyweightKG *float64) models.SetLoad {
+	load := models.SetLoad{
+		SetID:       set.ID,
+		ExerciseID:  set.ExerciseID,
+		Kind:        set.Kind,
+		Reps:        set.Reps,
+		AddedKG:     set.WeightKG,
+		EffectiveKG: set.WeightKG,
+	}
+	if bodyweightKG == nil || *bodyweightKG <= 0 {
+		return load
+	}
+	bodyweight := *bodyweightKG
+	load.EffectiveKG = round2(load.EffectiveKG + fraction*bodyweight)
+	relative := round2(load.EffectiveKG / bodyweight)
+	load.Relative = &relative
+	return load
+}
+
+// round2 rounds to two decimals, the precision weights are stored with.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=bodyweight.go -destination=./mocks/bodyweight_mock.go -package=mocks
+
+type bodyweightDeps struct {
+	svc       *BodyweightService
+	entries   *mocks.MockBodyweightRepository
+	sessions  *mocks.MockMetricsSessionRepository
+	exercises *mocks.MockExerciseRepository
+	profiles  *mocks.MockProfileRepository
+}
+
+func newBodyweightDeps(t *testing.T) bodyweightDeps {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	d := bodyweightDeps{
+		entries:   mocks.NewMockBodyweightRepository(ctrl),
+		sessions:  mocks.NewMockMetricsSessionRepository(ctrl),
+		exercises: mocks.NewMockExerciseRepository(ctrl),
+		profiles:  mocks.NewMockProfileRepository(ctrl),
+	}
+	d.svc = NewBodyweightService(d.entries, d.sessions, d.exercises, d.profiles)
+	return d
+}
+
+func TestBodyweightService_LogBodyweight(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("defaults to now", func(t *testing.T) {
+		deps := newBodyweightDeps(t)
+		deps.entries.EXPECT().Create(ctx, userID, 80.5, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, w float64, at time.Time) (*models.BodyweightEntry, error) {
+				require.WithinDuration(t, time.Now(), at, time.Minute)
+				return &models.BodyweightEntry{UserID: userID, WeightKG: w, MeasuredAt: at}, nil
+			})
+
+		entry, err := deps.svc.LogBodyweight(ctx, userID, 80.5, nil)
+		require.NoError(t, err)
+		require.Equal(t, 80.5, entry.WeightKG)
+	})
+}
+
+func TestBodyweightService_SessionMetrics(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+	pushUp, bench := uuid.New(), uuid.New()
+	performedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	session := &models.Session{ID: sessionID, UserID: userID, PerformedAt: performedAt, Sets: []models.Set{
+		{ExerciseID: pushUp, Kind: models.SetKindWorking, Reps: 10, WeightKG: 0},
+		{ExerciseID: pushUp, Kind: models.SetKindWorking, Reps: 5, WeightKG: 10},
+		{ExerciseID: bench, Kind: models.SetKindWorking, Reps: 5, WeightKG: 60},
+	}}
+	exercises := map[uuid.UUID]models.Exercise{
+		pushUp: {ID: pushUp, BodyweightFraction: 0.64},
+		bench:  {ID: bench},
+	}
+
+	t.Run("uses nearest logged bodyweight", func(t *testing.T) {
+		deps := newBodyweightDeps(t)
+		deps.sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(session, nil)
+		deps.entries.EXPECT().Nearest(ctx, userID, performedAt).Return(&models.BodyweightEntry{WeightKG: 80}, nil)
+		deps.exercises.EXPECT().FindByIDs(ctx, gomock.Any()).Return(exercises, nil)
+
+		m, err := deps.svc.SessionMetrics(ctx, userID, sessionID, nil)
+		require.NoError(t, err)
+		require.Equal(t, 80.0, *m.BodyweightKG)
+		require.Equal(t, 51.2, m.Sets[0].EffectiveKG)
+		require.Equal(t, 0.64, *m.Sets[0].Relative)
+		require.Equal(t, 61.2, m.Sets[1].EffectiveKG)
+		require.Equal(t, 60.0, m.Sets[2].EffectiveKG)
+		require.Equal(t, 0.75, *m.Sets[2].Relative)
+		require.Equal(t, 51.2*10+61.2*5+60*5, m.TonnageKG)
+	})
+
+	t.Run("falls back to profile bodyweight", func(t *testing.T) {
+		deps := newBodyweightDeps(t)
+		profileWeight := 70.0
+		deps.sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(session, nil)
+		deps.entries.EXPECT().Nearest(ctx, userID, performedAt).Return(nil, sql.ErrNoRows)
+		deps.profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{BodyweightKG: &profileWeight}, nil)
+		deps.exercises.EXPECT().FindByIDs(ctx, gomock.Any()).Return(exercises, nil)
+
+		m, err := deps.svc.SessionMetrics(ctx, userID, sessionID, nil)
+		require.NoError(t, err)
+		require.Equal(t, 44.8, m.Sets[0].EffectiveKG)
+	})
+
+	t.Run("unknown bodyweight counts external load only", func(t *testing.T) {
+		deps := newBodyweightDeps(t)
+		deps.sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(session, nil)
+		deps.entries.EXPECT().Nearest(ctx, userID, performedAt).Return(nil, sql.ErrNoRows)
+		deps.profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{}, nil)
+		deps.exercises.EXPECT().FindByIDs(ctx, gomock.Any()).Return(exercises, nil)
+
+		m, err := deps.svc.SessionMetrics(ctx, userID, sessionID, nil)
+		require.NoError(t, err)
+		require.Nil(t, m.BodyweightKG)
+		require.Equal(t, 0.0, m.Sets[0].EffectiveKG)
+		require.Nil(t, m.Sets[0].Relative)
+		require.Equal(t, 350.0, m.TonnageKG)
+	})
+
+	t.Run("tonnage counts working sets unless asked", func(t *testing.T) {
+		withWarmup := &models.Session{ID: sessionID, UserID: userID, PerformedAt: performedAt, Sets: []models.Set{
+			{ExerciseID: bench, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20},
+			{ExerciseID: bench, Kind: models.SetKindWorking, Reps: 5, WeightKG: 60},
+			{ExerciseID: bench, Kind: models.SetKindAMRAP, Reps: 8, WeightKG: 50},
+		}}
+		for _, tc := range []struct {
+			kinds   []string
+			tonnage float64
+		}{
+			{nil, 300},
+			{[]string{models.SetKindWorking, models.SetKindAMRAP}, 700},
+			{[]string{models.SetKindWarmup, models.SetKindWorking, models.SetKindAMRAP}, 900},
+		} {
+			deps := newBodyweightDeps(t)
+			deps.sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(withWarmup, nil)
+			deps.entries.EXPECT().Nearest(ctx, userID, performedAt).Return(&models.BodyweightEntry{WeightKG: 80}, nil)
+			deps.exercises.EXPECT().FindByIDs(ctx, gomock.Any()).Return(exercises, nil)
+
+			m, err := deps.svc.SessionMetrics(ctx, userID, sessionID, tc.kinds)
+			require.NoError(t, err)
+			require.Len(t, m.Sets, 3, "every set is listed")
+			require.Equal(t, models.SetKindWarmup, m.Sets[0].Kind)
+			require.Equal(t, tc.tonnage, m.TonnageKG, tc.kinds)
+		}
+	})
+
+	t.Run("someone else's session", func(t *testing.T) {
+		deps := newBodyweightDeps(t)
+		deps.sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(nil, sql.ErrNoRows)
+
+		_, err := deps.svc.SessionMetrics(ctx, userID, sessionID, nil)
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	t "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/google/uuid"
+)
+
+type CalendarSessionRepository interface {
+	SessionBelongsToUser(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (bool, error)
+	ListBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Session, error)
+	ListOverduePlanned(ctx context.Context, before time.Time) ([]*models.Session, error)
+	Reschedule(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error)
+	RescheduleMissed(ctx context.Context, userID, sessionID uuid.UUID, plannedAt, at time.Time) (*models.Session, error)
+	AbandonPlanned(ctx context.Context, userID, sessionID uuid.UUID, plannedAt, at time.Time) (*models.Session, error)
+	ListUpcomingPlanned(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Session, error)
+}
+
+type CalendarFeedRepository interface {
+	SetCalendarFeedToken(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	DeleteCalendarFeedToken(ctx context.Context, userID uuid.UUID) error
+	FindByCalendarFeedToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+// maxCalendarDays is the longest range the calendar covers in one request.
+const maxCalendarDays = 92
+
+// maxMissedReschedules is how often a missed session is moved before it is abandoned.
+const maxMissedReschedules = 3
+
+var (
+	ErrCalendarRange        = errors.New("invalid calendar range")
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+)
+
+// CalendarService lays sessions out on the user's calendar and keeps planned sessions from falling
+// behind.
+type CalendarService struct {
+	sessions  CalendarSessionRepository
+	profiles  ProfileRepository
+	feeds     CalendarFeedRepository
+	exercises ExerciseRepository
+}
+
+func NewCalendarService(sessions CalendarSessionRepository, profiles ProfileRepository, feeds CalendarFeedRepository, exercises ExerciseRepository) *CalendarService {
+	return &CalendarService{sessions: sessions, profiles: profiles, feeds: feeds, exercises: exercises}
+}
+
+// Calendar returns every day from one date to another, both included, in the user's time zone, with
+// the sessions performed or planned on each. Only the year, month and day of from and to are used.
+func (s *CalendarService) Calendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.CalendarDay, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(profile)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if !end.After(start) || end.After(start.AddDate(0, 0, maxCalendarDays)) {
+		return nil, ErrCalendarRange
+	}
+
+	sessions, err := s.sessions.ListBetween(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	training := trainingWeekdays(profile.TrainingDays)
+	today := dayStart(time.Now().In(loc))
+	var days []models.CalendarDay
+	byDate := make(map[string]int)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		byDate[d.Format(time.DateOnly)] = len(days)
+		days = append(days, models.CalendarDay{Date: d, TrainingDay: training[d.Weekday()], Sessions: []*models.Session{}})
+	}
+	for _, session := range sessions {
+		i, ok := byDate[session.PerformedAt.In(loc).Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		days[i].Sessions = append(days[i].Sessions, session)
+		if session.Status == models.SessionPlanned && days[i].Date.Before(today) {
+			days[i].Missed = true
+		}
+	}
+	return days, nil
+}
+
+// Reschedule moves one of the user's planned sessions to another time.
+func (s *CalendarService) Reschedule(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error) {
+	owned, err := s.sessions.SessionBelongsToUser(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, ErrSessionNotFound
+	}
+	session, err := s.sessions.Reschedule(ctx, userID, sessionID, at.UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionState
+	}
+	return session, err
+}
+
+// RescheduleMissed finds planned sessions whose day has ended, in their user's time zone, without them
+// being started. Each moves to the user's next training day from today at the time it was planned for,
+// or to today when the user has no training days; several missed sessions of one user take successive
+// training days. A session missed maxMissedReschedules times is abandoned instead.
+func (s *CalendarService) RescheduleMissed(ctx context.Context) (rescheduled, abandoned int, err error) {
+	now := time.Now()
+	overdue, err := s.sessions.ListOverduePlanned(ctx, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	profiles := make(map[uuid.UUID]*models.Profile)
+	lastDay := make(map[uuid.UUID]time.Time)
+	for _, session := range overdue {
+		profile, ok := profiles[session.UserID]
+		if !ok {
+			profile, err = s.profiles.GetProfile(ctx, session.UserID)
+			if err != nil {
+				return rescheduled, abandoned, err
+			}
+			profiles[session.UserID] = profile
+		}
+		loc := userLocation(profile)
+		today := dayStart(now.In(loc))
+		planned := session.PerformedAt.In(loc)
+		if !dayStart(planned).Before(today) {
+			continue
+		}
+
+		if session.MissedCount >= maxMissedReschedules {
+			_, err := s.sessions.AbandonPlanned(ctx, session.UserID, session.ID, session.PerformedAt, now.UTC())
+			if errors.Is(err, sql.ErrNoRows) {
+				continue // started or moved since it was listed
+			}
+			if err != nil {
+				return rescheduled, abandoned, err
+			}
+			abandoned++
+			continue
+		}
+
+		from := today
+		if last, ok := lastDay[session.UserID]; ok && !last.Before(from) {
+			from = last.AddDate(0, 0, 1)
+		}
+		day := nextTrainingDay(from, trainingWeekdays(profile.TrainingDays))
+		at := time.Date(day.Year(), day.Month(), day.Day(), planned.Hour(), planned.Minute(), planned.Second(), 0, loc)
+		_, err := s.sessions.RescheduleMissed(ctx, session.UserID, session.ID, session.PerformedAt, at.UTC())
+		if errors.Is(err, sql.ErrNoRows) {
+			continue // started or moved since it was listed, possibly by another instance
+		}
+		if err != nil {
+			return rescheduled, abandoned, err
+		}
+		lastDay[session.UserID] = day
+		rescheduled++
+	}
+	return rescheduled, abandoned, nil
+}
+
+// IssueFeedToken creates a secret token for the user's calendar feed and returns it. Only its hash is
+// stored; a new token replaces the previous one, which stops working.
+func (s *CalendarService) IssueFeedToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	raw, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.feeds.SetCalendarFeedToken(ctx, userID, t.HashOpaqueToken(raw)); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// RevokeFeedToken turns the user's calendar feed off.
+func (s *CalendarService) RevokeFeedToken(ctx context.Context, userID uuid.UUID) error {
+	err := s.feeds.DeleteCalendarFeedToken(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCalendarFeedNotFound
+	}
+	return err
+}
+
+// Feed returns the calendar feed a token gives access to: the owner's planned sessions from the start of
+// today in their time zone on, so a session planned for earlier today stays visible until it starts.
+func (s *CalendarService) Feed(ctx context.Context, token string) (*models.CalendarFeed, error) {
+	userID, err := s.feeds.FindByCalendarFeedToken(ctx, t.HashOpaqueToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := dayStart(time.Now().In(userLocation(profile)))
+	sessions, err := s.sessions.ListUpcomingPlanned(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, session := range sessions {
+		for _, p := range session.PlannedSets {
+			if !seen[p.ExerciseID] {
+				seen[p.ExerciseID] = true
+				ids = append(ids, p.ExerciseID)
+			}
+		}
+	}
+	exercises := map[uuid.UUID]models.Exercise{}
+	if len(ids) > 0 {
+		exercises, err = s.exercises.FindByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &models.CalendarFeed{Profile: profile, Sessions: sessions, Exercises: exercises}, nil
+}
+
+// userLocation is the user's time zone, or UTC when it cannot be loaded.
+func userLocation(profile *models.Profile) *time.Location {
+	loc, err := time.LoadLocation(profile.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// dayStart returns midnight at the start of t's day, in t's location.
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// trainingWeekdays turns weekday names such as "monday" into a set of weekdays.
+func trainingWeekdays(names []string) map[time.Weekday]bool {
+	days := make(map[time.Weekday]bool, len(names))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		for _, name := range names {
+			if strings.EqualFold(name, d.String()) {
+				days[d] = true
+			}
+		}
+	}
+	return days
+}
+
+// nextTrainingDay returns the first training day on or after from, or from itself when there are no
+// training days.
+func nextTrainingDay(from time.Time, training map[time.Weekday]bool) time.Time {
+	for i := 0; i < 7; i++ {
+		if d := from.AddDate(0, 0, i); training[d.Weekday()] {
+			return d
+		}
+	}
+	return from
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=calendar.go -destination=./mocks/calendar_mock.go -package=mocks
+func TestCalendarService_Calendar(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	profile := &models.Profile{UserID: userID, TimeZone: "America/New_York", TrainingDays: []string{"monday", "thursday"}}
+
+	newService := func(t *testing.T) (*CalendarService, *mocks.MockCalendarSessionRepository, *mocks.MockProfileRepository) {
+		ctrl := gomock.NewController(t)
+		sessions := mocks.NewMockCalendarSessionRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		return NewCalendarService(sessions, profiles, mocks.NewMockCalendarFeedRepository(ctrl), mocks.NewMockExerciseRepository(ctrl)), sessions, profiles
+	}
+
+	t.Run("lays sessions out by local day", func(t *testing.T) {
+		svc, sessions, profiles := newService(t)
+		from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC) // a Monday
+		to := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+		start := time.Date(2025, 3, 3, 0, 0, 0, 0, loc)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(profile, nil)
+		done := &models.Session{ID: uuid.New(), Status: models.SessionCompleted, PerformedAt: start.Add(18 * time.Hour).UTC()}
+		// 01:00 UTC on Thursday is still Wednesday evening in New York
+		late := &models.Session{ID: uuid.New(), Status: models.SessionCompleted, PerformedAt: time.Date(2025, 3, 6, 1, 0, 0, 0, time.UTC)}
+		missed := &models.Session{ID: uuid.New(), Status: models.SessionPlanned, PerformedAt: start.AddDate(0, 0, 3).Add(7 * time.Hour).UTC()}
+		sessions.EXPECT().ListBetween(ctx, userID, start, start.AddDate(0, 0, 4)).Return([]*models.Session{done, late, missed}, nil)
+
+		days, err := svc.Calendar(ctx, userID, from, to)
+		require.NoError(t, err)
+		require.Len(t, days, 4)
+		require.True(t, days[0].TrainingDay)
+		require.Equal(t, []*models.Session{done}, days[0].Sessions)
+		require.False(t, days[1].TrainingDay)
+		require.Empty(t, days[1].Sessions)
+		require.Equal(t, []*models.Session{late}, days[2].Sessions)
+		require.True(t, days[3].TrainingDay)
+		require.True(t, days[3].Missed, "a planned session on a past day was missed")
+		require.False(t, days[0].Missed)
+	})
+
+	t.Run("range ends before it starts", func(t *testing.T) {
+		svc, _, profiles := newService(t)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(profile, nil)
+		day := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+		_, err := svc.Calendar(ctx, userID, day, day.AddDate(0, 0, -1))
+		require.ErrorIs(t, err, ErrCalendarRange)
+	})
+
+	t.Run("range too long", func(t *testing.T) {
+		svc, _, profiles := newService(t)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(profile, nil)
+		day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		_, err := svc.Calendar(ctx, userID, day, day.AddDate(0, 0, maxCalendarDays))
+		require.ErrorIs(t, err, ErrCalendarRange)
+	})
+}
+
+func TestCalendarService_Reschedule(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+	at := time.Date(2025, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	newService := func(t *testing.T) (*CalendarService, *mocks.MockCalendarSessionRepository) {
+		ctrl := gomock.NewController(t)
+		sessions := mocks.NewMockCalendarSessionRepository(ctrl)
+		return NewCalendarService(sessions, mocks.NewMockProfileRepository(ctrl), mocks.NewMockCalendarFeedRepository(ctrl), mocks.NewMockExerciseRepository(ctrl)), sessions
+	}
+
+	t.Run("moves a planned session", func(t *testing.T) {
+		svc, sessions := newService(t)
+		sessions.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		sessions.EXPECT().Reschedule(ctx, userID, sessionID, at).Return(&models.Session{ID: sessionID, PerformedAt: at}, nil)
+
+		session, err := svc.Reschedule(ctx, userID, sessionID, at)
+		require.NoError(t, err)
+		require.Equal(t, at, session.PerformedAt)
+	})
+
+	t.Run("only planned sessions move", func(t *testing.T) {
+		svc, sessions := newService(t)
+		sessions.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		sessions.EXPECT().Reschedule(ctx, userID, sessionID, at).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.Reschedule(ctx, userID, sessionID, at)
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("someone else's session", func(t *testing.T) {
+		svc, sessions := newService(t)
+		sessions.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(false, nil)
+
+		_, err := svc.Reschedule(ctx, userID, sessionID, at)
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestCalendarService_RescheduleMissed(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockCalendarSessionRepository(ctrl)
+	profiles := mocks.NewMockProfileRepository(ctrl)
+	svc := NewCalendarService(sessions, profiles, mocks.NewMockCalendarFeedRepository(ctrl), mocks.NewMockExerciseRepository(ctrl))
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	today := dayStart(time.Now().In(loc))
+	// Train on the weekday after today only, so every move lands on a known day
+	tomorrow := today.AddDate(0, 0, 1)
+	profile := &models.Profile{UserID: userID, TimeZone: "Europe/Berlin", TrainingDays: []string{tomorrow.Weekday().String()}}
+
+	at := func(day time.Time, hour int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, 30, 0, 0, loc).UTC()
+	}
+	first := &models.Session{ID: uuid.New(), UserID: userID, Status: models.SessionPlanned, PerformedAt: at(today.AddDate(0, 0, -2), 7)}
+	second := &models.Session{ID: uuid.New(), UserID: userID, Status: models.SessionPlanned, PerformedAt: at(today.AddDate(0, 0, -1), 18)}
+	givenUp := &models.Session{ID: uuid.New(), UserID: userID, Status: models.SessionPlanned, PerformedAt: at(today.AddDate(0, 0, -1), 9), MissedCount: maxMissedReschedules}
+	laterToday := &models.Session{ID: uuid.New(), UserID: userID, Status: models.SessionPlanned, PerformedAt: today.Add(time.Minute).UTC()}
+
+	sessions.EXPECT().ListOverduePlanned(ctx, gomock.Any()).Return([]*models.Session{first, givenUp, second, laterToday}, nil)
+	profiles.EXPECT().GetProfile(ctx, userID).Return(profile, nil)
+	sessions.EXPECT().RescheduleMissed(ctx, userID, first.ID, first.PerformedAt, at(tomorrow, 7)).Return(first, nil)
+	sessions.EXPECT().AbandonPlanned(ctx, userID, givenUp.ID, givenUp.PerformedAt, gomock.Any()).Return(givenUp, nil)
+	sessions.EXPECT().RescheduleMissed(ctx, userID, second.ID, second.PerformedAt, at(tomorrow.AddDate(0, 0, 7), 18)).Return(second, nil)
+
+	moved, abandoned, err := svc.RescheduleMissed(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, moved)
+	require.Equal(t, 1, abandoned)
+
+	t.Run("sessions moved since they were listed are skipped", func(t *testing.T) {
+		sessions.EXPECT().ListOverduePlanned(ctx, gomock.Any()).Return([]*models.Session{first, givenUp}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(profile, nil)
+		sessions.EXPECT().RescheduleMissed(ctx, userID, first.ID, first.PerformedAt, at(tomorrow, 7)).Return(nil, sql.ErrNoRows)
+		sessions.EXPECT().AbandonPlanned(ctx, userID, givenUp.ID, givenUp.PerformedAt, gomock.Any()).Return(nil, sql.ErrNoRows)
+
+		moved, abandoned, err := svc.RescheduleMissed(ctx)
+		require.NoError(t, err)
+		require.Zero(t, moved)
+		require.Zero(t, abandoned)
+	})
+}
+
+func TestCalendarService_Feed(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	squat, plank := uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*CalendarService, *mocks.MockCalendarSessionRepository, *mocks.MockProfileRepository, *mocks.MockCalendarFeedRepository, *mocks.MockExerciseRepository) {
+		ctrl := gomock.NewController(t)
+		sessions := mocks.NewMockCalendarSessionRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		feeds := mocks.NewMockCalendarFeedRepository(ctrl)
+		exercises := mocks.NewMockExerciseRepository(ctrl)
+		return NewCalendarService(sessions, profiles, feeds, exercises), sessions, profiles, feeds, exercises
+	}
+
+	t.Run("issued tokens are stored hashed and open the feed", func(t *testing.T) {
+		svc, sessions, profiles, feeds, exercises := newService(t)
+		var stored string
+		feeds.EXPECT().SetCalendarFeedToken(ctx, userID, gomock.Any()).DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string) error {
+			stored = hash
+			return nil
+		})
+		token, err := svc.IssueFeedToken(ctx, userID)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+		require.NotEqual(t, token, stored)
+
+		loc, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+		feeds.EXPECT().FindByCalendarFeedToken(ctx, stored).Return(userID, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "Asia/Tokyo"}, nil)
+		sessions.EXPECT().ListUpcomingPlanned(ctx, userID, gomock.Any()).DoAndReturn(func(_ context.Context, _ uuid.UUID, from time.Time) ([]*models.Session, error) {
+			now := time.Now().In(loc)
+			require.Equal(t, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), from, "from the start of the user's day")
+			return []*models.Session{
+				{ID: uuid.New(), PlannedSets: []models.PlannedSet{{ExerciseID: squat}, {ExerciseID: plank}, {ExerciseID: squat}}},
+				{ID: uuid.New(), PlannedSets: []models.PlannedSet{{ExerciseID: plank}}},
+			}, nil
+		})
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{squat, plank}).Return(map[uuid.UUID]models.Exercise{
+			squat: {ID: squat, Name: "Squat"},
+			plank: {ID: plank, Name: "Plank"},
+		}, nil)
+
+		feed, err := svc.Feed(ctx, token)
+		require.NoError(t, err)
+		require.Len(t, feed.Sessions, 2)
+		require.Equal(t, "Squat", feed.Exercises[squat].Name)
+	})
+
+	t.Run("sessions without a plan need no exercises", func(t *testing.T) {
+		svc, sessions, profiles, feeds, _ := newService(t)
+		feeds.EXPECT().FindByCalendarFeedToken(ctx, gomock.Any()).Return(userID, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+		sessions.EXPECT().ListUpcomingPlanned(ctx, userID, gomock.Any()).Return([]*models.Session{{ID: uuid.New()}}, nil)
+
+		feed, err := svc.Feed(ctx, "token")
+		require.NoError(t, err)
+		require.Len(t, feed.Sessions, 1)
+		require.Empty(t, feed.Exercises)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		svc, _, _, feeds, _ := newService(t)
+		feeds.EXPECT().FindByCalendarFeedToken(ctx, gomock.Any()).Return(uuid.Nil, sql.ErrNoRows)
+
+		_, err := svc.Feed(ctx, "guess")
+		require.ErrorIs(t, err, ErrCalendarFeedNotFound)
+	})
+
+	t.Run("revoking without a feed", func(t *testing.T) {
+		svc, _, _, feeds, _ := newService(t)
+		feeds.EXPECT().DeleteCalendarFeedToken(ctx, userID).Return(sql.ErrNoRows)
+
+		require.ErrorIs(t, svc.RevokeFeedToken(ctx, userID), ErrCalendarFeedNotFound)
+	})
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+// DashboardSessionSource supplies the last and next sessions; *SessionService implements it.
+type DashboardSessionSource interface {
+	LastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error)
+	NextPlannedSession(ctx context.Context, userID uuid.UUID) (*models.Session, error)
+}
+
+// DashboardPlanSource supplies the next workout suggestion; *plan.PlanService implements it.
+type DashboardPlanSource interface {
+	NextSuggestion(ctx context.Context, userID uuid.UUID) (*models.PlanSuggestion, error)
+}
+
+// DashboardStatsSource supplies volume, records and streaks; *StatsService implements it.
+type DashboardStatsSource interface {
+	Consistency(ctx context.Context, userID uuid.UUID, weeks int) (*models.ConsistencyStats, error)
+	WeeklyVolume(ctx context.Context, userID uuid.UUID, weeks int) ([]models.WeeklyVolume, error)
+	RecentRecords(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.PersonalRecord, error)
+}
+
+// recentRecordWindow is how far back personal records show on the dashboard.
+const recentRecordWindow = 30 * 24 * time.Hour
+
+// DashboardService composes the home screen from the session, plan and stats services.
+type DashboardService struct {
+	sessions  DashboardSessionSource
+	plans     DashboardPlanSource
+	stats     DashboardStatsSource
+	exercises ExerciseRepository
+}
+
+func NewDashboardService(sessions DashboardSessionSource, plans DashboardPlanSource, stats DashboardStatsSource, exercises ExerciseRepository) *DashboardService {
+	return &DashboardService{sessions: sessions, plans: plans, stats: stats, exercises: exercises}
+}
+
+// Dashboard gathers the user's home screen. Its parts are loaded concurrently; the first one to fail
+// cancels the others and its error is returned.
+func (s *DashboardService) Dashboard(ctx context.Context, userID uuid.UUID) (*models.Dashboard, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	run := func(load func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := load(); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	dashboard := &models.Dashboard{}
+	var volume []models.WeeklyVolume
+	var consistency *models.ConsistencyStats
+	run(func() (err error) {
+		dashboard.LastSession, err = s.sessions.LastSession(ctx, userID)
+		return err
+	})
+	run(func() (err error) {
+		dashboard.NextSession, err = s.sessions.NextPlannedSession(ctx, userID)
+		return err
+	})
+	run(func() (err error) {
+		dashboard.Suggestion, err = s.plans.NextSuggestion(ctx, userID)
+		return err
+	})
+	run(func() (err error) {
+		volume, err = s.stats.WeeklyVolume(ctx, userID, 2)
+		return err
+	})
+	run(func() (err error) {
+		dashboard.RecentRecords, err = s.stats.RecentRecords(ctx, userID, time.Now().Add(-recentRecordWindow))
+		return err
+	})
+	run(func() (err error) {
+		consistency, err = s.stats.Consistency(ctx, userID, 1)
+		return err
+	})
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if len(volume) == 2 {
+		dashboard.LastWeek, dashboard.ThisWeek = volume[0], volume[1]
+	}
+	dashboard.CurrentStreakWeeks = consistency.CurrentStreakWeeks
+	dashboard.LongestStreakWeeks = consistency.LongestStreakWeeks
+
+	var ids []uuid.UUID
+	if dashboard.LastSession != nil {
+		for _, set := range dashboard.LastSession.Sets {
+			ids = append(ids, set.ExerciseID)
+		}
+	}
+	if dashboard.NextSession != nil {
+		for _, planned := range dashboard.NextSession.PlannedSets {
+			ids = append(ids, planned.ExerciseID)
+		}
+	}
+	for _, record := range dashboard.RecentRecords {
+		ids = append(ids, record.ExerciseID)
+	}
+	dashboard.Exercises = map[uuid.UUID]models.Exercise{}
+	if len(ids) > 0 {
+		exercises, err := s.exercises.FindByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		dashboard.Exercises = exercises
+	}
+	return dashboard, nil
+}
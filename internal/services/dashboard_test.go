@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=dashboard.go -destination=./mocks/dashboard_mock.go -package=mocks
+func TestDashboardService_Dashboard(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	squatID, benchID := uuid.New(), uuid.New()
+
+	type deps struct {
+		sessions  *mocks.MockDashboardSessionSource
+		plans     *mocks.MockDashboardPlanSource
+		stats     *mocks.MockDashboardStatsSource
+		exercises *mocks.MockExerciseRepository
+	}
+	newService := func(t *testing.T) (*DashboardService, deps) {
+		ctrl := gomock.NewController(t)
+		d := deps{
+			sessions:  mocks.NewMockDashboardSessionSource(ctrl),
+			plans:     mocks.NewMockDashboardPlanSource(ctrl),
+			stats:     mocks.NewMockDashboardStatsSource(ctrl),
+			exercises: mocks.NewMockExerciseRepository(ctrl),
+		}
+		return NewDashboardService(d.sessions, d.plans, d.stats, d.exercises), d
+	}
+
+	t.Run("all parts", func(t *testing.T) {
+		svc, d := newService(t)
+		last := &models.Session{ID: uuid.New(), Sets: []models.Set{{ExerciseID: squatID, Reps: 5, WeightKG: 100}}}
+		next := &models.Session{ID: uuid.New(), PlannedSets: []models.PlannedSet{{ExerciseID: benchID, Reps: 5}}}
+		suggestion := &models.PlanSuggestion{ExerciseID: squatID, Reps: 5}
+		record := models.PersonalRecord{ExerciseID: squatID, WeightKG: 100, Reps: 5, SessionID: last.ID}
+		lastWeek, thisWeek := models.WeeklyVolume{VolumeKG: 1000}, models.WeeklyVolume{VolumeKG: 1200}
+
+		d.sessions.EXPECT().LastSession(gomock.Any(), userID).Return(last, nil)
+		d.sessions.EXPECT().NextPlannedSession(gomock.Any(), userID).Return(next, nil)
+		d.plans.EXPECT().NextSuggestion(gomock.Any(), userID).Return(suggestion, nil)
+		d.stats.EXPECT().WeeklyVolume(gomock.Any(), userID, 2).Return([]models.WeeklyVolume{lastWeek, thisWeek}, nil)
+		d.stats.EXPECT().RecentRecords(gomock.Any(), userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, since time.Time) ([]models.PersonalRecord, error) {
+				require.WithinDuration(t, time.Now().Add(-recentRecordWindow), since, time.Minute)
+				return []models.PersonalRecord{record}, nil
+			})
+		d.stats.EXPECT().Consistency(gomock.Any(), userID, 1).Return(&models.ConsistencyStats{CurrentStreakWeeks: 3, LongestStreakWeeks: 5}, nil)
+		d.exercises.EXPECT().FindByIDs(gomock.Any(), []uuid.UUID{squatID, benchID, squatID}).Return(map[uuid.UUID]models.Exercise{
+			squatID: {ID: squatID, Name: "Squat"},
+			benchID: {ID: benchID, Name: "Bench Press"},
+		}, nil)
+
+		got, err := svc.Dashboard(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, last, got.LastSession)
+		require.Equal(t, next, got.NextSession)
+		require.Equal(t, suggestion, got.Suggestion)
+		require.Equal(t, thisWeek, got.ThisWeek)
+		require.Equal(t, lastWeek, got.LastWeek)
+		require.Equal(t, []models.PersonalRecord{record}, got.RecentRecords)
+		require.Equal(t, 3, got.CurrentStreakWeeks)
+		require.Equal(t, 5, got.LongestStreakWeeks)
+		require.Len(t, got.Exercises, 2)
+	})
+
+	t.Run("new user", func(t *testing.T) {
+		svc, d := newService(t)
+		d.sessions.EXPECT().LastSession(gomock.Any(), userID).Return(nil, nil)
+		d.sessions.EXPECT().NextPlannedSession(gomock.Any(), userID).Return(nil, nil)
+		d.plans.EXPECT().NextSuggestion(gomock.Any(), userID).Return(&models.PlanSuggestion{}, nil)
+		d.stats.EXPECT().WeeklyVolume(gomock.Any(), userID, 2).Return(make([]models.WeeklyVolume, 2), nil)
+		d.stats.EXPECT().RecentRecords(gomock.Any(), userID, gomock.Any()).Return([]models.PersonalRecord{}, nil)
+		d.stats.EXPECT().Consistency(gomock.Any(), userID, 1).Return(&models.ConsistencyStats{}, nil)
+
+		got, err := svc.Dashboard(ctx, userID)
+		require.NoError(t, err)
+		require.Nil(t, got.LastSession)
+		require.Nil(t, got.NextSession)
+		require.Empty(t, got.Exercises, "no exercises are looked up when nothing refers to one")
+	})
+
+	t.Run("a failing part cancels the others", func(t *testing.T) {
+		svc, d := newService(t)
+		failure := errors.New("boom")
+		// every other part waits for the request to be cancelled, so the call only returns once the failure
+		// has cancelled it
+		blocked := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		d.sessions.EXPECT().LastSession(gomock.Any(), userID).Return(nil, failure)
+		d.sessions.EXPECT().NextPlannedSession(gomock.Any(), userID).
+			DoAndReturn(func(ctx context.Context, _ uuid.UUID) (*models.Session, error) { return nil, blocked(ctx) })
+		d.plans.EXPECT().NextSuggestion(gomock.Any(), userID).
+			DoAndReturn(func(ctx context.Context, _ uuid.UUID) (*models.PlanSuggestion, error) { return nil, blocked(ctx) })
+		d.stats.EXPECT().WeeklyVolume(gomock.Any(), userID, 2).
+			DoAndReturn(func(ctx context.Context, _ uuid.UUID, _ int) ([]models.WeeklyVolume, error) { return nil, blocked(ctx) })
+		d.stats.EXPECT().RecentRecords(gomock.Any(), userID, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ uuid.UUID, _ time.Time) ([]models.PersonalRecord, error) {
+				return nil, blocked(ctx)
+			})
+		d.stats.EXPECT().Consistency(gomock.Any(), userID, 1).
+			DoAndReturn(func(ctx context.Context, _ uuid.UUID, _ int) (*models.ConsistencyStats, error) {
+				return nil, blocked(ctx)
+			})
+
+		_, err := svc.Dashboard(ctx, userID)
+		require.ErrorIs(t, err, failure)
+	})
+}
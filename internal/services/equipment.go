@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/plates"
+	"github.com/alexanderramin/kalistheniks/internal/units"
+	"github.com/google/uuid"
+)
+
+type EquipmentRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*models.Equipment, error)
+	Save(ctx context.Context, e *models.Equipment) (*models.Equipment, error)
+}
+
+var (
+	ErrFindEquipment = errors.New("failed to load equipment")
+	ErrSaveEquipment = errors.New("failed to save equipment")
+)
+
+// EquipmentService manages the user's equipment profile and works out plate loadings with it.
+type EquipmentService struct {
+	equipment EquipmentRepository
+	profiles  ProfileRepository
+}
+
+func NewEquipmentService(equipment EquipmentRepository, profiles ProfileRepository) *EquipmentService {
+	return &EquipmentService{equipment: equipment, profiles: profiles}
+}
+
+// GetEquipment returns the user's saved equipment. Users who never saved any get a standard gym for
+// their unit system: a 20 kg bar and metric plates, or a 45 lb bar and pound plates.
+func (s *EquipmentService) GetEquipment(ctx context.Context, userID uuid.UUID) (*models.Equipment, error) {
+	e, err := s.equipment.Get(ctx, userID)
+	if err == nil {
+		return e, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %v", ErrFindEquipment, err)
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindProfile, err)
+	}
+	bar, platesKG := plates.Defaults(profile.Units)
+	return &models.Equipment{UserID: userID, BarKG: bar, PlatesKG: platesKG, DumbbellsKG: []float64{}}, nil
+}
+
+// UpdateEquipment replaces the user's equipment. Plates are kept heaviest first and dumbbells
+// lightest first, each without duplicates.
+func (s *EquipmentService) UpdateEquipment(ctx context.Context, e *models.Equipment) (*models.Equipment, error) {
+	e.PlatesKG = uniqueSorted(e.PlatesKG)
+	sort.Sort(sort.Reverse(sort.Float64Slice(e.PlatesKG)))
+	e.DumbbellsKG = uniqueSorted(e.DumbbellsKG)
+	saved, err := s.equipment.Save(ctx, e)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSaveEquipment, err)
+	}
+	return saved, nil
+}
+
+// PlateLoading returns how to load the user's bar for targetKG. The arithmetic happens in the user's
+// unit, so pound plates add up to whole pounds rather than to rounded kilograms.
+func (s *EquipmentService) PlateLoading(ctx context.Context, userID uuid.UUID, targetKG float64) (*models.PlateLoading, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindProfile, err)
+	}
+	e, err := s.GetEquipment(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	system := profile.Units
+	bar := units.FromKG(e.BarKG, system)
+	available := make([]float64, len(e.PlatesKG))
+	for i, p := range e.PlatesKG {
+		available[i] = units.FromKG(p, system)
+	}
+	total, perSide := plates.Load(units.FromKG(targetKG, system), bar, available)
+
+	loading := &models.PlateLoading{
+		TargetKG:   targetKG,
+		AchievedKG: units.ToKG(total, system),
+		BarKG:      e.BarKG,
+		PerSideKG:  make([]float64, len(perSide)),
+	}
+	for i, p := range perSide {
+		loading.PerSideKG[i] = units.ToKG(p, system)
+	}
+	return loading, nil
+}
+
+func uniqueSorted(values []float64) []float64 {
+	out := make([]float64, 0, len(values))
+	seen := make(map[float64]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Float64s(out)
+	return out
+}
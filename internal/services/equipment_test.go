@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=equipment.go -destination=./mocks/equipment_mock.go -package=mocks
+
+func TestEquipmentService_GetEquipment(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("returns saved equipment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockEquipmentRepository(ctrl)
+		svc := NewEquipmentService(repo, mocks.NewMockProfileRepository(ctrl))
+		repo.EXPECT().Get(ctx, userID).Return(&models.Equipment{UserID: userID, BarKG: 15, HasRings: true}, nil)
+
+		e, err := svc.GetEquipment(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 15.0, e.BarKG)
+		require.True(t, e.HasRings)
+	})
+
+	t.Run("defaults follow the unit system", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockEquipmentRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		svc := NewEquipmentService(repo, profiles)
+		repo.EXPECT().Get(ctx, userID).Return(nil, sql.ErrNoRows)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsImperial}, nil)
+
+		e, err := svc.GetEquipment(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 20.41, e.BarKG)
+		require.Nil(t, e.UpdatedAt)
+	})
+
+	t.Run("wraps repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockEquipmentRepository(ctrl)
+		svc := NewEquipmentService(repo, mocks.NewMockProfileRepository(ctrl))
+		repo.EXPECT().Get(ctx, userID).Return(nil, errors.New("db down"))
+
+		_, err := svc.GetEquipment(ctx, userID)
+		require.ErrorIs(t, err, ErrFindEquipment)
+	})
+}
+
+func TestEquipmentService_UpdateEquipment(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockEquipmentRepository(ctrl)
+	svc := NewEquipmentService(repo, mocks.NewMockProfileRepository(ctrl))
+
+	repo.EXPECT().Save(ctx, &models.Equipment{
+		UserID:      userID,
+		BarKG:       20,
+		PlatesKG:    []float64{10, 5, 1.25},
+		DumbbellsKG: []float64{10, 12.5},
+	}).DoAndReturn(func(_ context.Context, e *models.Equipment) (*models.Equipment, error) { return e, nil })
+
+	_, err := svc.UpdateEquipment(ctx, &models.Equipment{
+		UserID:      userID,
+		BarKG:       20,
+		PlatesKG:    []float64{5, 1.25, 10, 5},
+		DumbbellsKG: []float64{12.5, 10},
+	})
+	require.NoError(t, err)
+
+	repo.EXPECT().Save(ctx, gomock.Any()).Return(nil, errors.New("constraint"))
+	_, err = svc.UpdateEquipment(ctx, &models.Equipment{UserID: userID})
+	require.ErrorIs(t, err, ErrSaveEquipment)
+}
+
+func TestEquipmentService_PlateLoading(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("loads with the user's plates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockEquipmentRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		svc := NewEquipmentService(repo, profiles)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		repo.EXPECT().Get(ctx, userID).Return(&models.Equipment{UserID: userID, BarKG: 20, PlatesKG: []float64{5, 1.25}}, nil)
+
+		loading, err := svc.PlateLoading(ctx, userID, 83)
+		require.NoError(t, err)
+		require.Equal(t, 82.5, loading.AchievedKG)
+		require.Equal(t, []float64{5, 5, 5, 5, 5, 5, 1.25}, loading.PerSideKG)
+	})
+
+	t.Run("pound plates add up in pounds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockEquipmentRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		svc := NewEquipmentService(repo, profiles)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsImperial}, nil).Times(2)
+		repo.EXPECT().Get(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		loading, err := svc.PlateLoading(ctx, userID, 102.06) // 225 lb
+		require.NoError(t, err)
+		require.Equal(t, 102.06, loading.AchievedKG)
+		require.Equal(t, []float64{20.41, 20.41}, loading.PerSideKG)
+	})
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type ExportSessionRepository interface {
+	ListWithSets(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+	ListExercisesForUser(ctx context.Context, userID uuid.UUID) ([]models.Exercise, error)
+}
+
+type ExportIdentityRepository interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error)
+}
+
+type ExportBodyweightRepository interface {
+	List(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error)
+}
+
+type ExportEquipmentRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*models.Equipment, error)
+}
+
+type ExportTemplateRepository interface {
+	List(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error)
+}
+
+type ExportReadinessRepository interface {
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]*models.ReadinessCheckIn, error)
+}
+
+var ErrExportData = errors.New("failed to export data")
+
+// ExportService assembles a complete copy of a user's data for download.
+type ExportService struct {
+	profiles   ProfileRepository
+	identities ExportIdentityRepository
+	sessions   ExportSessionRepository
+	bodyweight ExportBodyweightRepository
+	equipment  ExportEquipmentRepository
+	templates  ExportTemplateRepository
+	readiness  ExportReadinessRepository
+}
+
+func NewExportService(profiles ProfileRepository, identities ExportIdentityRepository, sessions ExportSessionRepository, bodyweight ExportBodyweightRepository, equipment ExportEquipmentRepository, templates ExportTemplateRepository, readiness ExportReadinessRepository) *ExportService {
+	return &ExportService{profiles: profiles, identities: identities, sessions: sessions, bodyweight: bodyweight, equipment: equipment, templates: templates, readiness: readiness}
+}
+
+// Export returns the account, profile, linked identities, bodyweight log, saved equipment, sessions with their sets, session
+// templates, readiness check-ins, the exercises those sets reference and the personal record per exercise. Sessions are
+// ordered oldest first.
+func (s *ExportService) Export(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	user, err := s.profiles.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindProfile, err)
+	}
+	identities, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+	bodyweight, err := s.bodyweight.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+	equipment, err := s.equipment.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		equipment, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+	sessions, err := s.sessions.ListWithSets(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+	exercises, err := s.sessions.ListExercisesForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+	templates, err := s.templates.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+	readiness, err := s.readiness.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportData, err)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].PerformedAt.Before(sessions[j].PerformedAt)
+	})
+	return &models.DataExport{
+		GeneratedAt: time.Now().UTC(),
+		User:        user,
+		Profile:     profile,
+		Identities:  identities,
+		Bodyweight:  bodyweight,
+		Equipment:   equipment,
+		Sessions:    sessions,
+		Templates:   templates,
+		Readiness:   readiness,
+		Exercises:   exercises,
+		Records:     personalRecords(sessions),
+	}, nil
+}
+
+// personalRecords picks the heaviest working set per exercise, preferring more reps and then the earlier
+// session on ties. Records are ordered by exercise ID so the output is stable.
+func personalRecords(sessions []*models.Session) []models.PersonalRecord {
+	best := make(map[uuid.UUID]models.PersonalRecord)
+	for _, session := range sessions {
+		for _, set := range session.Sets {
+			if set.Kind != models.SetKindWorking {
+				continue
+			}
+			current, ok := best[set.ExerciseID]
+			better := !ok ||
+				set.WeightKG > current.WeightKG ||
+				(set.WeightKG == current.WeightKG && set.Reps > current.Reps) ||
+				(set.WeightKG == current.WeightKG && set.Reps == current.Reps && session.PerformedAt.Before(current.PerformedAt))
+			if better {
+				best[set.ExerciseID] = models.PersonalRecord{
+					ExerciseID:  set.ExerciseID,
+					WeightKG:    set.WeightKG,
+					Reps:        set.Reps,
+					SessionID:   session.ID,
+					PerformedAt: session.PerformedAt,
+				}
+			}
+		}
+	}
+
+	records := make([]models.PersonalRecord, 0, len(best))
+	for _, record := range best {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ExerciseID.String() < records[j].ExerciseID.String()
+	})
+	return records
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=export.go -destination=./mocks/export_mock.go -package=mocks
+
+func TestExportService_Export(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	squat, bench := uuid.New(), uuid.New()
+	older := &models.Session{ID: uuid.New(), PerformedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Sets: []models.Set{
+		{ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+		{ExerciseID: bench, Kind: models.SetKindWorking, Reps: 5, WeightKG: 80},
+	}}
+	newer := &models.Session{ID: uuid.New(), PerformedAt: time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC), Sets: []models.Set{
+		{ExerciseID: squat, Kind: models.SetKindWorking, Reps: 3, WeightKG: 100},
+		{ExerciseID: squat, Kind: models.SetKindWorking, Reps: 6, WeightKG: 100},
+		{ExerciseID: bench, Kind: models.SetKindWorking, Reps: 8, WeightKG: 75},
+		{ExerciseID: bench, Kind: models.SetKindFailure, Reps: 1, WeightKG: 90}, // not a working set, so no record
+	}}
+
+	t.Run("assembles archive with sessions oldest first and records", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		identities := mocks.NewMockExportIdentityRepository(ctrl)
+		sessions := mocks.NewMockExportSessionRepository(ctrl)
+		bodyweight := mocks.NewMockExportBodyweightRepository(ctrl)
+		equipment := mocks.NewMockExportEquipmentRepository(ctrl)
+		templates := mocks.NewMockExportTemplateRepository(ctrl)
+		readiness := mocks.NewMockExportReadinessRepository(ctrl)
+		svc := NewExportService(profiles, identities, sessions, bodyweight, equipment, templates, readiness)
+
+		profiles.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID}, nil)
+		identities.EXPECT().ListByUser(ctx, userID).Return([]*models.UserIdentity{{Provider: "google"}}, nil)
+		bodyweight.EXPECT().List(ctx, userID).Return([]*models.BodyweightEntry{{WeightKG: 80}}, nil)
+		equipment.EXPECT().Get(ctx, userID).Return(nil, sql.ErrNoRows)
+		sessions.EXPECT().ListWithSets(ctx, userID).Return([]*models.Session{newer, older}, nil)
+		sessions.EXPECT().ListExercisesForUser(ctx, userID).Return([]models.Exercise{{ID: squat, Name: "Back Squat"}}, nil)
+		templates.EXPECT().List(ctx, userID).Return([]*models.SessionTemplate{{Name: "Leg day"}}, nil)
+		readiness.EXPECT().ListForUser(ctx, userID).Return([]*models.ReadinessCheckIn{{Sleep: 4, Soreness: 2, Stress: 3}}, nil)
+
+		export, err := svc.Export(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, userID, export.User.ID)
+		require.Len(t, export.Identities, 1)
+		require.Len(t, export.Bodyweight, 1)
+		require.Nil(t, export.Equipment)
+		require.Equal(t, []*models.Session{older, newer}, export.Sessions)
+		require.Len(t, export.Exercises, 1)
+		require.Len(t, export.Templates, 1)
+		require.Equal(t, []*models.ReadinessCheckIn{{Sleep: 4, Soreness: 2, Stress: 3}}, export.Readiness)
+
+		records := map[uuid.UUID]models.PersonalRecord{}
+		for _, r := range export.Records {
+			records[r.ExerciseID] = r
+		}
+		require.Equal(t, models.PersonalRecord{ExerciseID: squat, WeightKG: 100, Reps: 6, SessionID: newer.ID, PerformedAt: newer.PerformedAt}, records[squat])
+		require.Equal(t, models.PersonalRecord{ExerciseID: bench, WeightKG: 80, Reps: 5, SessionID: older.ID, PerformedAt: older.PerformedAt}, records[bench])
+	})
+
+	t.Run("session lookup fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		identities := mocks.NewMockExportIdentityRepository(ctrl)
+		sessions := mocks.NewMockExportSessionRepository(ctrl)
+		bodyweight := mocks.NewMockExportBodyweightRepository(ctrl)
+		equipment := mocks.NewMockExportEquipmentRepository(ctrl)
+		templates := mocks.NewMockExportTemplateRepository(ctrl)
+		readiness := mocks.NewMockExportReadinessRepository(ctrl)
+		svc := NewExportService(profiles, identities, sessions, bodyweight, equipment, templates, readiness)
+
+		profiles.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID}, nil)
+		identities.EXPECT().ListByUser(ctx, userID).Return(nil, nil)
+		bodyweight.EXPECT().List(ctx, userID).Return(nil, nil)
+		equipment.EXPECT().Get(ctx, userID).Return(&models.Equipment{UserID: userID}, nil)
+		sessions.EXPECT().ListWithSets(ctx, userID).Return(nil, errors.New("db down"))
+
+		_, err := svc.Export(ctx, userID)
+		require.ErrorIs(t, err, ErrExportData)
+	})
+}
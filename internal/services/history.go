@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/rules"
+	"github.com/google/uuid"
+)
+
+type HistorySessionRepository interface {
+	ListExerciseHistory(ctx context.Context, userID, exerciseID uuid.UUID) ([]*models.Session, error)
+}
+
+// HistoryService turns a user's logged sets into per-exercise progress series.
+type HistoryService struct {
+	sessions   HistorySessionRepository
+	exercises  ExerciseRepository
+	bodyweight BodyweightRepository
+	profiles   ProfileRepository
+}
+
+func NewHistoryService(sessions HistorySessionRepository, exercises ExerciseRepository, bodyweight BodyweightRepository, profiles ProfileRepository) *HistoryService {
+	return &HistoryService{sessions: sessions, exercises: exercises, bodyweight: bodyweight, profiles: profiles}
+}
+
+// ExerciseHistory sums up the user's sets of an exercise per session, or per week or month in the user's
+// time zone, oldest first. Only sets of the given kinds count; no kinds means working sets only. Periods
+// without counted sets are left out.
+func (s *HistoryService) ExerciseHistory(ctx context.Context, userID, exerciseID uuid.UUID, window string, kinds []string) (*models.ExerciseHistory, error) {
+	if len(kinds) == 0 {
+		kinds = []string{models.SetKindWorking}
+	}
+	exercises, err := s.exercises.FindByIDs(ctx, []uuid.UUID{exerciseID})
+	if err != nil {
+		return nil, err
+	}
+	exercise, ok := exercises[exerciseID]
+	if !ok {
+		return nil, ErrUnknownExercise
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessions.ListExerciseHistory(ctx, userID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*models.BodyweightEntry
+	if exercise.BodyweightFraction > 0 {
+		if entries, err = s.bodyweight.List(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	loc := userLocation(profile)
+	history := &models.ExerciseHistory{
+		ExerciseID:  exerciseID,
+		Measurement: exercise.Measurement,
+		Window:      window,
+		Points:      []models.ExerciseHistoryPoint{},
+	}
+	var rpeSum, rpeCount int
+	for _, session := range sessions {
+		var sets []models.Set
+		for _, set := range session.Sets {
+			if slices.Contains(kinds, set.Kind) {
+				sets = append(sets, set)
+			}
+		}
+		if len(sets) == 0 {
+			continue
+		}
+
+		start := historyPeriod(session.PerformedAt.In(loc), window)
+		n := len(history.Points)
+		if n == 0 || window == models.HistoryWindowSession || !history.Points[n-1].Start.Equal(start) {
+			point := models.ExerciseHistoryPoint{Start: start}
+			if window == models.HistoryWindowSession {
+				id := session.ID
+				point.SessionID = &id
+			}
+			history.Points = append(history.Points, point)
+			n++
+			rpeSum, rpeCount = 0, 0
+		}
+		point := &history.Points[n-1]
+		point.Sessions++
+
+		bodyweight := nearestBodyweight(entries, session.PerformedAt, profile.BodyweightKG)
+		for _, set := range sets {
+			load := setLoad(set, exercise.BodyweightFraction, bodyweight).EffectiveKG
+			if point.Sets == 0 || betterSet(set, load, point.BestSet, point.BestSetLoadKG, exercise.Measurement) {
+				point.BestSet, point.BestSetLoadKG = set, load
+			}
+			point.Sets++
+			point.TotalReps += set.Reps
+			point.VolumeKG = round2(point.VolumeKG + load*float64(set.Reps))
+			if e1rm := round2(rules.E1RM(load, set.Reps)); e1rm > 0 && (point.TopE1RMKG == nil || e1rm > *point.TopE1RMKG) {
+				point.TopE1RMKG = &e1rm
+			}
+			if set.RPE != nil {
+				rpeSum += *set.RPE
+				rpeCount++
+				avg := math.Round(float64(rpeSum)/float64(rpeCount)*10) / 10
+				point.AverageRPE = &avg
+			}
+		}
+	}
+	return history, nil
+}
+
+// historyPeriod returns when the window holding t starts: t itself per session, otherwise midnight
+// starting its week or month, in t's location.
+func historyPeriod(t time.Time, window string) time.Time {
+	switch window {
+	case models.HistoryWindowWeek:
+		return weekStart(t)
+	case models.HistoryWindowMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return t.UTC()
+	}
+}
+
+// betterSet reports whether a set beats the best so far: by load and then reps for rep work, by time
+// for holds and timed work, and by distance for distance work.
+func betterSet(set models.Set, load float64, best models.Set, bestLoad float64, measurement string) bool {
+	switch measurement {
+	case models.MeasurementHold, models.MeasurementDuration:
+		return set.DurationSeconds != nil && (best.DurationSeconds == nil || *set.DurationSeconds > *best.DurationSeconds)
+	case models.MeasurementDistance:
+		return set.DistanceM != nil && (best.DistanceM == nil || *set.DistanceM > *best.DistanceM)
+	default:
+		return load > bestLoad || (load == bestLoad && set.Reps > best.Reps)
+	}
+}
+
+// nearestBodyweight returns the logged bodyweight measured closest to at, or the profile's when nothing
+// has been logged.
+func nearestBodyweight(entries []*models.BodyweightEntry, at time.Time, fallback *float64) *float64 {
+	var nearest *models.BodyweightEntry
+	for _, e := range entries {
+		if nearest == nil || e.MeasuredAt.Sub(at).Abs() < nearest.MeasuredAt.Sub(at).Abs() {
+			nearest = e
+		}
+	}
+	if nearest == nil {
+		return fallback
+	}
+	weight := nearest.WeightKG
+	return &weight
+}
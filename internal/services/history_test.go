@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=history.go -destination=./mocks/history_mock.go -package=mocks
+func TestHistoryService_ExerciseHistory(t *testing.T) {
+	ctx := context.Background()
+	userID, exerciseID := uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*HistoryService, *mocks.MockHistorySessionRepository, *mocks.MockExerciseRepository, *mocks.MockBodyweightRepository, *mocks.MockProfileRepository) {
+		ctrl := gomock.NewController(t)
+		sessions := mocks.NewMockHistorySessionRepository(ctrl)
+		exercises := mocks.NewMockExerciseRepository(ctrl)
+		bodyweight := mocks.NewMockBodyweightRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		return NewHistoryService(sessions, exercises, bodyweight, profiles), sessions, exercises, bodyweight, profiles
+	}
+	rpe := func(v int) *int { return &v }
+	monday := time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC)
+	squat := models.Exercise{ID: exerciseID, Name: "Squat", Measurement: models.MeasurementReps}
+	history := []*models.Session{
+		{ID: uuid.New(), PerformedAt: monday, Sets: []models.Set{
+			{ExerciseID: exerciseID, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 40},
+			{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100, RPE: rpe(7)},
+			{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 3, WeightKG: 110, RPE: rpe(9)},
+		}},
+		{ID: uuid.New(), PerformedAt: monday.AddDate(0, 0, 3), Sets: []models.Set{
+			{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 8, WeightKG: 110},
+		}},
+		{ID: uuid.New(), PerformedAt: monday.AddDate(0, 0, 7), Sets: []models.Set{
+			{ExerciseID: exerciseID, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 40},
+		}},
+	}
+
+	t.Run("per session", func(t *testing.T) {
+		svc, sessions, exercises, _, profiles := newService(t)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: squat}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+		sessions.EXPECT().ListExerciseHistory(ctx, userID, exerciseID).Return(history, nil)
+
+		got, err := svc.ExerciseHistory(ctx, userID, exerciseID, models.HistoryWindowSession, nil)
+		require.NoError(t, err)
+		require.Len(t, got.Points, 2, "sessions with only warm-ups are left out")
+		first := got.Points[0]
+		require.Equal(t, history[0].ID, *first.SessionID)
+		require.Equal(t, monday, first.Start)
+		require.Equal(t, 2, first.Sets)
+		require.Equal(t, 110.0, first.BestSetLoadKG)
+		require.Equal(t, 3, first.BestSet.Reps)
+		require.Equal(t, 8, first.TotalReps)
+		require.Equal(t, 830.0, first.VolumeKG)
+		require.Equal(t, 121.0, *first.TopE1RMKG)
+		require.Equal(t, 8.0, *first.AverageRPE)
+		require.Nil(t, got.Points[1].AverageRPE)
+	})
+
+	t.Run("per week with other kinds", func(t *testing.T) {
+		svc, sessions, exercises, _, profiles := newService(t)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: squat}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+		sessions.EXPECT().ListExerciseHistory(ctx, userID, exerciseID).Return(history, nil)
+
+		got, err := svc.ExerciseHistory(ctx, userID, exerciseID, models.HistoryWindowWeek, []string{models.SetKindWorking, models.SetKindWarmup})
+		require.NoError(t, err)
+		require.Len(t, got.Points, 2)
+		week := got.Points[0]
+		require.Nil(t, week.SessionID)
+		require.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), week.Start)
+		require.Equal(t, 2, week.Sessions)
+		require.Equal(t, 4, week.Sets)
+		require.Equal(t, 8, week.BestSet.Reps, "110 kg for 8 beats 110 kg for 3")
+		require.Equal(t, 139.33, *week.TopE1RMKG)
+		require.Equal(t, 8.0, *week.AverageRPE)
+		require.Equal(t, 1, got.Points[1].Sessions)
+	})
+
+	t.Run("months follow the user's time zone", func(t *testing.T) {
+		svc, sessions, exercises, _, profiles := newService(t)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: squat}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "America/Los_Angeles"}, nil)
+		// 03:00 UTC on April 1st is still March 31st in Los Angeles
+		sessions.EXPECT().ListExerciseHistory(ctx, userID, exerciseID).Return([]*models.Session{
+			{ID: uuid.New(), PerformedAt: time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC), Sets: []models.Set{{Kind: models.SetKindWorking, Reps: 5, WeightKG: 100}}},
+			{ID: uuid.New(), PerformedAt: time.Date(2025, 4, 1, 3, 0, 0, 0, time.UTC), Sets: []models.Set{{Kind: models.SetKindWorking, Reps: 5, WeightKG: 100}}},
+		}, nil)
+
+		got, err := svc.ExerciseHistory(ctx, userID, exerciseID, models.HistoryWindowMonth, nil)
+		require.NoError(t, err)
+		require.Len(t, got.Points, 1)
+		require.Equal(t, 2, got.Points[0].Sessions)
+		require.Equal(t, time.March, got.Points[0].Start.Month())
+	})
+
+	t.Run("bodyweight work counts the nearest bodyweight", func(t *testing.T) {
+		svc, sessions, exercises, bodyweight, profiles := newService(t)
+		pullUp := models.Exercise{ID: exerciseID, Name: "Pull-up", Measurement: models.MeasurementReps, BodyweightFraction: 1}
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: pullUp}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+		sessions.EXPECT().ListExerciseHistory(ctx, userID, exerciseID).Return([]*models.Session{
+			{ID: uuid.New(), PerformedAt: monday, Sets: []models.Set{{Kind: models.SetKindWorking, Reps: 10}, {Kind: models.SetKindWorking, Reps: 5, WeightKG: 10}}},
+		}, nil)
+		bodyweight.EXPECT().List(ctx, userID).Return([]*models.BodyweightEntry{
+			{WeightKG: 82, MeasuredAt: monday.AddDate(0, 0, 20)},
+			{WeightKG: 80, MeasuredAt: monday.AddDate(0, 0, -2)},
+		}, nil)
+
+		got, err := svc.ExerciseHistory(ctx, userID, exerciseID, models.HistoryWindowSession, nil)
+		require.NoError(t, err)
+		require.Equal(t, 90.0, got.Points[0].BestSetLoadKG)
+		require.Equal(t, 1250.0, got.Points[0].VolumeKG)
+	})
+
+	t.Run("holds pick the longest set", func(t *testing.T) {
+		svc, sessions, exercises, _, profiles := newService(t)
+		plank := models.Exercise{ID: exerciseID, Name: "Plank", Measurement: models.MeasurementHold}
+		short, long := 30, 45
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: plank}, nil)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+		sessions.EXPECT().ListExerciseHistory(ctx, userID, exerciseID).Return([]*models.Session{
+			{ID: uuid.New(), PerformedAt: monday, Sets: []models.Set{
+				{Kind: models.SetKindWorking, DurationSeconds: &short},
+				{Kind: models.SetKindWorking, DurationSeconds: &long},
+			}},
+		}, nil)
+
+		got, err := svc.ExerciseHistory(ctx, userID, exerciseID, models.HistoryWindowSession, nil)
+		require.NoError(t, err)
+		require.Equal(t, 45, *got.Points[0].BestSet.DurationSeconds)
+		require.Nil(t, got.Points[0].TopE1RMKG)
+	})
+
+	t.Run("unknown exercise", func(t *testing.T) {
+		svc, _, exercises, _, _ := newService(t)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
+
+		_, err := svc.ExerciseHistory(ctx, userID, exerciseID, models.HistoryWindowSession, nil)
+		require.ErrorIs(t, err, ErrUnknownExercise)
+	})
+}
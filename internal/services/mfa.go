@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	t "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/alexanderramin/kalistheniks/internal/totp"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	totpIssuer        = "Kalistheniks"
+	recoveryCodeCount = 10
+)
+
+// EnrollTOTP generates a new secret for the user and returns it with its provisioning URI.
+// Two-factor auth stays off until ConfirmTOTP succeeds with a code from the authenticator.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (string, string, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.TOTPEnabledAt != nil {
+		return "", "", ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	if err := s.users.SetPendingTOTPSecret(ctx, userID, secret); err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrUpdateMFA, err)
+	}
+	return secret, totp.ProvisioningURI(totpIssuer, user.Email, secret), nil
+}
+
+// ConfirmTOTP enables two-factor auth once the user proves their authenticator works, and returns
+// one-time recovery codes. Only hashes of the codes are stored, so they cannot be shown again.
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := totp.Validate(*user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	if err := s.users.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpdateMFA, err)
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns off two-factor auth. It requires both the password and a current second factor;
+// wrong guesses at either count towards the account lockout.
+func (s *AuthService) DisableTOTP(ctx context.Context, userID uuid.UUID, password, code string) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return ErrAccountLocked
+	}
+	if user.TOTPEnabledAt == nil {
+		return ErrMFANotEnrolled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCredentials, lockErr)
+		}
+		return ErrInvalidCredentials
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidMFACode, lockErr)
+			}
+		}
+		return err
+	}
+	if err := s.users.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("%w: %v", ErrUpdateMFA, err)
+	}
+	return nil
+}
+
+// VerifyMFA completes a two-step login by exchanging a challenge from Login plus a TOTP or
+// recovery code for an access token. Wrong codes count towards the account lockout.
+func (s *AuthService) VerifyMFA(ctx context.Context, challenge, code string) (*models.User, string, error) {
+	subject, err := t.ParseMFAChallenge(challenge, s.jwtSecret)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrParseToken, err)
+	}
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrParseToken, err)
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	// Answered like a wrong code, the same as a locked Login.
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return nil, "", ErrInvalidMFACode
+	}
+	if user.TOTPEnabledAt == nil {
+		return nil, "", ErrMFANotEnrolled
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+				return nil, "", fmt.Errorf("%w: %v", ErrInvalidMFACode, lockErr)
+			}
+		}
+		return nil, "", err
+	}
+
+	if err := s.resetFailedLogins(ctx, user); err != nil {
+		return nil, "", err
+	}
+	if err := restoreAccount(ctx, s.users, user); err != nil {
+		return nil, "", err
+	}
+	token, err := t.GenerateVersionedToken(user.ID, user.TokenVersion, s.jwtSecret)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	return user, token, nil
+}
+
+// checkSecondFactor accepts either a TOTP code, which cannot be replayed, or an unused recovery code.
+func (s *AuthService) checkSecondFactor(ctx context.Context, user *models.User, code string) error {
+	if user.TOTPSecret != nil {
+		if step, ok := totp.Validate(*user.TOTPSecret, code, time.Now()); ok {
+			fresh, err := s.users.ConsumeTOTPStep(ctx, user.ID, step)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrUpdateMFA, err)
+			}
+			if !fresh {
+				return ErrInvalidMFACode
+			}
+			return nil
+		}
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return ErrInvalidMFACode
+	}
+	used, err := s.users.ConsumeRecoveryCode(ctx, user.ID, t.HashOpaqueToken(normalized))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUpdateMFA, err)
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// recoveryCodeLength is the number of base32 characters in a recovery code, giving 50 bits of entropy.
+const recoveryCodeLength = 10
+
+// generateRecoveryCodes returns display codes formatted as "xxxxx-xxxxx" and the hashes to store.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(enc.EncodeToString(b))[:recoveryCodeLength]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, t.HashOpaqueToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
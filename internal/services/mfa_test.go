@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	tok "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/alexanderramin/kalistheniks/internal/totp"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// testTOTPSecret is an arbitrary base32 secret used to compute valid codes in tests.
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func currentCode(t *testing.T) string {
+	t.Helper()
+	code, err := totp.Code(testTOTPSecret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func TestAuthService_EnrollTOTP(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("stores pending secret", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, Email: "test@example.com"}, nil)
+		deps.usersRepo.EXPECT().SetPendingTOTPSecret(ctx, userID, gomock.Any()).Return(nil)
+
+		secret, uri, err := deps.svc.EnrollTOTP(ctx, userID)
+		require.NoError(t, err)
+		require.NotEmpty(t, secret)
+		require.True(t, strings.HasPrefix(uri, "otpauth://totp/Kalistheniks:test@example.com?"))
+		require.Contains(t, uri, "secret="+secret)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		enabledAt := time.Now()
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, TOTPEnabledAt: &enabledAt}, nil)
+
+		_, _, err := deps.svc.EnrollTOTP(ctx, userID)
+		require.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+	})
+}
+
+func TestAuthService_ConfirmTOTP(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	secret := testTOTPSecret
+
+	t.Run("enables and returns hashed recovery codes", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		var storedHashes []string
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, TOTPSecret: &secret}, nil)
+		deps.usersRepo.EXPECT().EnableTOTP(ctx, userID, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, _ int64, hashes []string) error {
+				storedHashes = hashes
+				return nil
+			})
+
+		codes, err := deps.svc.ConfirmTOTP(ctx, userID, currentCode(t))
+		require.NoError(t, err)
+		require.Len(t, codes, recoveryCodeCount)
+		require.Len(t, storedHashes, recoveryCodeCount)
+		require.Equal(t, tok.HashOpaqueToken(normalizeRecoveryCode(codes[0])), storedHashes[0])
+		require.NotContains(t, storedHashes, codes[0])
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID, TOTPSecret: &secret}, nil)
+
+		_, err := deps.svc.ConfirmTOTP(ctx, userID, "000000")
+		require.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("not enrolled", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+
+		_, err := deps.svc.ConfirmTOTP(ctx, userID, "123456")
+		require.ErrorIs(t, err, ErrMFANotEnrolled)
+	})
+}
+
+func TestAuthService_LoginWithMFA(t *testing.T) {
+	const hashedPassword = "$2a$10$nzAyuLjvw2JKqKETtpFyvukYMwsMoAByVcziZ7RGnZUlQvehEJ8qq"
+	ctx := context.Background()
+	userID := uuid.New()
+	secret := testTOTPSecret
+	enabledAt := time.Now()
+	mfaUser := &models.User{ID: userID, PasswordHash: hashedPassword, TOTPSecret: &secret, TOTPEnabledAt: &enabledAt}
+
+	t.Run("login returns challenge instead of access token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(mfaUser, nil)
+
+		_, challenge, err := deps.svc.Login(ctx, "test@example.com", "password123")
+		require.ErrorIs(t, err, ErrMFARequired)
+		_, err = tok.ParseToken(challenge, "testsecret")
+		require.Error(t, err)
+		subject, err := tok.ParseMFAChallenge(challenge, "testsecret")
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), subject)
+	})
+
+	t.Run("challenge and totp code yield access token", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		challenge, err := tok.GenerateMFAChallenge(userID, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().ConsumeTOTPStep(ctx, userID, gomock.Any()).Return(true, nil)
+
+		user, access, err := deps.svc.VerifyMFA(ctx, challenge, currentCode(t))
+		require.NoError(t, err)
+		require.Equal(t, userID, user.ID)
+		subject, err := tok.ParseToken(access, "testsecret")
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), subject)
+	})
+
+	t.Run("replayed totp code is rejected", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		challenge, err := tok.GenerateMFAChallenge(userID, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().ConsumeTOTPStep(ctx, userID, gomock.Any()).Return(false, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(1, nil)
+
+		_, _, err = deps.svc.VerifyMFA(ctx, challenge, currentCode(t))
+		require.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("recovery code is accepted", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		challenge, err := tok.GenerateMFAChallenge(userID, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().ConsumeRecoveryCode(ctx, userID, tok.HashOpaqueToken("abcdefghij")).Return(true, nil)
+
+		_, access, err := deps.svc.VerifyMFA(ctx, challenge, "ABCDE-FGHIJ")
+		require.NoError(t, err)
+		require.NotEmpty(t, access)
+	})
+
+	t.Run("wrong code counts as failed login", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		challenge, err := tok.GenerateMFAChallenge(userID, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().ConsumeRecoveryCode(ctx, userID, gomock.Any()).Return(false, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(1, nil)
+
+		_, _, err = deps.svc.VerifyMFA(ctx, challenge, "wrong-codes")
+		require.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("alternating password logins with wrong codes still locks", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		failing := *mfaUser
+		failing.FailedLoginAttempts = maxFailedLogins - 1
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "test@example.com").Return(&failing, nil)
+		deps.usersRepo.EXPECT().ResetFailedLogins(gomock.Any(), gomock.Any()).Times(0)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&failing, nil)
+		deps.usersRepo.EXPECT().ConsumeRecoveryCode(ctx, userID, gomock.Any()).Return(false, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(maxFailedLogins, nil)
+		deps.usersRepo.EXPECT().LockUntil(ctx, userID, gomock.Any()).Return(nil)
+
+		_, challenge, err := deps.svc.Login(ctx, "test@example.com", "password123")
+		require.ErrorIs(t, err, ErrMFARequired)
+		_, _, err = deps.svc.VerifyMFA(ctx, challenge, "wrong-codes")
+		require.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("passing the second factor clears earlier failures", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		failing := *mfaUser
+		failing.FailedLoginAttempts = 2
+		challenge, err := tok.GenerateMFAChallenge(userID, "testsecret")
+		require.NoError(t, err)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&failing, nil)
+		deps.usersRepo.EXPECT().ConsumeTOTPStep(ctx, userID, gomock.Any()).Return(true, nil)
+		deps.usersRepo.EXPECT().ResetFailedLogins(ctx, userID).Return(nil)
+
+		_, access, err := deps.svc.VerifyMFA(ctx, challenge, currentCode(t))
+		require.NoError(t, err)
+		require.NotEmpty(t, access)
+	})
+
+	t.Run("access token is not a valid challenge", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		access, err := tok.GenerateToken(userID, "testsecret")
+		require.NoError(t, err)
+
+		_, _, err = deps.svc.VerifyMFA(ctx, access, currentCode(t))
+		require.ErrorIs(t, err, ErrParseToken)
+	})
+}
+
+func TestAuthService_DisableTOTP(t *testing.T) {
+	const hashedPassword = "$2a$10$nzAyuLjvw2JKqKETtpFyvukYMwsMoAByVcziZ7RGnZUlQvehEJ8qq"
+	ctx := context.Background()
+	userID := uuid.New()
+	secret := testTOTPSecret
+	enabledAt := time.Now()
+	mfaUser := &models.User{ID: userID, PasswordHash: hashedPassword, TOTPSecret: &secret, TOTPEnabledAt: &enabledAt}
+
+	t.Run("requires password and code", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().ConsumeTOTPStep(ctx, userID, gomock.Any()).Return(true, nil)
+		deps.usersRepo.EXPECT().DisableTOTP(ctx, userID).Return(nil)
+
+		require.NoError(t, deps.svc.DisableTOTP(ctx, userID, "password123", currentCode(t)))
+	})
+
+	t.Run("wrong password counts as failed login", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(1, nil)
+
+		require.ErrorIs(t, deps.svc.DisableTOTP(ctx, userID, "wrong", currentCode(t)), ErrInvalidCredentials)
+	})
+
+	t.Run("wrong code counts as failed login and locks", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(mfaUser, nil)
+		deps.usersRepo.EXPECT().ConsumeRecoveryCode(ctx, userID, gomock.Any()).Return(false, nil)
+		deps.usersRepo.EXPECT().RecordFailedLogin(ctx, userID).Return(maxFailedLogins, nil)
+		deps.usersRepo.EXPECT().LockUntil(ctx, userID, gomock.Any()).Return(nil)
+
+		require.ErrorIs(t, deps.svc.DisableTOTP(ctx, userID, "password123", "wrong-codes"), ErrInvalidMFACode)
+	})
+
+	t.Run("locked account", func(t *testing.T) {
+		deps := newAuthDeps(t)
+		locked := *mfaUser
+		until := time.Now().Add(time.Hour)
+		locked.LockedUntil = &until
+		deps.usersRepo.EXPECT().FindByID(ctx, userID).Return(&locked, nil)
+
+		require.ErrorIs(t, deps.svc.DisableTOTP(ctx, userID, "password123", currentCode(t)), ErrAccountLocked)
+	})
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: adherence.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAdherenceSessionRepository is a mock of AdherenceSessionRepository interface.
+type MockAdherenceSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdherenceSessionRepositoryMockRecorder
+}
+
+// MockAdherenceSessionRepositoryMockRecorder is the mock recorder for MockAdherenceSessionRepository.
+type MockAdherenceSessionRepositoryMockRecorder struct {
+	mock *MockAdherenceSessionRepository
+}
+
+// NewMockAdherenceSessionRepository creates a new mock instance.
+func NewMockAdherenceSessionRepository(ctrl *gomock.Controller) *MockAdherenceSessionRepository {
+	mock := &MockAdherenceSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockAdherenceSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdherenceSessionRepository) EXPECT() *MockAdherenceSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetWithSets mocks base method.
+func (m *MockAdherenceSessionRepository) GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithSets", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithSets indicates an expected call of GetWithSets.
+func (mr *MockAdherenceSessionRepositoryMockRecorder) GetWithSets(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithSets", reflect.TypeOf((*MockAdherenceSessionRepository)(nil).GetWithSets), ctx, userID, sessionID)
+}
+
+// ListPlannedSessions mocks base method.
+func (m *MockAdherenceSessionRepository) ListPlannedSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPlannedSessions", ctx, userID, since)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPlannedSessions indicates an expected call of ListPlannedSessions.
+func (mr *MockAdherenceSessionRepositoryMockRecorder) ListPlannedSessions(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPlannedSessions", reflect.TypeOf((*MockAdherenceSessionRepository)(nil).ListPlannedSessions), ctx, userID, since)
+}
@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bodyweight.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockBodyweightRepository is a mock of BodyweightRepository interface.
+type MockBodyweightRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBodyweightRepositoryMockRecorder
+}
+
+// MockBodyweightRepositoryMockRecorder is the mock recorder for MockBodyweightRepository.
+type MockBodyweightRepositoryMockRecorder struct {
+	mock *MockBodyweightRepository
+}
+
+// NewMockBodyweightRepository creates a new mock instance.
+func NewMockBodyweightRepository(ctrl *gomock.Controller) *MockBodyweightRepository {
+	mock := &MockBodyweightRepository{ctrl: ctrl}
+	mock.recorder = &MockBodyweightRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBodyweightRepository) EXPECT() *MockBodyweightRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBodyweightRepository) Create(ctx context.Context, userID uuid.UUID, weightKG float64, measuredAt time.Time) (*models.BodyweightEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, weightKG, measuredAt)
+	ret0, _ := ret[0].(*models.BodyweightEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBodyweightRepositoryMockRecorder) Create(ctx, userID, weightKG, measuredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBodyweightRepository)(nil).Create), ctx, userID, weightKG, measuredAt)
+}
+
+// List mocks base method.
+func (m *MockBodyweightRepository) List(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]*models.BodyweightEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBodyweightRepositoryMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBodyweightRepository)(nil).List), ctx, userID)
+}
+
+// Nearest mocks base method.
+func (m *MockBodyweightRepository) Nearest(ctx context.Context, userID uuid.UUID, at time.Time) (*models.BodyweightEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nearest", ctx, userID, at)
+	ret0, _ := ret[0].(*models.BodyweightEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nearest indicates an expected call of Nearest.
+func (mr *MockBodyweightRepositoryMockRecorder) Nearest(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nearest", reflect.TypeOf((*MockBodyweightRepository)(nil).Nearest), ctx, userID, at)
+}
+
+// MockExerciseRepository is a mock of ExerciseRepository interface.
+type MockExerciseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExerciseRepositoryMockRecorder
+}
+
+// MockExerciseRepositoryMockRecorder is the mock recorder for MockExerciseRepository.
+type MockExerciseRepositoryMockRecorder struct {
+	mock *MockExerciseRepository
+}
+
+// NewMockExerciseRepository creates a new mock instance.
+func NewMockExerciseRepository(ctrl *gomock.Controller) *MockExerciseRepository {
+	mock := &MockExerciseRepository{ctrl: ctrl}
+	mock.recorder = &MockExerciseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExerciseRepository) EXPECT() *MockExerciseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByIDs mocks base method.
+func (m *MockExerciseRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[uuid.UUID]models.Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockExerciseRepositoryMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockExerciseRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// MockMetricsSessionRepository is a mock of MetricsSessionRepository interface.
+type MockMetricsSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsSessionRepositoryMockRecorder
+}
+
+// MockMetricsSessionRepositoryMockRecorder is the mock recorder for MockMetricsSessionRepository.
+type MockMetricsSessionRepositoryMockRecorder struct {
+	mock *MockMetricsSessionRepository
+}
+
+// NewMockMetricsSessionRepository creates a new mock instance.
+func NewMockMetricsSessionRepository(ctrl *gomock.Controller) *MockMetricsSessionRepository {
+	mock := &MockMetricsSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockMetricsSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricsSessionRepository) EXPECT() *MockMetricsSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetWithSets mocks base method.
+func (m *MockMetricsSessionRepository) GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithSets", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithSets indicates an expected call of GetWithSets.
+func (mr *MockMetricsSessionRepositoryMockRecorder) GetWithSets(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithSets", reflect.TypeOf((*MockMetricsSessionRepository)(nil).GetWithSets), ctx, userID, sessionID)
+}
@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calendar.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockCalendarSessionRepository is a mock of CalendarSessionRepository interface.
+type MockCalendarSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarSessionRepositoryMockRecorder
+}
+
+// MockCalendarSessionRepositoryMockRecorder is the mock recorder for MockCalendarSessionRepository.
+type MockCalendarSessionRepositoryMockRecorder struct {
+	mock *MockCalendarSessionRepository
+}
+
+// NewMockCalendarSessionRepository creates a new mock instance.
+func NewMockCalendarSessionRepository(ctrl *gomock.Controller) *MockCalendarSessionRepository {
+	mock := &MockCalendarSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockCalendarSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarSessionRepository) EXPECT() *MockCalendarSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AbandonPlanned mocks base method.
+func (m *MockCalendarSessionRepository) AbandonPlanned(ctx context.Context, userID, sessionID uuid.UUID, plannedAt, at time.Time) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbandonPlanned", ctx, userID, sessionID, plannedAt, at)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbandonPlanned indicates an expected call of AbandonPlanned.
+func (mr *MockCalendarSessionRepositoryMockRecorder) AbandonPlanned(ctx, userID, sessionID, plannedAt, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbandonPlanned", reflect.TypeOf((*MockCalendarSessionRepository)(nil).AbandonPlanned), ctx, userID, sessionID, plannedAt, at)
+}
+
+// ListBetween mocks base method.
+func (m *MockCalendarSessionRepository) ListBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBetween", ctx, userID, from, to)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBetween indicates an expected call of ListBetween.
+func (mr *MockCalendarSessionRepositoryMockRecorder) ListBetween(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBetween", reflect.TypeOf((*MockCalendarSessionRepository)(nil).ListBetween), ctx, userID, from, to)
+}
+
+// ListOverduePlanned mocks base method.
+func (m *MockCalendarSessionRepository) ListOverduePlanned(ctx context.Context, before time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverduePlanned", ctx, before)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverduePlanned indicates an expected call of ListOverduePlanned.
+func (mr *MockCalendarSessionRepositoryMockRecorder) ListOverduePlanned(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverduePlanned", reflect.TypeOf((*MockCalendarSessionRepository)(nil).ListOverduePlanned), ctx, before)
+}
+
+// ListUpcomingPlanned mocks base method.
+func (m *MockCalendarSessionRepository) ListUpcomingPlanned(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpcomingPlanned", ctx, userID, from)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpcomingPlanned indicates an expected call of ListUpcomingPlanned.
+func (mr *MockCalendarSessionRepositoryMockRecorder) ListUpcomingPlanned(ctx, userID, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpcomingPlanned", reflect.TypeOf((*MockCalendarSessionRepository)(nil).ListUpcomingPlanned), ctx, userID, from)
+}
+
+// Reschedule mocks base method.
+func (m *MockCalendarSessionRepository) Reschedule(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, userID, sessionID, at)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockCalendarSessionRepositoryMockRecorder) Reschedule(ctx, userID, sessionID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockCalendarSessionRepository)(nil).Reschedule), ctx, userID, sessionID, at)
+}
+
+// RescheduleMissed mocks base method.
+func (m *MockCalendarSessionRepository) RescheduleMissed(ctx context.Context, userID, sessionID uuid.UUID, plannedAt, at time.Time) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleMissed", ctx, userID, sessionID, plannedAt, at)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RescheduleMissed indicates an expected call of RescheduleMissed.
+func (mr *MockCalendarSessionRepositoryMockRecorder) RescheduleMissed(ctx, userID, sessionID, plannedAt, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleMissed", reflect.TypeOf((*MockCalendarSessionRepository)(nil).RescheduleMissed), ctx, userID, sessionID, plannedAt, at)
+}
+
+// SessionBelongsToUser mocks base method.
+func (m *MockCalendarSessionRepository) SessionBelongsToUser(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionBelongsToUser", ctx, sessionID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionBelongsToUser indicates an expected call of SessionBelongsToUser.
+func (mr *MockCalendarSessionRepositoryMockRecorder) SessionBelongsToUser(ctx, sessionID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionBelongsToUser", reflect.TypeOf((*MockCalendarSessionRepository)(nil).SessionBelongsToUser), ctx, sessionID, userID)
+}
+
+// MockCalendarFeedRepository is a mock of CalendarFeedRepository interface.
+type MockCalendarFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarFeedRepositoryMockRecorder
+}
+
+// MockCalendarFeedRepositoryMockRecorder is the mock recorder for MockCalendarFeedRepository.
+type MockCalendarFeedRepositoryMockRecorder struct {
+	mock *MockCalendarFeedRepository
+}
+
+// NewMockCalendarFeedRepository creates a new mock instance.
+func NewMockCalendarFeedRepository(ctrl *gomock.Controller) *MockCalendarFeedRepository {
+	mock := &MockCalendarFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockCalendarFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarFeedRepository) EXPECT() *MockCalendarFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteCalendarFeedToken mocks base method.
+func (m *MockCalendarFeedRepository) DeleteCalendarFeedToken(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendarFeedToken", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendarFeedToken indicates an expected call of DeleteCalendarFeedToken.
+func (mr *MockCalendarFeedRepositoryMockRecorder) DeleteCalendarFeedToken(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendarFeedToken", reflect.TypeOf((*MockCalendarFeedRepository)(nil).DeleteCalendarFeedToken), ctx, userID)
+}
+
+// FindByCalendarFeedToken mocks base method.
+func (m *MockCalendarFeedRepository) FindByCalendarFeedToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCalendarFeedToken", ctx, tokenHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCalendarFeedToken indicates an expected call of FindByCalendarFeedToken.
+func (mr *MockCalendarFeedRepositoryMockRecorder) FindByCalendarFeedToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCalendarFeedToken", reflect.TypeOf((*MockCalendarFeedRepository)(nil).FindByCalendarFeedToken), ctx, tokenHash)
+}
+
+// SetCalendarFeedToken mocks base method.
+func (m *MockCalendarFeedRepository) SetCalendarFeedToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCalendarFeedToken", ctx, userID, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCalendarFeedToken indicates an expected call of SetCalendarFeedToken.
+func (mr *MockCalendarFeedRepositoryMockRecorder) SetCalendarFeedToken(ctx, userID, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCalendarFeedToken", reflect.TypeOf((*MockCalendarFeedRepository)(nil).SetCalendarFeedToken), ctx, userID, tokenHash)
+}
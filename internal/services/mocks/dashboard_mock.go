@@ -0,0 +1,174 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dashboard.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDashboardSessionSource is a mock of DashboardSessionSource interface.
+type MockDashboardSessionSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockDashboardSessionSourceMockRecorder
+}
+
+// MockDashboardSessionSourceMockRecorder is the mock recorder for MockDashboardSessionSource.
+type MockDashboardSessionSourceMockRecorder struct {
+	mock *MockDashboardSessionSource
+}
+
+// NewMockDashboardSessionSource creates a new mock instance.
+func NewMockDashboardSessionSource(ctrl *gomock.Controller) *MockDashboardSessionSource {
+	mock := &MockDashboardSessionSource{ctrl: ctrl}
+	mock.recorder = &MockDashboardSessionSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDashboardSessionSource) EXPECT() *MockDashboardSessionSourceMockRecorder {
+	return m.recorder
+}
+
+// LastSession mocks base method.
+func (m *MockDashboardSessionSource) LastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastSession", ctx, userID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastSession indicates an expected call of LastSession.
+func (mr *MockDashboardSessionSourceMockRecorder) LastSession(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastSession", reflect.TypeOf((*MockDashboardSessionSource)(nil).LastSession), ctx, userID)
+}
+
+// NextPlannedSession mocks base method.
+func (m *MockDashboardSessionSource) NextPlannedSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextPlannedSession", ctx, userID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextPlannedSession indicates an expected call of NextPlannedSession.
+func (mr *MockDashboardSessionSourceMockRecorder) NextPlannedSession(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPlannedSession", reflect.TypeOf((*MockDashboardSessionSource)(nil).NextPlannedSession), ctx, userID)
+}
+
+// MockDashboardPlanSource is a mock of DashboardPlanSource interface.
+type MockDashboardPlanSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockDashboardPlanSourceMockRecorder
+}
+
+// MockDashboardPlanSourceMockRecorder is the mock recorder for MockDashboardPlanSource.
+type MockDashboardPlanSourceMockRecorder struct {
+	mock *MockDashboardPlanSource
+}
+
+// NewMockDashboardPlanSource creates a new mock instance.
+func NewMockDashboardPlanSource(ctrl *gomock.Controller) *MockDashboardPlanSource {
+	mock := &MockDashboardPlanSource{ctrl: ctrl}
+	mock.recorder = &MockDashboardPlanSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDashboardPlanSource) EXPECT() *MockDashboardPlanSourceMockRecorder {
+	return m.recorder
+}
+
+// NextSuggestion mocks base method.
+func (m *MockDashboardPlanSource) NextSuggestion(ctx context.Context, userID uuid.UUID) (*models.PlanSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextSuggestion", ctx, userID)
+	ret0, _ := ret[0].(*models.PlanSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextSuggestion indicates an expected call of NextSuggestion.
+func (mr *MockDashboardPlanSourceMockRecorder) NextSuggestion(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextSuggestion", reflect.TypeOf((*MockDashboardPlanSource)(nil).NextSuggestion), ctx, userID)
+}
+
+// MockDashboardStatsSource is a mock of DashboardStatsSource interface.
+type MockDashboardStatsSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockDashboardStatsSourceMockRecorder
+}
+
+// MockDashboardStatsSourceMockRecorder is the mock recorder for MockDashboardStatsSource.
+type MockDashboardStatsSourceMockRecorder struct {
+	mock *MockDashboardStatsSource
+}
+
+// NewMockDashboardStatsSource creates a new mock instance.
+func NewMockDashboardStatsSource(ctrl *gomock.Controller) *MockDashboardStatsSource {
+	mock := &MockDashboardStatsSource{ctrl: ctrl}
+	mock.recorder = &MockDashboardStatsSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDashboardStatsSource) EXPECT() *MockDashboardStatsSourceMockRecorder {
+	return m.recorder
+}
+
+// Consistency mocks base method.
+func (m *MockDashboardStatsSource) Consistency(ctx context.Context, userID uuid.UUID, weeks int) (*models.ConsistencyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consistency", ctx, userID, weeks)
+	ret0, _ := ret[0].(*models.ConsistencyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consistency indicates an expected call of Consistency.
+func (mr *MockDashboardStatsSourceMockRecorder) Consistency(ctx, userID, weeks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consistency", reflect.TypeOf((*MockDashboardStatsSource)(nil).Consistency), ctx, userID, weeks)
+}
+
+// RecentRecords mocks base method.
+func (m *MockDashboardStatsSource) RecentRecords(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.PersonalRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentRecords", ctx, userID, since)
+	ret0, _ := ret[0].([]models.PersonalRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecentRecords indicates an expected call of RecentRecords.
+func (mr *MockDashboardStatsSourceMockRecorder) RecentRecords(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentRecords", reflect.TypeOf((*MockDashboardStatsSource)(nil).RecentRecords), ctx, userID, since)
+}
+
+// WeeklyVolume mocks base method.
+func (m *MockDashboardStatsSource) WeeklyVolume(ctx context.Context, userID uuid.UUID, weeks int) ([]models.WeeklyVolume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WeeklyVolume", ctx, userID, weeks)
+	ret0, _ := ret[0].([]models.WeeklyVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WeeklyVolume indicates an expected call of WeeklyVolume.
+func (mr *MockDashboardStatsSourceMockRecorder) WeeklyVolume(ctx, userID, weeks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WeeklyVolume", reflect.TypeOf((*MockDashboardStatsSource)(nil).WeeklyVolume), ctx, userID, weeks)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: equipment.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockEquipmentRepository is a mock of EquipmentRepository interface.
+type MockEquipmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEquipmentRepositoryMockRecorder
+}
+
+// MockEquipmentRepositoryMockRecorder is the mock recorder for MockEquipmentRepository.
+type MockEquipmentRepositoryMockRecorder struct {
+	mock *MockEquipmentRepository
+}
+
+// NewMockEquipmentRepository creates a new mock instance.
+func NewMockEquipmentRepository(ctrl *gomock.Controller) *MockEquipmentRepository {
+	mock := &MockEquipmentRepository{ctrl: ctrl}
+	mock.recorder = &MockEquipmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEquipmentRepository) EXPECT() *MockEquipmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockEquipmentRepository) Get(ctx context.Context, userID uuid.UUID) (*models.Equipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*models.Equipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockEquipmentRepositoryMockRecorder) Get(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEquipmentRepository)(nil).Get), ctx, userID)
+}
+
+// Save mocks base method.
+func (m *MockEquipmentRepository) Save(ctx context.Context, e *models.Equipment) (*models.Equipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, e)
+	ret0, _ := ret[0].(*models.Equipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockEquipmentRepositoryMockRecorder) Save(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockEquipmentRepository)(nil).Save), ctx, e)
+}
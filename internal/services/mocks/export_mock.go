@@ -0,0 +1,257 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: export.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockExportSessionRepository is a mock of ExportSessionRepository interface.
+type MockExportSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportSessionRepositoryMockRecorder
+}
+
+// MockExportSessionRepositoryMockRecorder is the mock recorder for MockExportSessionRepository.
+type MockExportSessionRepositoryMockRecorder struct {
+	mock *MockExportSessionRepository
+}
+
+// NewMockExportSessionRepository creates a new mock instance.
+func NewMockExportSessionRepository(ctrl *gomock.Controller) *MockExportSessionRepository {
+	mock := &MockExportSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockExportSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportSessionRepository) EXPECT() *MockExportSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListExercisesForUser mocks base method.
+func (m *MockExportSessionRepository) ListExercisesForUser(ctx context.Context, userID uuid.UUID) ([]models.Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExercisesForUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExercisesForUser indicates an expected call of ListExercisesForUser.
+func (mr *MockExportSessionRepositoryMockRecorder) ListExercisesForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExercisesForUser", reflect.TypeOf((*MockExportSessionRepository)(nil).ListExercisesForUser), ctx, userID)
+}
+
+// ListWithSets mocks base method.
+func (m *MockExportSessionRepository) ListWithSets(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithSets", ctx, userID)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithSets indicates an expected call of ListWithSets.
+func (mr *MockExportSessionRepositoryMockRecorder) ListWithSets(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithSets", reflect.TypeOf((*MockExportSessionRepository)(nil).ListWithSets), ctx, userID)
+}
+
+// MockExportIdentityRepository is a mock of ExportIdentityRepository interface.
+type MockExportIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportIdentityRepositoryMockRecorder
+}
+
+// MockExportIdentityRepositoryMockRecorder is the mock recorder for MockExportIdentityRepository.
+type MockExportIdentityRepositoryMockRecorder struct {
+	mock *MockExportIdentityRepository
+}
+
+// NewMockExportIdentityRepository creates a new mock instance.
+func NewMockExportIdentityRepository(ctrl *gomock.Controller) *MockExportIdentityRepository {
+	mock := &MockExportIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockExportIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportIdentityRepository) EXPECT() *MockExportIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListByUser mocks base method.
+func (m *MockExportIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockExportIdentityRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockExportIdentityRepository)(nil).ListByUser), ctx, userID)
+}
+
+// MockExportBodyweightRepository is a mock of ExportBodyweightRepository interface.
+type MockExportBodyweightRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportBodyweightRepositoryMockRecorder
+}
+
+// MockExportBodyweightRepositoryMockRecorder is the mock recorder for MockExportBodyweightRepository.
+type MockExportBodyweightRepositoryMockRecorder struct {
+	mock *MockExportBodyweightRepository
+}
+
+// NewMockExportBodyweightRepository creates a new mock instance.
+func NewMockExportBodyweightRepository(ctrl *gomock.Controller) *MockExportBodyweightRepository {
+	mock := &MockExportBodyweightRepository{ctrl: ctrl}
+	mock.recorder = &MockExportBodyweightRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportBodyweightRepository) EXPECT() *MockExportBodyweightRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockExportBodyweightRepository) List(ctx context.Context, userID uuid.UUID) ([]*models.BodyweightEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]*models.BodyweightEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockExportBodyweightRepositoryMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockExportBodyweightRepository)(nil).List), ctx, userID)
+}
+
+// MockExportEquipmentRepository is a mock of ExportEquipmentRepository interface.
+type MockExportEquipmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportEquipmentRepositoryMockRecorder
+}
+
+// MockExportEquipmentRepositoryMockRecorder is the mock recorder for MockExportEquipmentRepository.
+type MockExportEquipmentRepositoryMockRecorder struct {
+	mock *MockExportEquipmentRepository
+}
+
+// NewMockExportEquipmentRepository creates a new mock instance.
+func NewMockExportEquipmentRepository(ctrl *gomock.Controller) *MockExportEquipmentRepository {
+	mock := &MockExportEquipmentRepository{ctrl: ctrl}
+	mock.recorder = &MockExportEquipmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportEquipmentRepository) EXPECT() *MockExportEquipmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockExportEquipmentRepository) Get(ctx context.Context, userID uuid.UUID) (*models.Equipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*models.Equipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExportEquipmentRepositoryMockRecorder) Get(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExportEquipmentRepository)(nil).Get), ctx, userID)
+}
+
+// MockExportTemplateRepository is a mock of ExportTemplateRepository interface.
+type MockExportTemplateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportTemplateRepositoryMockRecorder
+}
+
+// MockExportTemplateRepositoryMockRecorder is the mock recorder for MockExportTemplateRepository.
+type MockExportTemplateRepositoryMockRecorder struct {
+	mock *MockExportTemplateRepository
+}
+
+// NewMockExportTemplateRepository creates a new mock instance.
+func NewMockExportTemplateRepository(ctrl *gomock.Controller) *MockExportTemplateRepository {
+	mock := &MockExportTemplateRepository{ctrl: ctrl}
+	mock.recorder = &MockExportTemplateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportTemplateRepository) EXPECT() *MockExportTemplateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockExportTemplateRepository) List(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]*models.SessionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockExportTemplateRepositoryMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockExportTemplateRepository)(nil).List), ctx, userID)
+}
+
+// MockExportReadinessRepository is a mock of ExportReadinessRepository interface.
+type MockExportReadinessRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportReadinessRepositoryMockRecorder
+}
+
+// MockExportReadinessRepositoryMockRecorder is the mock recorder for MockExportReadinessRepository.
+type MockExportReadinessRepositoryMockRecorder struct {
+	mock *MockExportReadinessRepository
+}
+
+// NewMockExportReadinessRepository creates a new mock instance.
+func NewMockExportReadinessRepository(ctrl *gomock.Controller) *MockExportReadinessRepository {
+	mock := &MockExportReadinessRepository{ctrl: ctrl}
+	mock.recorder = &MockExportReadinessRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportReadinessRepository) EXPECT() *MockExportReadinessRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListForUser mocks base method.
+func (m *MockExportReadinessRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]*models.ReadinessCheckIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForUser", ctx, userID)
+	ret0, _ := ret[0].([]*models.ReadinessCheckIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForUser indicates an expected call of ListForUser.
+func (mr *MockExportReadinessRepositoryMockRecorder) ListForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForUser", reflect.TypeOf((*MockExportReadinessRepository)(nil).ListForUser), ctx, userID)
+}
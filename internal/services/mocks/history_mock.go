@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: history.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockHistorySessionRepository is a mock of HistorySessionRepository interface.
+type MockHistorySessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistorySessionRepositoryMockRecorder
+}
+
+// MockHistorySessionRepositoryMockRecorder is the mock recorder for MockHistorySessionRepository.
+type MockHistorySessionRepositoryMockRecorder struct {
+	mock *MockHistorySessionRepository
+}
+
+// NewMockHistorySessionRepository creates a new mock instance.
+func NewMockHistorySessionRepository(ctrl *gomock.Controller) *MockHistorySessionRepository {
+	mock := &MockHistorySessionRepository{ctrl: ctrl}
+	mock.recorder = &MockHistorySessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistorySessionRepository) EXPECT() *MockHistorySessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListExerciseHistory mocks base method.
+func (m *MockHistorySessionRepository) ListExerciseHistory(ctx context.Context, userID, exerciseID uuid.UUID) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExerciseHistory", ctx, userID, exerciseID)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExerciseHistory indicates an expected call of ListExerciseHistory.
+func (mr *MockHistorySessionRepositoryMockRecorder) ListExerciseHistory(ctx, userID, exerciseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExerciseHistory", reflect.TypeOf((*MockHistorySessionRepository)(nil).ListExerciseHistory), ctx, userID, exerciseID)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/alexanderramin/kalistheniks/internal/models"
 	gomock "github.com/golang/mock/gomock"
@@ -36,6 +37,21 @@ func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddPlannedSet mocks base method.
+func (m *MockSessionRepository) AddPlannedSet(ctx context.Context, p *models.PlannedSet) (*models.PlannedSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPlannedSet", ctx, p)
+	ret0, _ := ret[0].(*models.PlannedSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPlannedSet indicates an expected call of AddPlannedSet.
+func (mr *MockSessionRepositoryMockRecorder) AddPlannedSet(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPlannedSet", reflect.TypeOf((*MockSessionRepository)(nil).AddPlannedSet), ctx, p)
+}
+
 // AddSet mocks base method.
 func (m *MockSessionRepository) AddSet(ctx context.Context, set *models.Set) (*models.Set, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +67,21 @@ func (mr *MockSessionRepositoryMockRecorder) AddSet(ctx, set interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSet", reflect.TypeOf((*MockSessionRepository)(nil).AddSet), ctx, set)
 }
 
+// CloseStaleSessions mocks base method.
+func (m *MockSessionRepository) CloseStaleSessions(ctx context.Context, startedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseStaleSessions", ctx, startedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseStaleSessions indicates an expected call of CloseStaleSessions.
+func (mr *MockSessionRepositoryMockRecorder) CloseStaleSessions(ctx, startedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseStaleSessions", reflect.TypeOf((*MockSessionRepository)(nil).CloseStaleSessions), ctx, startedBefore)
+}
+
 // Create mocks base method.
 func (m *MockSessionRepository) Create(ctx context.Context, s *models.Session) (*models.Session, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +97,126 @@ func (mr *MockSessionRepositoryMockRecorder) Create(ctx, s interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepository)(nil).Create), ctx, s)
 }
 
+// CreateGroup mocks base method.
+func (m *MockSessionRepository) CreateGroup(ctx context.Context, g *models.SetGroup) (*models.SetGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, g)
+	ret0, _ := ret[0].(*models.SetGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockSessionRepositoryMockRecorder) CreateGroup(ctx, g interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockSessionRepository)(nil).CreateGroup), ctx, g)
+}
+
+// CreateWithPlan mocks base method.
+func (m *MockSessionRepository) CreateWithPlan(ctx context.Context, s *models.Session) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithPlan", ctx, s)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithPlan indicates an expected call of CreateWithPlan.
+func (mr *MockSessionRepositoryMockRecorder) CreateWithPlan(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithPlan", reflect.TypeOf((*MockSessionRepository)(nil).CreateWithPlan), ctx, s)
+}
+
+// FinishSession mocks base method.
+func (m *MockSessionRepository) FinishSession(ctx context.Context, userID, sessionID uuid.UUID, status string, at time.Time) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSession", ctx, userID, sessionID, status, at)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishSession indicates an expected call of FinishSession.
+func (mr *MockSessionRepositoryMockRecorder) FinishSession(ctx, userID, sessionID, status, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSession", reflect.TypeOf((*MockSessionRepository)(nil).FinishSession), ctx, userID, sessionID, status, at)
+}
+
+// GetGroup mocks base method.
+func (m *MockSessionRepository) GetGroup(ctx context.Context, sessionID, groupID uuid.UUID) (*models.SetGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", ctx, sessionID, groupID)
+	ret0, _ := ret[0].(*models.SetGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockSessionRepositoryMockRecorder) GetGroup(ctx, sessionID, groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockSessionRepository)(nil).GetGroup), ctx, sessionID, groupID)
+}
+
+// GetLastSession mocks base method.
+func (m *MockSessionRepository) GetLastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastSession", ctx, userID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastSession indicates an expected call of GetLastSession.
+func (mr *MockSessionRepositoryMockRecorder) GetLastSession(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSession", reflect.TypeOf((*MockSessionRepository)(nil).GetLastSession), ctx, userID)
+}
+
+// GetStatus mocks base method.
+func (m *MockSessionRepository) GetStatus(ctx context.Context, userID, sessionID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, userID, sessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockSessionRepositoryMockRecorder) GetStatus(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockSessionRepository)(nil).GetStatus), ctx, userID, sessionID)
+}
+
+// GetWithSets mocks base method.
+func (m *MockSessionRepository) GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithSets", ctx, userID, sessionID)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithSets indicates an expected call of GetWithSets.
+func (mr *MockSessionRepositoryMockRecorder) GetWithSets(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithSets", reflect.TypeOf((*MockSessionRepository)(nil).GetWithSets), ctx, userID, sessionID)
+}
+
+// ListUpcomingPlanned mocks base method.
+func (m *MockSessionRepository) ListUpcomingPlanned(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpcomingPlanned", ctx, userID, from)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpcomingPlanned indicates an expected call of ListUpcomingPlanned.
+func (mr *MockSessionRepositoryMockRecorder) ListUpcomingPlanned(ctx, userID, from interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpcomingPlanned", reflect.TypeOf((*MockSessionRepository)(nil).ListUpcomingPlanned), ctx, userID, from)
+}
+
 // ListWithSets mocks base method.
 func (m *MockSessionRepository) ListWithSets(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
 	m.ctrl.T.Helper()
@@ -95,3 +246,18 @@ func (mr *MockSessionRepositoryMockRecorder) SessionBelongsToUser(ctx, sessionID
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionBelongsToUser", reflect.TypeOf((*MockSessionRepository)(nil).SessionBelongsToUser), ctx, sessionID, userID)
 }
+
+// StartSession mocks base method.
+func (m *MockSessionRepository) StartSession(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSession", ctx, userID, sessionID, at)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSession indicates an expected call of StartSession.
+func (mr *MockSessionRepositoryMockRecorder) StartSession(ctx, userID, sessionID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSession", reflect.TypeOf((*MockSessionRepository)(nil).StartSession), ctx, userID, sessionID, at)
+}
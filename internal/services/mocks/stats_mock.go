@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: stats.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockStatsSessionRepository is a mock of StatsSessionRepository interface.
+type MockStatsSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsSessionRepositoryMockRecorder
+}
+
+// MockStatsSessionRepositoryMockRecorder is the mock recorder for MockStatsSessionRepository.
+type MockStatsSessionRepositoryMockRecorder struct {
+	mock *MockStatsSessionRepository
+}
+
+// NewMockStatsSessionRepository creates a new mock instance.
+func NewMockStatsSessionRepository(ctrl *gomock.Controller) *MockStatsSessionRepository {
+	mock := &MockStatsSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockStatsSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatsSessionRepository) EXPECT() *MockStatsSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListCompleted mocks base method.
+func (m *MockStatsSessionRepository) ListCompleted(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCompleted", ctx, userID)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCompleted indicates an expected call of ListCompleted.
+func (mr *MockStatsSessionRepositoryMockRecorder) ListCompleted(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCompleted", reflect.TypeOf((*MockStatsSessionRepository)(nil).ListCompleted), ctx, userID)
+}
+
+// ListTrainedSince mocks base method.
+func (m *MockStatsSessionRepository) ListTrainedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrainedSince", ctx, userID, since)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrainedSince indicates an expected call of ListTrainedSince.
+func (mr *MockStatsSessionRepositoryMockRecorder) ListTrainedSince(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrainedSince", reflect.TypeOf((*MockStatsSessionRepository)(nil).ListTrainedSince), ctx, userID, since)
+}
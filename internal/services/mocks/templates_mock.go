@@ -0,0 +1,120 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: templates.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockTemplateRepository is a mock of TemplateRepository interface.
+type MockTemplateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateRepositoryMockRecorder
+}
+
+// MockTemplateRepositoryMockRecorder is the mock recorder for MockTemplateRepository.
+type MockTemplateRepositoryMockRecorder struct {
+	mock *MockTemplateRepository
+}
+
+// NewMockTemplateRepository creates a new mock instance.
+func NewMockTemplateRepository(ctrl *gomock.Controller) *MockTemplateRepository {
+	mock := &MockTemplateRepository{ctrl: ctrl}
+	mock.recorder = &MockTemplateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateRepository) EXPECT() *MockTemplateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTemplateRepository) Create(ctx context.Context, t *models.SessionTemplate) (*models.SessionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(*models.SessionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTemplateRepositoryMockRecorder) Create(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTemplateRepository)(nil).Create), ctx, t)
+}
+
+// Get mocks base method.
+func (m *MockTemplateRepository) Get(ctx context.Context, userID, templateID uuid.UUID) (*models.SessionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, templateID)
+	ret0, _ := ret[0].(*models.SessionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTemplateRepositoryMockRecorder) Get(ctx, userID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTemplateRepository)(nil).Get), ctx, userID, templateID)
+}
+
+// List mocks base method.
+func (m *MockTemplateRepository) List(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]*models.SessionTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTemplateRepositoryMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTemplateRepository)(nil).List), ctx, userID)
+}
+
+// MockProgressionSource is a mock of ProgressionSource interface.
+type MockProgressionSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockProgressionSourceMockRecorder
+}
+
+// MockProgressionSourceMockRecorder is the mock recorder for MockProgressionSource.
+type MockProgressionSourceMockRecorder struct {
+	mock *MockProgressionSource
+}
+
+// NewMockProgressionSource creates a new mock instance.
+func NewMockProgressionSource(ctrl *gomock.Controller) *MockProgressionSource {
+	mock := &MockProgressionSource{ctrl: ctrl}
+	mock.recorder = &MockProgressionSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProgressionSource) EXPECT() *MockProgressionSourceMockRecorder {
+	return m.recorder
+}
+
+// Targets mocks base method.
+func (m *MockProgressionSource) Targets(ctx context.Context, userID uuid.UUID, exerciseIDs []uuid.UUID) (map[uuid.UUID]*models.PlanSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Targets", ctx, userID, exerciseIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]*models.PlanSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Targets indicates an expected call of Targets.
+func (mr *MockProgressionSourceMockRecorder) Targets(ctx, userID, exerciseIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Targets", reflect.TypeOf((*MockProgressionSource)(nil).Targets), ctx, userID, exerciseIDs)
+}
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: workload.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/alexanderramin/kalistheniks/internal/models"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockWorkloadSessionRepository is a mock of WorkloadSessionRepository interface.
+type MockWorkloadSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkloadSessionRepositoryMockRecorder
+}
+
+// MockWorkloadSessionRepositoryMockRecorder is the mock recorder for MockWorkloadSessionRepository.
+type MockWorkloadSessionRepositoryMockRecorder struct {
+	mock *MockWorkloadSessionRepository
+}
+
+// NewMockWorkloadSessionRepository creates a new mock instance.
+func NewMockWorkloadSessionRepository(ctrl *gomock.Controller) *MockWorkloadSessionRepository {
+	mock := &MockWorkloadSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockWorkloadSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkloadSessionRepository) EXPECT() *MockWorkloadSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListTrainedSince mocks base method.
+func (m *MockWorkloadSessionRepository) ListTrainedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrainedSince", ctx, userID, since)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrainedSince indicates an expected call of ListTrainedSince.
+func (mr *MockWorkloadSessionRepositoryMockRecorder) ListTrainedSince(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrainedSince", reflect.TypeOf((*MockWorkloadSessionRepository)(nil).ListTrainedSince), ctx, userID, since)
+}
+
+// MockReadinessRepository is a mock of ReadinessRepository interface.
+type MockReadinessRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadinessRepositoryMockRecorder
+}
+
+// MockReadinessRepositoryMockRecorder is the mock recorder for MockReadinessRepository.
+type MockReadinessRepositoryMockRecorder struct {
+	mock *MockReadinessRepository
+}
+
+// NewMockReadinessRepository creates a new mock instance.
+func NewMockReadinessRepository(ctrl *gomock.Controller) *MockReadinessRepository {
+	mock := &MockReadinessRepository{ctrl: ctrl}
+	mock.recorder = &MockReadinessRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReadinessRepository) EXPECT() *MockReadinessRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetForDay mocks base method.
+func (m *MockReadinessRepository) GetForDay(ctx context.Context, userID uuid.UUID, day time.Time) (*models.ReadinessCheckIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForDay", ctx, userID, day)
+	ret0, _ := ret[0].(*models.ReadinessCheckIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForDay indicates an expected call of GetForDay.
+func (mr *MockReadinessRepositoryMockRecorder) GetForDay(ctx, userID, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForDay", reflect.TypeOf((*MockReadinessRepository)(nil).GetForDay), ctx, userID, day)
+}
+
+// Upsert mocks base method.
+func (m *MockReadinessRepository) Upsert(ctx context.Context, c *models.ReadinessCheckIn) (*models.ReadinessCheckIn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, c)
+	ret0, _ := ret[0].(*models.ReadinessCheckIn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockReadinessRepositoryMockRecorder) Upsert(ctx, c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockReadinessRepository)(nil).Upsert), ctx, c)
+}
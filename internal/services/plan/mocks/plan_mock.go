@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/alexanderramin/kalistheniks/internal/models"
 	gomock "github.com/golang/mock/gomock"
@@ -36,6 +37,21 @@ func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetLastExerciseSet mocks base method.
+func (m *MockSessionRepository) GetLastExerciseSet(ctx context.Context, userID, exerciseID uuid.UUID) (*models.Set, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastExerciseSet", ctx, userID, exerciseID)
+	ret0, _ := ret[0].(*models.Set)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastExerciseSet indicates an expected call of GetLastExerciseSet.
+func (mr *MockSessionRepositoryMockRecorder) GetLastExerciseSet(ctx, userID, exerciseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastExerciseSet", reflect.TypeOf((*MockSessionRepository)(nil).GetLastExerciseSet), ctx, userID, exerciseID)
+}
+
 // GetLastSession mocks base method.
 func (m *MockSessionRepository) GetLastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
 	m.ctrl.T.Helper()
@@ -65,3 +81,170 @@ func (mr *MockSessionRepositoryMockRecorder) GetLastSet(ctx, userID interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSet", reflect.TypeOf((*MockSessionRepository)(nil).GetLastSet), ctx, userID)
 }
+
+// ListPlannedSessions mocks base method.
+func (m *MockSessionRepository) ListPlannedSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPlannedSessions", ctx, userID, since)
+	ret0, _ := ret[0].([]*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPlannedSessions indicates an expected call of ListPlannedSessions.
+func (mr *MockSessionRepositoryMockRecorder) ListPlannedSessions(ctx, userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPlannedSessions", reflect.TypeOf((*MockSessionRepository)(nil).ListPlannedSessions), ctx, userID, since)
+}
+
+// MockProfileRepository is a mock of ProfileRepository interface.
+type MockProfileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileRepositoryMockRecorder
+}
+
+// MockProfileRepositoryMockRecorder is the mock recorder for MockProfileRepository.
+type MockProfileRepositoryMockRecorder struct {
+	mock *MockProfileRepository
+}
+
+// NewMockProfileRepository creates a new mock instance.
+func NewMockProfileRepository(ctrl *gomock.Controller) *MockProfileRepository {
+	mock := &MockProfileRepository{ctrl: ctrl}
+	mock.recorder = &MockProfileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileRepository) EXPECT() *MockProfileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetProfile mocks base method.
+func (m *MockProfileRepository) GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, userID)
+	ret0, _ := ret[0].(*models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfileRepositoryMockRecorder) GetProfile(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileRepository)(nil).GetProfile), ctx, userID)
+}
+
+// MockExerciseRepository is a mock of ExerciseRepository interface.
+type MockExerciseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExerciseRepositoryMockRecorder
+}
+
+// MockExerciseRepositoryMockRecorder is the mock recorder for MockExerciseRepository.
+type MockExerciseRepositoryMockRecorder struct {
+	mock *MockExerciseRepository
+}
+
+// NewMockExerciseRepository creates a new mock instance.
+func NewMockExerciseRepository(ctrl *gomock.Controller) *MockExerciseRepository {
+	mock := &MockExerciseRepository{ctrl: ctrl}
+	mock.recorder = &MockExerciseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExerciseRepository) EXPECT() *MockExerciseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByIDs mocks base method.
+func (m *MockExerciseRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[uuid.UUID]models.Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockExerciseRepositoryMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockExerciseRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// MockEquipmentSource is a mock of EquipmentSource interface.
+type MockEquipmentSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockEquipmentSourceMockRecorder
+}
+
+// MockEquipmentSourceMockRecorder is the mock recorder for MockEquipmentSource.
+type MockEquipmentSourceMockRecorder struct {
+	mock *MockEquipmentSource
+}
+
+// NewMockEquipmentSource creates a new mock instance.
+func NewMockEquipmentSource(ctrl *gomock.Controller) *MockEquipmentSource {
+	mock := &MockEquipmentSource{ctrl: ctrl}
+	mock.recorder = &MockEquipmentSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEquipmentSource) EXPECT() *MockEquipmentSourceMockRecorder {
+	return m.recorder
+}
+
+// GetEquipment mocks base method.
+func (m *MockEquipmentSource) GetEquipment(ctx context.Context, userID uuid.UUID) (*models.Equipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEquipment", ctx, userID)
+	ret0, _ := ret[0].(*models.Equipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEquipment indicates an expected call of GetEquipment.
+func (mr *MockEquipmentSourceMockRecorder) GetEquipment(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEquipment", reflect.TypeOf((*MockEquipmentSource)(nil).GetEquipment), ctx, userID)
+}
+
+// MockWorkloadSource is a mock of WorkloadSource interface.
+type MockWorkloadSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkloadSourceMockRecorder
+}
+
+// MockWorkloadSourceMockRecorder is the mock recorder for MockWorkloadSource.
+type MockWorkloadSourceMockRecorder struct {
+	mock *MockWorkloadSource
+}
+
+// NewMockWorkloadSource creates a new mock instance.
+func NewMockWorkloadSource(ctrl *gomock.Controller) *MockWorkloadSource {
+	mock := &MockWorkloadSource{ctrl: ctrl}
+	mock.recorder = &MockWorkloadSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkloadSource) EXPECT() *MockWorkloadSourceMockRecorder {
+	return m.recorder
+}
+
+// Workload mocks base method.
+func (m *MockWorkloadSource) Workload(ctx context.Context, userID uuid.UUID) (*models.Workload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Workload", ctx, userID)
+	ret0, _ := ret[0].(*models.Workload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Workload indicates an expected call of Workload.
+func (mr *MockWorkloadSourceMockRecorder) Workload(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Workload", reflect.TypeOf((*MockWorkloadSource)(nil).Workload), ctx, userID)
+}
@@ -4,46 +4,202 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/plates"
+	"github.com/alexanderramin/kalistheniks/internal/rules"
+	"github.com/alexanderramin/kalistheniks/internal/units"
 	"github.com/google/uuid"
 )
 
 type SessionRepository interface {
 	GetLastSet(ctx context.Context, userID uuid.UUID) (*models.Set, error)
 	GetLastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error)
+	GetLastExerciseSet(ctx context.Context, userID, exerciseID uuid.UUID) (*models.Set, error)
+	ListPlannedSessions(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error)
+}
+
+// ProfileRepository exposes the user preferences that tailor suggestions.
+type ProfileRepository interface {
+	GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error)
+}
+
+type ExerciseRepository interface {
+	FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Exercise, error)
+}
+
+// EquipmentSource returns the user's equipment, or standard defaults; *services.EquipmentService implements it.
+type EquipmentSource interface {
+	GetEquipment(ctx context.Context, userID uuid.UUID) (*models.Equipment, error)
+}
+
+// WorkloadSource reports the user's training load and how far to scale volume for it;
+// *services.WorkloadService implements it.
+type WorkloadSource interface {
+	Workload(ctx context.Context, userID uuid.UUID) (*models.Workload, error)
 }
 
 // PlanService holds simple V1 progression logic.
 type PlanService struct {
-	sessions SessionRepository
+	sessions  SessionRepository
+	profiles  ProfileRepository
+	exercises ExerciseRepository
+	equipment EquipmentSource
+	workload  WorkloadSource
+	rules     *rules.RuleEngine
 }
 
-func NewPlanService(repo SessionRepository) *PlanService {
-	return &PlanService{sessions: repo}
+func NewPlanService(repo SessionRepository, profiles ProfileRepository, exercises ExerciseRepository, equipment EquipmentSource, workload WorkloadSource) *PlanService {
+	return &PlanService{sessions: repo, profiles: profiles, exercises: exercises, equipment: equipment, workload: workload, rules: rules.New()}
 }
 
+// adherenceWindow is how far back planned sessions count towards the adherence that steers progression.
+const adherenceWindow = 28 * 24 * time.Hour
+
 // TODO: replace with a proper rule engine integration.
 // NextSuggestion returns a naive progression recommendation based on the last recorded set.
+// Loads are worked out in the user's unit system and snapped to what their equipment can make, and
+// volume is scaled down while the user's workload or readiness calls for it.
 func (p *PlanService) NextSuggestion(ctx context.Context, userID uuid.UUID) (*models.PlanSuggestion, error) {
 	if userID == uuid.Nil {
 		return nil, errors.New("invalid user ID")
 	}
+	profile, err := p.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 	lastSet, err := p.sessions.GetLastSet(ctx, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			// No history: start with a default.
+			// No history: start with the user's empty bar.
+			equipment, err := p.equipment.GetEquipment(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
 			id := uuid.New()
-			return &models.PlanSuggestion{
-				ExerciseID: id,
-				WeightKG:   20,
-				Reps:       8,
-				Notes:      "No history found; starting default weight and reps.",
-			}, nil
+			return withUnits(&models.PlanSuggestion{
+				ExerciseID:  id,
+				WeightKG:    equipment.BarKG,
+				Reps:        8,
+				RestSeconds: defaultRestSeconds,
+				Notes:       "No history found; starting default weight and reps.",
+			}, profile.Units), nil
 		}
 		return nil, err
 	}
 
+	exercises, err := p.exercises.FindByIDs(ctx, []uuid.UUID{lastSet.ExerciseID})
+	if err != nil {
+		return nil, err
+	}
+	exercise, known := exercises[lastSet.ExerciseID]
+	plans, err := p.sessions.ListPlannedSessions(ctx, userID, time.Now().Add(-adherenceWindow))
+	if err != nil {
+		return nil, err
+	}
+	workload, err := p.workload.Workload(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	suggestion, err := p.progress(ctx, userID, lastSet, exercise, known, profile, plans, workload)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastSession, err := p.sessions.GetLastSession(ctx, userID); err == nil {
+		if lastSession.SessionType != nil {
+			switch *lastSession.SessionType {
+			case "upper":
+				suggestion.Notes += " Next: switch to lower body."
+			case "lower":
+				suggestion.Notes += " Next: switch to upper body."
+			}
+		}
+	}
+
+	return withUnits(suggestion, profile.Units), nil
+}
+
+// Targets returns the progression target for the next working set of each exercise, keyed by exercise.
+// Exercises the user has never trained are left out.
+func (p *PlanService) Targets(ctx context.Context, userID uuid.UUID, exerciseIDs []uuid.UUID) (map[uuid.UUID]*models.PlanSuggestion, error) {
+	profile, err := p.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	exercises, err := p.exercises.FindByIDs(ctx, exerciseIDs)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := p.sessions.ListPlannedSessions(ctx, userID, time.Now().Add(-adherenceWindow))
+	if err != nil {
+		return nil, err
+	}
+	workload, err := p.workload.Workload(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[uuid.UUID]*models.PlanSuggestion, len(exerciseIDs))
+	for _, id := range exerciseIDs {
+		lastSet, err := p.sessions.GetLastExerciseSet(ctx, userID, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		exercise, known := exercises[id]
+		suggestion, err := p.progress(ctx, userID, lastSet, exercise, known, profile, plans, workload)
+		if err != nil {
+			return nil, err
+		}
+		targets[id] = withUnits(suggestion, profile.Units)
+	}
+	return targets, nil
+}
+
+// progress works out the next set of an exercise from its last set, by the way the exercise is measured.
+// How well the user kept to the recent plans for the exercise decides whether it may progress at all.
+// The workload then scales the volume, and the rest is picked for the set as scaled.
+func (p *PlanService) progress(ctx context.Context, userID uuid.UUID, lastSet *models.Set, exercise models.Exercise, known bool, profile *models.Profile, plans []*models.Session, workload *models.Workload) (*models.PlanSuggestion, error) {
+	var adherence models.Adherence
+	for _, session := range plans {
+		adherence = rules.Add(adherence, rules.Measure(session, &lastSet.ExerciseID))
+	}
+	step := p.rules.ProgressionStep(adherence)
+
+	var suggestion *models.PlanSuggestion
+	var err error
+	switch exercise.Measurement {
+	case models.MeasurementHold, models.MeasurementDuration:
+		suggestion = progressTime(lastSet, exercise.Measurement, step)
+	case models.MeasurementDistance:
+		suggestion = &models.PlanSuggestion{
+			ExerciseID:      lastSet.ExerciseID,
+			WeightKG:        lastSet.WeightKG,
+			DurationSeconds: lastSet.DurationSeconds,
+			DistanceM:       lastSet.DistanceM,
+			Notes:           "Maintain load and distance.",
+		}
+	default:
+		suggestion, err = p.progressReps(ctx, userID, lastSet, exercise, known, profile, step)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scaleVolume(suggestion, workload)
+	suggestion.RestSeconds = suggestRest(exercise, lastSet, suggestion)
+	return suggestion, nil
+}
+
+// progressReps moves a rep exercise through the rep range, adding load at the top of it. A hold repeats
+// the last set, and a deload takes a tenth off its load.
+func (p *PlanService) progressReps(ctx context.Context, userID uuid.UUID, lastSet *models.Set, exercise models.Exercise, known bool, profile *models.Profile, step rules.Step) (*models.PlanSuggestion, error) {
 	const upperRepRange = 12
 	const lowerRepRange = 6
 	suggestion := &models.PlanSuggestion{
@@ -52,9 +208,15 @@ func (p *PlanService) NextSuggestion(ctx context.Context, userID uuid.UUID) (*mo
 		Reps:       lastSet.Reps,
 	}
 
+	increase := step == rules.Progress && lastSet.Reps >= upperRepRange
 	switch {
-	case lastSet.Reps >= upperRepRange:
-		suggestion.WeightKG = lastSet.WeightKG + 2.5
+	case step == rules.Deload:
+		suggestion.WeightKG = lastSet.WeightKG * deloadFactor
+		suggestion.Notes = "Recent plans were missed; deload and rebuild."
+	case step == rules.Hold:
+		suggestion.Notes = "Recent plans were not fully completed; repeat this load."
+	case increase:
+		suggestion.WeightKG = increaseLoad(lastSet.WeightKG, profile)
 		suggestion.Notes = "Hit upper range; increase weight."
 	case lastSet.Reps <= lowerRepRange:
 		suggestion.Reps = lastSet.Reps - 1
@@ -63,16 +225,196 @@ func (p *PlanService) NextSuggestion(ctx context.Context, userID uuid.UUID) (*mo
 		suggestion.Notes = "Maintain weight and rep target."
 	}
 
-	if lastSession, err := p.sessions.GetLastSession(ctx, userID); err == nil {
-		if lastSession.SessionType != nil {
-			switch *lastSession.SessionType {
-			case "upper":
-				suggestion.Notes += " Next: switch to lower body."
-			case "lower":
-				suggestion.Notes += " Next: switch to upper body."
+	if !known {
+		return suggestion, nil
+	}
+	var err error
+	suggestion.WeightKG, err = p.snapLoad(ctx, userID, lastSet, exercise, suggestion.WeightKG, profile.Units, increase)
+	if err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// progressTime extends the time of a hold or timed set by about a tenth, in steps of at least five
+// seconds, unless the last set was rated at or near failure or recent plans were not kept. Load is left
+// as it was.
+func progressTime(lastSet *models.Set, measurement string, step rules.Step) *models.PlanSuggestion {
+	const holdStepSeconds = 5
+	suggestion := &models.PlanSuggestion{
+		ExerciseID: lastSet.ExerciseID,
+		WeightKG:   lastSet.WeightKG,
+	}
+	if lastSet.DurationSeconds == nil {
+		suggestion.Notes = "No time recorded; log how long the last set lasted."
+		return suggestion
+	}
+
+	seconds := *lastSet.DurationSeconds
+	switch {
+	case step != rules.Progress:
+		suggestion.Notes = "Recent plans were not fully completed; repeat the same time."
+	case lastSet.RPE != nil && *lastSet.RPE >= 9:
+		suggestion.Notes = "Near max; repeat the same time."
+	default:
+		step := max(holdStepSeconds, (seconds/10+holdStepSeconds/2)/holdStepSeconds*holdStepSeconds)
+		seconds += step
+		suggestion.Notes = "Held the target; extend the time."
+		if measurement == models.MeasurementHold {
+			suggestion.Notes = "Held the target; extend the hold."
+		}
+	}
+	suggestion.DurationSeconds = &seconds
+	return suggestion
+}
+
+// scaleVolume takes the suggested reps, time or distance down by the workload's volume scale, keeping
+// at least one rep or second. Load is left alone, so the set stays as heavy but shorter.
+func scaleVolume(s *models.PlanSuggestion, w *models.Workload) {
+	if w.VolumeScale >= 1 {
+		return
+	}
+	scale := w.VolumeScale
+	switch {
+	case s.DistanceM != nil:
+		distance := math.Round(*s.DistanceM * scale)
+		s.DistanceM = &distance
+	case s.DurationSeconds != nil:
+		seconds := max(1, int(math.Round(float64(*s.DurationSeconds)*scale)))
+		s.DurationSeconds = &seconds
+	case s.Reps > 0:
+		s.Reps = max(1, int(math.Round(float64(s.Reps)*scale)))
+	default:
+		return
+	}
+	s.VolumeScale = &scale
+	s.Notes += " " + w.Notes
+}
+
+// deloadFactor scales the load of a rep exercise whose recent plans were missed by a wide margin.
+const deloadFactor = 0.9
+
+// defaultRestSeconds is the rest suggested when there is nothing to base it on.
+const defaultRestSeconds = 120
+
+// suggestRest picks the rest before the suggested set. Heavy, low-rep work needs the longest rest and
+// barbell lifts a little more than other loading; a hold or timed set is rested about as long as it
+// lasts. The last set's RPE moves the rest by half a minute either way.
+func suggestRest(exercise models.Exercise, lastSet *models.Set, s *models.PlanSuggestion) int {
+	const minRest, maxRest = 30, 300
+	var rest int
+	switch exercise.Measurement {
+	case models.MeasurementHold, models.MeasurementDuration:
+		rest = defaultRestSeconds
+		if s.DurationSeconds != nil {
+			rest = min(max(*s.DurationSeconds, 60), 180)
+		}
+	case models.MeasurementDistance:
+		rest = defaultRestSeconds
+	default:
+		switch {
+		case s.Reps <= 5:
+			rest = 180
+		case s.Reps <= 8:
+			rest = 150
+		case s.Reps <= 12:
+			rest = 120
+		default:
+			rest = 90
+		}
+		if exercise.Equipment == models.EquipmentBarbell {
+			rest += 30
+		}
+	}
+
+	if lastSet.RPE != nil {
+		switch {
+		case *lastSet.RPE >= 9:
+			rest += 30
+		case *lastSet.RPE <= 6:
+			rest -= 30
+		}
+	}
+	return min(max(rest, minRest), maxRest)
+}
+
+// increaseLoad adds one plate increment in the user's unit and snaps the result to that increment, so
+// an odd logged load such as 61.23 kg (135 lb) becomes 140 lb rather than 140.01. Advanced lifters are
+// closer to their ceiling, so they progress in smaller steps.
+func increaseLoad(weightKG float64, profile *models.Profile) float64 {
+	advanced := profile.Experience != nil && *profile.Experience == models.ExperienceAdvanced
+	step := units.Increment(profile.Units, advanced)
+	next := units.Snap(units.FromKG(weightKG, profile.Units)+step, step)
+	return units.ToKG(next, profile.Units)
+}
+
+// snapLoad moves weightKG to the nearest load the user's equipment can make for the exercise of the
+// last set. An increase never snaps back down to the last load; if the equipment cannot make the
+// planned jump, the next heavier achievable load is used. Equipment with nothing to choose from
+// leaves the load as planned.
+func (p *PlanService) snapLoad(ctx context.Context, userID uuid.UUID, lastSet *models.Set, exercise models.Exercise, weightKG float64, system string, increase bool) (float64, error) {
+	equipment, err := p.equipment.GetEquipment(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	target := units.FromKG(weightKG, system)
+	options := loadOptions(exercise.Equipment, equipment, system, target)
+	if len(options) == 0 {
+		return weightKG, nil
+	}
+	snapped := plates.Nearest(target, options)
+	if current := units.FromKG(lastSet.WeightKG, system); increase && snapped <= current {
+		for _, o := range options {
+			if o > current {
+				snapped = o
+				break
 			}
 		}
 	}
+	if snapped == target {
+		return weightKG, nil
+	}
+	return units.ToKG(snapped, system), nil
+}
 
-	return suggestion, nil
+// loadOptions lists, ascending and in the user's unit, the loads the equipment makes for an
+// exercise: the bar with pairs of plates, a single dumbbell, or any stack of plates on a dip belt.
+// Without saved plates there is nothing to choose from for plate-loaded exercises.
+func loadOptions(kind string, e *models.Equipment, system string, around float64) []float64 {
+	if kind != models.EquipmentDumbbell && len(e.PlatesKG) == 0 {
+		return nil
+	}
+	available := make([]float64, len(e.PlatesKG))
+	for i, p := range e.PlatesKG {
+		available[i] = units.FromKG(p, system)
+	}
+
+	switch kind {
+	case models.EquipmentBarbell:
+		bar := units.FromKG(e.BarKG, system)
+		sides := plates.Sums(available, around)
+		options := make([]float64, len(sides))
+		for i, s := range sides {
+			options[i] = bar + 2*s
+		}
+		return options
+	case models.EquipmentDumbbell:
+		options := make([]float64, len(e.DumbbellsKG))
+		for i, d := range e.DumbbellsKG {
+			options[i] = units.FromKG(d, system)
+		}
+		sort.Float64s(options)
+		return options
+	case models.EquipmentBodyweight:
+		return plates.Sums(available, 2*around+1)
+	}
+	return nil
+}
+
+// withUnits fills in the suggested weight in the user's unit alongside the canonical kilograms.
+func withUnits(s *models.PlanSuggestion, system string) *models.PlanSuggestion {
+	s.Weight = units.FromKG(s.WeightKG, system)
+	s.Unit = units.Symbol(system)
+	return s
 }
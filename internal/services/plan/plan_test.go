@@ -12,7 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-//go:generate mockgen -source=plan.go -destination=./mocks/plan_mock.go -package=mocks SessionRepository
+// steadyWorkload reports a workload that leaves volume alone.
+func steadyWorkload(ctrl *gomock.Controller) *mocks.MockWorkloadSource {
+	workload := mocks.NewMockWorkloadSource(ctrl)
+	workload.EXPECT().Workload(gomock.Any(), gomock.Any()).Return(&models.Workload{VolumeScale: 1}, nil).AnyTimes()
+	return workload
+}
+
+//go:generate mockgen -source=plan.go -destination=./mocks/plan_mock.go -package=mocks SessionRepository,ProfileRepository,ExerciseRepository,EquipmentSource,WorkloadSource
 func TestPlanService_NextSuggestion(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -21,13 +28,19 @@ func TestPlanService_NextSuggestion(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
 
-		service := NewPlanService(mockSessionRepository)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
 		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(&models.Set{
 			ExerciseID: exerciseID,
 			WeightKG:   0.0,
 			Reps:       10,
 		}, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
 		stype := "workout"
 		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(&models.Session{
 			SessionType: &stype,
@@ -43,26 +56,38 @@ func TestPlanService_NextSuggestion(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
 
-		service := NewPlanService(mockSessionRepository)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
 		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(nil, sql.ErrNoRows)
+		mockEquipment.EXPECT().GetEquipment(ctx, userID).Return(&models.Equipment{BarKG: 20}, nil)
 		suggestion, err := service.NextSuggestion(ctx, userID)
 		require.NoError(t, err)
 		require.Equal(t, 20.0, suggestion.WeightKG)
 		require.Equal(t, 8, suggestion.Reps)
+		require.Equal(t, 120, suggestion.RestSeconds)
 		require.Contains(t, suggestion.Notes, "No history found")
 	})
 	t.Run("increases weight on high reps", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
 
-		service := NewPlanService(mockSessionRepository)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
 		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(&models.Set{
 			ExerciseID: exerciseID,
 			WeightKG:   40.0,
 			Reps:       13,
 		}, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
 		stype := "workout"
 		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(&models.Session{
 			SessionType: &stype,
@@ -70,6 +95,8 @@ func TestPlanService_NextSuggestion(t *testing.T) {
 		suggestion, err := service.NextSuggestion(ctx, userID)
 		require.NoError(t, err)
 		require.Equal(t, 42.5, suggestion.WeightKG)
+		require.Equal(t, 42.5, suggestion.Weight)
+		require.Equal(t, "kg", suggestion.Unit)
 		require.Equal(t, 13, suggestion.Reps)
 		require.Contains(t, suggestion.Notes, "increase weight")
 	})
@@ -78,13 +105,19 @@ func TestPlanService_NextSuggestion(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
 
-		service := NewPlanService(mockSessionRepository)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
 		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(&models.Set{
 			ExerciseID: exerciseID,
 			WeightKG:   60.0,
 			Reps:       5,
 		}, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
 		stype := "workout"
 		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(&models.Session{
 			SessionType: &stype,
@@ -100,13 +133,19 @@ func TestPlanService_NextSuggestion(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
 
-		service := NewPlanService(mockSessionRepository)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
 		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(&models.Set{
 			ExerciseID: exerciseID,
 			WeightKG:   0.0,
 			Reps:       8,
 		}, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
 		stype := "upper"
 		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(&models.Session{
 			SessionType: &stype,
@@ -116,14 +155,389 @@ func TestPlanService_NextSuggestion(t *testing.T) {
 		require.Contains(t, suggestion.Notes, "switch to lower body")
 	})
 
+	t.Run("advanced lifters progress in smaller steps", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		advanced := models.ExperienceAdvanced
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Experience: &advanced}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(&models.Set{
+			ExerciseID: exerciseID,
+			WeightKG:   100.0,
+			Reps:       12,
+		}, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
+		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 101.25, suggestion.WeightKG)
+	})
+
+	t.Run("imperial lifters progress in pound plates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsImperial}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(&models.Set{
+			ExerciseID: exerciseID,
+			WeightKG:   61.23, // 135 lb
+			Reps:       12,
+		}, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
+		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 140.0, suggestion.Weight)
+		require.Equal(t, "lb", suggestion.Unit)
+		require.Equal(t, 63.5, suggestion.WeightKG)
+	})
+
+	t.Run("default starts with the empty bar", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsImperial}, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(nil, sql.ErrNoRows)
+		mockEquipment.EXPECT().GetEquipment(ctx, userID).Return(&models.Equipment{BarKG: 20.41}, nil)
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 45.0, suggestion.Weight)
+		require.Equal(t, 20.41, suggestion.WeightKG)
+	})
+
 	t.Run("handles repository error", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
 
-		service := NewPlanService(mockSessionRepository)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
 		_, err := service.NextSuggestion(ctx, uuid.Nil)
 		require.Error(t, err)
 		require.ErrorContains(t, err, "invalid user ID")
 	})
 }
+
+func TestPlanService_SnapsToEquipment(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exerciseID := uuid.New()
+
+	suggest := func(t *testing.T, kind string, equipment *models.Equipment, last *models.Set) *models.PlanSuggestion {
+		ctrl := gomock.NewController(t)
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+
+		last.ExerciseID = exerciseID
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(last, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).
+			Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID, Equipment: kind}}, nil)
+		mockEquipment.EXPECT().GetEquipment(ctx, userID).Return(equipment, nil)
+		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		return suggestion
+	}
+
+	t.Run("barbell increase takes the next load the plates make", func(t *testing.T) {
+		s := suggest(t, models.EquipmentBarbell, &models.Equipment{BarKG: 20, PlatesKG: []float64{5}}, &models.Set{WeightKG: 80, Reps: 12})
+		require.Equal(t, 90.0, s.WeightKG)
+	})
+
+	t.Run("barbell increase with fractional plates", func(t *testing.T) {
+		s := suggest(t, models.EquipmentBarbell, &models.Equipment{BarKG: 20, PlatesKG: []float64{5, 1.25}}, &models.Set{WeightKG: 80, Reps: 12})
+		require.Equal(t, 82.5, s.WeightKG)
+	})
+
+	t.Run("dumbbell load snaps to the rack", func(t *testing.T) {
+		s := suggest(t, models.EquipmentDumbbell, &models.Equipment{DumbbellsKG: []float64{25, 20, 22.5}}, &models.Set{WeightKG: 22, Reps: 8})
+		require.Equal(t, 22.5, s.WeightKG)
+	})
+
+	t.Run("weighted bodyweight progression uses single plates", func(t *testing.T) {
+		s := suggest(t, models.EquipmentBodyweight, &models.Equipment{PlatesKG: []float64{10, 5}}, &models.Set{WeightKG: 0, Reps: 12})
+		require.Equal(t, 5.0, s.WeightKG)
+	})
+
+	t.Run("no saved plates leaves the barbell load as planned", func(t *testing.T) {
+		s := suggest(t, models.EquipmentBarbell, &models.Equipment{BarKG: 20}, &models.Set{WeightKG: 80, Reps: 12})
+		require.Equal(t, 82.5, s.WeightKG)
+	})
+
+	t.Run("no saved plates leaves the bodyweight load as planned", func(t *testing.T) {
+		s := suggest(t, models.EquipmentBodyweight, &models.Equipment{}, &models.Set{WeightKG: 10, Reps: 12})
+		require.Equal(t, 12.5, s.WeightKG)
+	})
+}
+
+func TestPlanService_ProgressesTime(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exerciseID := uuid.New()
+
+	suggest := func(t *testing.T, measurement string, last *models.Set) *models.PlanSuggestion {
+		ctrl := gomock.NewController(t)
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+
+		last.ExerciseID = exerciseID
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(last, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).
+			Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID, Equipment: models.EquipmentBodyweight, Measurement: measurement}}, nil)
+		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		return suggestion
+	}
+	seconds := func(n int) *int { return &n }
+
+	t.Run("short hold extends by five seconds", func(t *testing.T) {
+		s := suggest(t, models.MeasurementHold, &models.Set{DurationSeconds: seconds(30)})
+		require.Equal(t, 35, *s.DurationSeconds)
+		require.Equal(t, 0, s.Reps)
+		require.Contains(t, s.Notes, "extend the hold")
+	})
+
+	t.Run("long hold extends by about a tenth", func(t *testing.T) {
+		s := suggest(t, models.MeasurementHold, &models.Set{DurationSeconds: seconds(120), WeightKG: 10})
+		require.Equal(t, 130, *s.DurationSeconds)
+		require.Equal(t, 10.0, s.WeightKG)
+	})
+
+	t.Run("hold near failure is repeated", func(t *testing.T) {
+		rpe := 9
+		s := suggest(t, models.MeasurementHold, &models.Set{DurationSeconds: seconds(40), RPE: &rpe})
+		require.Equal(t, 40, *s.DurationSeconds)
+		require.Contains(t, s.Notes, "repeat the same time")
+	})
+
+	t.Run("timed work extends too", func(t *testing.T) {
+		s := suggest(t, models.MeasurementDuration, &models.Set{DurationSeconds: seconds(300)})
+		require.Equal(t, 330, *s.DurationSeconds)
+	})
+
+	t.Run("distance is maintained", func(t *testing.T) {
+		meters := 40.0
+		s := suggest(t, models.MeasurementDistance, &models.Set{DistanceM: &meters, WeightKG: 24})
+		require.Equal(t, 40.0, *s.DistanceM)
+		require.Equal(t, 24.0, s.WeightKG)
+	})
+}
+
+func TestSuggestRest(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	barbell := models.Exercise{Equipment: models.EquipmentBarbell, Measurement: models.MeasurementReps}
+	bodyweight := models.Exercise{Equipment: models.EquipmentBodyweight, Measurement: models.MeasurementReps}
+	hold := models.Exercise{Equipment: models.EquipmentBodyweight, Measurement: models.MeasurementHold}
+
+	tests := []struct {
+		name       string
+		exercise   models.Exercise
+		last       *models.Set
+		suggestion *models.PlanSuggestion
+		want       int
+	}{
+		{"heavy barbell triple", barbell, &models.Set{}, &models.PlanSuggestion{Reps: 3}, 210},
+		{"moderate bodyweight reps", bodyweight, &models.Set{}, &models.PlanSuggestion{Reps: 10}, 120},
+		{"high reps", bodyweight, &models.Set{}, &models.PlanSuggestion{Reps: 20}, 90},
+		{"near failure rests longer", bodyweight, &models.Set{RPE: intPtr(10)}, &models.PlanSuggestion{Reps: 10}, 150},
+		{"easy set rests less", bodyweight, &models.Set{RPE: intPtr(5)}, &models.PlanSuggestion{Reps: 20}, 60},
+		{"heaviest work near failure", barbell, &models.Set{RPE: intPtr(9)}, &models.PlanSuggestion{Reps: 1}, 240},
+		{"short hold rests a minute", hold, &models.Set{}, &models.PlanSuggestion{DurationSeconds: intPtr(20)}, 60},
+		{"long hold rests as long as it lasts", hold, &models.Set{}, &models.PlanSuggestion{DurationSeconds: intPtr(90)}, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, suggestRest(tt.exercise, tt.last, tt.suggestion))
+		})
+	}
+}
+
+func TestPlanService_Targets(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	row, plank, untrained := uuid.New(), uuid.New(), uuid.New()
+
+	ctrl := gomock.NewController(t)
+	mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+	mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+	mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+	mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+	service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+
+	ids := []uuid.UUID{row, plank, untrained}
+	hold := 30
+	mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+	mockExerciseRepository.EXPECT().FindByIDs(ctx, ids).Return(map[uuid.UUID]models.Exercise{
+		row:   {ID: row, Measurement: models.MeasurementReps},
+		plank: {ID: plank, Equipment: models.EquipmentBodyweight, Measurement: models.MeasurementHold},
+	}, nil)
+	mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
+	mockSessionRepository.EXPECT().GetLastExerciseSet(ctx, userID, row).Return(&models.Set{ExerciseID: row, Reps: 10, WeightKG: 40}, nil)
+	mockEquipment.EXPECT().GetEquipment(ctx, userID).Return(&models.Equipment{BarKG: 20}, nil)
+	mockSessionRepository.EXPECT().GetLastExerciseSet(ctx, userID, plank).Return(&models.Set{ExerciseID: plank, DurationSeconds: &hold}, nil)
+	mockSessionRepository.EXPECT().GetLastExerciseSet(ctx, userID, untrained).Return(nil, sql.ErrNoRows)
+
+	targets, err := service.Targets(ctx, userID, ids)
+	require.NoError(t, err)
+	require.Len(t, targets, 2, "exercises without history are left out")
+	require.Equal(t, 10, targets[row].Reps)
+	require.Equal(t, 40.0, targets[row].Weight)
+	require.Equal(t, 35, *targets[plank].DurationSeconds)
+	require.Equal(t, 60, targets[plank].RestSeconds)
+}
+
+func TestPlanService_FollowsAdherence(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exerciseID := uuid.New()
+
+	// plannedSession plans three sets of 10 at 40 kg, of which the user logged the given reps.
+	plannedSession := func(reps ...int) *models.Session {
+		s := &models.Session{}
+		for i := 0; i < 3; i++ {
+			s.PlannedSets = append(s.PlannedSets, models.PlannedSet{Position: i, ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: 10, WeightKG: 40})
+		}
+		for _, r := range reps {
+			s.Sets = append(s.Sets, models.Set{ExerciseID: exerciseID, Kind: models.SetKindWorking, Reps: r, WeightKG: 40})
+		}
+		return s
+	}
+
+	suggest := func(t *testing.T, measurement string, last *models.Set, plans []*models.Session) *models.PlanSuggestion {
+		ctrl := gomock.NewController(t)
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, steadyWorkload(ctrl))
+
+		last.ExerciseID = exerciseID
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(last, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(plans, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).
+			Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID, Equipment: models.EquipmentDumbbell, Measurement: measurement}}, nil)
+		mockEquipment.EXPECT().GetEquipment(ctx, userID).Return(&models.Equipment{DumbbellsKG: []float64{30, 35, 36, 40, 45}}, nil).AnyTimes()
+		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		return suggestion
+	}
+
+	t.Run("kept plans progress as usual", func(t *testing.T) {
+		s := suggest(t, models.MeasurementReps, &models.Set{Reps: 12, WeightKG: 40}, []*models.Session{plannedSession(10, 10, 10)})
+		require.Equal(t, 45.0, s.WeightKG)
+	})
+
+	t.Run("plans short on reps hold the load", func(t *testing.T) {
+		s := suggest(t, models.MeasurementReps, &models.Set{Reps: 12, WeightKG: 40}, []*models.Session{plannedSession(10, 8, 7)})
+		require.Equal(t, 40.0, s.WeightKG)
+		require.Equal(t, 12, s.Reps)
+		require.Contains(t, s.Notes, "repeat this load")
+	})
+
+	t.Run("missed plans deload", func(t *testing.T) {
+		plans := []*models.Session{plannedSession(10), plannedSession(10, 6)}
+		s := suggest(t, models.MeasurementReps, &models.Set{Reps: 8, WeightKG: 40}, plans)
+		require.Equal(t, 36.0, s.WeightKG)
+		require.Equal(t, 8, s.Reps)
+		require.Contains(t, s.Notes, "deload")
+	})
+
+	t.Run("holds are not extended while plans are missed", func(t *testing.T) {
+		hold := 30
+		s := suggest(t, models.MeasurementHold, &models.Set{DurationSeconds: &hold}, []*models.Session{plannedSession(10)})
+		require.Equal(t, 30, *s.DurationSeconds)
+	})
+}
+
+func TestPlanService_ScalesVolumeForWorkload(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exerciseID := uuid.New()
+
+	suggest := func(t *testing.T, measurement string, last *models.Set, workload *models.Workload) *models.PlanSuggestion {
+		ctrl := gomock.NewController(t)
+		mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+		mockProfileRepository := mocks.NewMockProfileRepository(ctrl)
+		mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
+		mockEquipment := mocks.NewMockEquipmentSource(ctrl)
+		mockWorkload := mocks.NewMockWorkloadSource(ctrl)
+		service := NewPlanService(mockSessionRepository, mockProfileRepository, mockExerciseRepository, mockEquipment, mockWorkload)
+
+		last.ExerciseID = exerciseID
+		mockProfileRepository.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+		mockSessionRepository.EXPECT().GetLastSet(ctx, userID).Return(last, nil)
+		mockSessionRepository.EXPECT().ListPlannedSessions(ctx, userID, gomock.Any()).Return(nil, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).
+			Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID, Measurement: measurement}}, nil)
+		mockEquipment.EXPECT().GetEquipment(ctx, userID).Return(&models.Equipment{}, nil).AnyTimes()
+		mockWorkload.EXPECT().Workload(ctx, userID).Return(workload, nil)
+		mockSessionRepository.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		suggestion, err := service.NextSuggestion(ctx, userID)
+		require.NoError(t, err)
+		return suggestion
+	}
+	spike := &models.Workload{VolumeScale: 0.7, Notes: "Training load spiked this week; volume cut to recover."}
+
+	t.Run("steady workload leaves volume alone", func(t *testing.T) {
+		s := suggest(t, models.MeasurementReps, &models.Set{Reps: 10, WeightKG: 40}, &models.Workload{VolumeScale: 1})
+		require.Equal(t, 10, s.Reps)
+		require.Nil(t, s.VolumeScale)
+	})
+
+	t.Run("reps are cut and load kept", func(t *testing.T) {
+		s := suggest(t, models.MeasurementReps, &models.Set{Reps: 10, WeightKG: 40}, spike)
+		require.Equal(t, 7, s.Reps)
+		require.Equal(t, 40.0, s.WeightKG)
+		require.Equal(t, 0.7, *s.VolumeScale)
+		require.Contains(t, s.Notes, "spiked")
+		require.Equal(t, 150, s.RestSeconds, "rest follows the 7 reps actually suggested")
+	})
+
+	t.Run("holds are shortened", func(t *testing.T) {
+		seconds := 60
+		s := suggest(t, models.MeasurementHold, &models.Set{DurationSeconds: &seconds}, spike)
+		require.Equal(t, 46, *s.DurationSeconds, "65 s extended hold cut to 70%")
+		require.Equal(t, 60, s.RestSeconds, "rest follows the shortened hold")
+	})
+
+	t.Run("never below one rep", func(t *testing.T) {
+		s := suggest(t, models.MeasurementReps, &models.Set{Reps: 2, WeightKG: 100}, &models.Workload{VolumeScale: 0.3})
+		require.Equal(t, 1, s.Reps)
+	})
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type ProfileRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, update models.ProfileUpdate) (*models.Profile, error)
+}
+
+var (
+	ErrFindProfile   = errors.New("failed to load profile")
+	ErrUpdateProfile = errors.New("failed to update profile")
+)
+
+// ProfileService reads and updates the account owner's profile and preferences.
+type ProfileService struct {
+	profiles ProfileRepository
+}
+
+func NewProfileService(repo ProfileRepository) *ProfileService {
+	return &ProfileService{profiles: repo}
+}
+
+// GetMe returns the user together with their profile.
+func (s *ProfileService) GetMe(ctx context.Context, userID uuid.UUID) (*models.User, *models.Profile, error) {
+	user, err := s.profiles.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrFindProfile, err)
+	}
+	return user, profile, nil
+}
+
+// GetProfile returns the user's profile, which carries their unit preference.
+func (s *ProfileService) GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFindProfile, err)
+	}
+	return profile, nil
+}
+
+// UpdateProfile applies a partial update and returns the user with the resulting profile.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID uuid.UUID, update models.ProfileUpdate) (*models.User, *models.Profile, error) {
+	user, err := s.profiles.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+	profile, err := s.profiles.UpdateProfile(ctx, userID, update)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUpdateProfile, err)
+	}
+	return user, profile, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=profile.go -destination=./mocks/profile_mock.go -package=mocks
+
+func TestProfileService_GetMe(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("returns user and profile", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockProfileRepository(ctrl)
+		svc := NewProfileService(repo)
+		repo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		repo.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsMetric}, nil)
+
+		user, profile, err := svc.GetMe(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, userID, user.ID)
+		require.Equal(t, models.UnitsMetric, profile.Units)
+	})
+
+	t.Run("wraps profile error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockProfileRepository(ctrl)
+		svc := NewProfileService(repo)
+		repo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		repo.EXPECT().GetProfile(ctx, userID).Return(nil, errors.New("db down"))
+
+		_, _, err := svc.GetMe(ctx, userID)
+		require.ErrorIs(t, err, ErrFindProfile)
+	})
+}
+
+func TestProfileService_GetProfile(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockProfileRepository(ctrl)
+	svc := NewProfileService(repo)
+
+	repo.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, Units: models.UnitsImperial}, nil)
+	profile, err := svc.GetProfile(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, models.UnitsImperial, profile.Units)
+
+	repo.EXPECT().GetProfile(ctx, userID).Return(nil, errors.New("db down"))
+	_, err = svc.GetProfile(ctx, userID)
+	require.ErrorIs(t, err, ErrFindProfile)
+}
+
+func TestProfileService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	units := models.UnitsImperial
+	update := models.ProfileUpdate{Units: &units}
+
+	t.Run("applies update", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockProfileRepository(ctrl)
+		svc := NewProfileService(repo)
+		repo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		repo.EXPECT().UpdateProfile(ctx, userID, update).Return(&models.Profile{UserID: userID, Units: units}, nil)
+
+		_, profile, err := svc.UpdateProfile(ctx, userID, update)
+		require.NoError(t, err)
+		require.Equal(t, models.UnitsImperial, profile.Units)
+	})
+
+	t.Run("wraps update error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockProfileRepository(ctrl)
+		svc := NewProfileService(repo)
+		repo.EXPECT().FindByID(ctx, userID).Return(&models.User{ID: userID}, nil)
+		repo.EXPECT().UpdateProfile(ctx, userID, update).Return(nil, errors.New("constraint"))
+
+		_, _, err := svc.UpdateProfile(ctx, userID, update)
+		require.ErrorIs(t, err, ErrUpdateProfile)
+	})
+}
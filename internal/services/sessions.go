@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/alexanderramin/kalistheniks/internal/models"
@@ -14,20 +17,60 @@ type SessionRepository interface {
 	AddSet(ctx context.Context, set *models.Set) (*models.Set, error)
 	ListWithSets(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
 	SessionBelongsToUser(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (bool, error)
+	GetWithSets(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error)
+	GetStatus(ctx context.Context, userID, sessionID uuid.UUID) (string, error)
+	CreateGroup(ctx context.Context, g *models.SetGroup) (*models.SetGroup, error)
+	GetGroup(ctx context.Context, sessionID, groupID uuid.UUID) (*models.SetGroup, error)
+	StartSession(ctx context.Context, userID, sessionID uuid.UUID, at time.Time) (*models.Session, error)
+	FinishSession(ctx context.Context, userID, sessionID uuid.UUID, status string, at time.Time) (*models.Session, error)
+	CloseStaleSessions(ctx context.Context, startedBefore time.Time) (int64, error)
+	CreateWithPlan(ctx context.Context, s *models.Session) (*models.Session, error)
+	AddPlannedSet(ctx context.Context, p *models.PlannedSet) (*models.PlannedSet, error)
+	GetLastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error)
+	ListUpcomingPlanned(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Session, error)
 }
 
+// staleSessionAge is how long a session may stay in progress before CloseStaleSessions ends it.
+const staleSessionAge = 6 * time.Hour
+
+// maxClockSkew is how far ahead of the server a client's completed_at may be before it is rejected.
+const maxClockSkew = 5 * time.Minute
+
+var (
+	ErrUnknownExercise = errors.New("unknown exercise")
+	ErrInvalidSet      = errors.New("invalid set")
+	ErrUnknownGroup    = errors.New("unknown set group")
+	ErrSessionState    = errors.New("session is not in a state that allows this")
+)
+
 type SessionService struct {
-	sessions SessionRepository
+	sessions  SessionRepository
+	exercises ExerciseRepository
 }
 
-func NewSessionService(repo SessionRepository) *SessionService {
-	return &SessionService{sessions: repo}
+func NewSessionService(repo SessionRepository, exercises ExerciseRepository) *SessionService {
+	return &SessionService{sessions: repo, exercises: exercises}
 }
 
-func (s *SessionService) CreateSession(ctx context.Context, userID uuid.UUID, performedAt *time.Time, sessionType *string, notes *string) (*models.Session, error) {
+// CreateSession records a session. Without a status it is a completed workout logged after the fact; a
+// planned session is started later, and one created in progress starts now.
+func (s *SessionService) CreateSession(ctx context.Context, userID uuid.UUID, performedAt *time.Time, sessionType *string, notes *string, status string) (*models.Session, error) {
 	if userID == uuid.Nil {
 		return nil, errors.New("userID cannot be nil")
 	}
+	if status == "" {
+		status = models.SessionCompleted
+	}
+	session, err := newSession(userID, performedAt, sessionType, notes, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessions.Create(ctx, session)
+}
+
+// newSession builds a session in the given state, performed now unless performedAt says otherwise. A
+// session in progress starts, and is performed, now. Sessions cannot be created abandoned.
+func newSession(userID uuid.UUID, performedAt *time.Time, sessionType, notes *string, status string) (*models.Session, error) {
 	when := time.Now().UTC()
 	if performedAt != nil {
 		when = performedAt.UTC()
@@ -38,31 +81,286 @@ func (s *SessionService) CreateSession(ctx context.Context, userID uuid.UUID, pe
 		PerformedAt: when,
 		Notes:       notes,
 		SessionType: sessionType,
+		Status:      status,
+	}
+	switch status {
+	case models.SessionCompleted, models.SessionPlanned:
+	case models.SessionInProgress:
+		now := time.Now().UTC()
+		session.PerformedAt = now
+		session.StartedAt = &now
+	default:
+		return nil, ErrSessionState
+	}
+	return session, nil
+}
+
+// StartSession moves one of the user's planned sessions to in progress.
+func (s *SessionService) StartSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	if err := s.requireSession(ctx, userID, sessionID); err != nil {
+		return nil, err
+	}
+	session, err := s.sessions.StartSession(ctx, userID, sessionID, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionState
 	}
+	return session, err
+}
 
-	return s.sessions.Create(ctx, session)
+// FinishSession ends one of the user's sessions in progress as completed or abandoned.
+func (s *SessionService) FinishSession(ctx context.Context, userID, sessionID uuid.UUID, status string) (*models.Session, error) {
+	if status != models.SessionCompleted && status != models.SessionAbandoned {
+		return nil, ErrSessionState
+	}
+	if err := s.requireSession(ctx, userID, sessionID); err != nil {
+		return nil, err
+	}
+	session, err := s.sessions.FinishSession(ctx, userID, sessionID, status, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionState
+	}
+	return session, err
 }
 
-func (s *SessionService) AddSet(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, exerciseID uuid.UUID, setIndex, reps int, weight float64, rpe *int) (*models.Set, error) {
+// CloseStaleSessions ends sessions left in progress for longer than staleSessionAge, such as when the
+// app was closed mid-workout.
+func (s *SessionService) CloseStaleSessions(ctx context.Context) (int64, error) {
+	return s.sessions.CloseStaleSessions(ctx, time.Now().Add(-staleSessionAge))
+}
+
+// requireSession returns ErrSessionNotFound unless the session belongs to the user.
+func (s *SessionService) requireSession(ctx context.Context, userID, sessionID uuid.UUID) error {
 	owned, err := s.sessions.SessionBelongsToUser(ctx, sessionID, userID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if !owned {
-		return nil, errors.New("session does not belong to user")
+		return ErrSessionNotFound
 	}
+	return nil
+}
 
-	set := &models.Set{
-		SessionID:  sessionID,
-		ExerciseID: exerciseID,
-		SetIndex:   setIndex,
-		Reps:       reps,
-		WeightKG:   weight,
-		RPE:        rpe,
+// AddSet logs a set in one of the user's sessions in progress or completed. The fields the set must
+// carry depend on how its exercise is measured; see validateMeasurement. Sets without a kind are
+// working sets, and sets without a completion time were completed now.
+func (s *SessionService) AddSet(ctx context.Context, userID uuid.UUID, set *models.Set) (*models.Set, error) {
+	if set.Kind == "" {
+		set.Kind = models.SetKindWorking
+	}
+	now := time.Now().UTC()
+	if set.CompletedAt.IsZero() {
+		set.CompletedAt = now
+	}
+	if set.CompletedAt.After(now.Add(maxClockSkew)) {
+		return nil, fmt.Errorf("%w: completed_at is in the future", ErrInvalidSet)
+	}
+	status, err := s.sessions.GetStatus(ctx, userID, set.SessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Sets are logged live or after the fact, never into a plan or an abandoned workout.
+	if status != models.SessionInProgress && status != models.SessionCompleted {
+		return nil, ErrSessionState
+	}
+
+	exercises, err := s.exercises.FindByIDs(ctx, []uuid.UUID{set.ExerciseID})
+	if err != nil {
+		return nil, err
+	}
+	exercise, ok := exercises[set.ExerciseID]
+	if !ok {
+		return nil, ErrUnknownExercise
+	}
+	if err := validateMeasurement(set, exercise.Measurement); err != nil {
+		return nil, err
+	}
+	if err := s.validateGroup(ctx, set); err != nil {
+		return nil, err
 	}
 	return s.sessions.AddSet(ctx, set)
 }
 
+// AddPlannedSet appends a set to the plan of one of the user's sessions that has not finished yet. Planned
+// sets are checked like logged ones and are later compared with what was logged; see AdherenceService.
+func (s *SessionService) AddPlannedSet(ctx context.Context, userID uuid.UUID, planned *models.PlannedSet) (*models.PlannedSet, error) {
+	if planned.Kind == "" {
+		planned.Kind = models.SetKindWorking
+	}
+	session, err := s.sessions.GetWithSets(ctx, userID, planned.SessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.SessionPlanned && session.Status != models.SessionInProgress {
+		return nil, ErrSessionState
+	}
+
+	exercises, err := s.exercises.FindByIDs(ctx, []uuid.UUID{planned.ExerciseID})
+	if err != nil {
+		return nil, err
+	}
+	exercise, ok := exercises[planned.ExerciseID]
+	if !ok {
+		return nil, ErrUnknownExercise
+	}
+	asSet := &models.Set{
+		SessionID:       planned.SessionID,
+		Reps:            planned.Reps,
+		DurationSeconds: planned.DurationSeconds,
+		DistanceM:       planned.DistanceM,
+		GroupID:         planned.GroupID,
+		Round:           planned.Round,
+	}
+	if err := validateMeasurement(asSet, exercise.Measurement); err != nil {
+		return nil, err
+	}
+	if err := s.validateGroup(ctx, asSet); err != nil {
+		return nil, err
+	}
+	planned.Round = asSet.Round
+	return s.sessions.AddPlannedSet(ctx, planned)
+}
+
+// validateGroup checks that a grouped set names a group of its own session and a round the group
+// has; the round defaults to the first. Sets outside a group have no round.
+func (s *SessionService) validateGroup(ctx context.Context, set *models.Set) error {
+	if set.GroupID == nil {
+		if set.Round != nil {
+			return fmt.Errorf("%w: round is only allowed for sets in a group", ErrInvalidSet)
+		}
+		return nil
+	}
+	group, err := s.sessions.GetGroup(ctx, set.SessionID, *set.GroupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownGroup
+	}
+	if err != nil {
+		return err
+	}
+	if set.Round == nil {
+		first := 1
+		set.Round = &first
+	}
+	if group.Rounds != nil && *set.Round > *group.Rounds {
+		return fmt.Errorf("%w: the group has %d rounds", ErrInvalidSet, *group.Rounds)
+	}
+	return nil
+}
+
+// CreateGroup adds a superset, circuit or other block of sets to one of the user's sessions.
+func (s *SessionService) CreateGroup(ctx context.Context, userID, sessionID uuid.UUID, kind string, rounds, restSeconds *int) (*models.SetGroup, error) {
+	if err := s.requireSession(ctx, userID, sessionID); err != nil {
+		return nil, err
+	}
+	return s.sessions.CreateGroup(ctx, &models.SetGroup{
+		SessionID:   sessionID,
+		Kind:        kind,
+		Rounds:      rounds,
+		RestSeconds: restSeconds,
+	})
+}
+
+// GetSession returns one of the user's sessions with its sets and groups.
+func (s *SessionService) GetSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.Session, error) {
+	session, err := s.sessions.GetWithSets(ctx, userID, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	fillActualRest(session.Sets)
+	return session, nil
+}
+
+// LastSession returns the user's most recent session in progress or completed, with its sets, or nil when
+// they have not trained yet.
+func (s *SessionService) LastSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
+	last, err := s.sessions.GetLastSession(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSession(ctx, userID, last.ID)
+}
+
+// NextPlannedSession returns the user's soonest planned session that is not yet due, with its planned
+// sets, or nil when nothing is planned.
+func (s *SessionService) NextPlannedSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
+	upcoming, err := s.sessions.ListUpcomingPlanned(ctx, userID, time.Now())
+	if err != nil || len(upcoming) == 0 {
+		return nil, err
+	}
+	return upcoming[0], nil
+}
+
+// fillActualRest sets ActualRestSeconds on every set but the first to the time between the end of the
+// set completed before it and its own start. A timed set started DurationSeconds before it was completed;
+// other sets are treated as starting when they were completed. Overlapping times count as no rest.
+func fillActualRest(sets []models.Set) {
+	order := make([]int, len(sets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sets[order[a]].CompletedAt.Before(sets[order[b]].CompletedAt)
+	})
+
+	for i := 1; i < len(order); i++ {
+		prev, cur := &sets[order[i-1]], &sets[order[i]]
+		start := cur.CompletedAt
+		if cur.DurationSeconds != nil {
+			start = start.Add(-time.Duration(*cur.DurationSeconds) * time.Second)
+		}
+		rest := max(0, int(start.Sub(prev.CompletedAt).Round(time.Second).Seconds()))
+		cur.ActualRestSeconds = &rest
+	}
+}
+
+// validateMeasurement checks that a set carries the fields its exercise is measured by and no others:
+// reps for rep exercises, duration_seconds for holds and timed work, and distance_m, optionally with
+// the time taken, for distance work. Any of them may carry added weight.
+func validateMeasurement(set *models.Set, measurement string) error {
+	switch measurement {
+	case models.MeasurementHold, models.MeasurementDuration:
+		switch {
+		case set.DurationSeconds == nil:
+			return fmt.Errorf("%w: %s sets need duration_seconds", ErrInvalidSet, measurement)
+		case set.Reps != 0 || set.DistanceM != nil:
+			return fmt.Errorf("%w: %s sets only record duration_seconds", ErrInvalidSet, measurement)
+		}
+	case models.MeasurementDistance:
+		switch {
+		case set.DistanceM == nil:
+			return fmt.Errorf("%w: distance sets need distance_m", ErrInvalidSet)
+		case set.Reps != 0:
+			return fmt.Errorf("%w: distance sets only record distance_m and duration_seconds", ErrInvalidSet)
+		}
+	default:
+		switch {
+		case set.Reps < 1:
+			return fmt.Errorf("%w: reps sets need at least one rep", ErrInvalidSet)
+		case set.DurationSeconds != nil || set.DistanceM != nil:
+			return fmt.Errorf("%w: reps sets do not record duration_seconds or distance_m", ErrInvalidSet)
+		}
+	}
+	return nil
+}
+
 func (s *SessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
-	return s.sessions.ListWithSets(ctx, userID)
+	sessions, err := s.sessions.ListWithSets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		fillActualRest(session.Sets)
+	}
+	return sessions, nil
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -19,6 +20,7 @@ func TestSessionService_CreateSession(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+	mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
 	ctx := context.Background()
 	performedAt := time.Now().Add(-24 * time.Hour)
 	sessionType := "workout"
@@ -27,7 +29,7 @@ func TestSessionService_CreateSession(t *testing.T) {
 	notes := "Felt great!"
 
 	t.Run("creates session successfully", func(t *testing.T) {
-		service := NewSessionService(mockSessionRepository)
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
 		mockSessionRepository.EXPECT().Create(ctx, gomock.Any()).Return(&models.Session{
 			ID:          sessionID,
 			UserID:      userID,
@@ -35,16 +37,16 @@ func TestSessionService_CreateSession(t *testing.T) {
 			SessionType: &sessionType,
 			Notes:       &notes,
 		}, nil)
-		res, err := service.CreateSession(ctx, userID, &performedAt, &sessionType, &notes)
+		res, err := service.CreateSession(ctx, userID, &performedAt, &sessionType, &notes, "")
 		require.NoError(t, err)
 		require.Equal(t, sessionID, res.ID)
 
 	})
 
 	t.Run("handles repository error", func(t *testing.T) {
-		service := NewSessionService(mockSessionRepository)
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
 		mockSessionRepository.EXPECT().Create(ctx, gomock.Any()).Return(&models.Session{}, errors.New("db error"))
-		res, err := service.CreateSession(ctx, userID, &performedAt, &sessionType, &notes)
+		res, err := service.CreateSession(ctx, userID, &performedAt, &sessionType, &notes, "")
 		require.Error(t, err)
 		require.ErrorContains(t, err, "db error")
 		require.Empty(t, res)
@@ -55,15 +57,24 @@ func TestSessionService_AddSet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+	mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
 	ctx := context.Background()
 	sessionID := uuid.New()
 	setID := uuid.New()
 	exerciseID := uuid.New()
 	userID := uuid.New()
 
+	exercises := func(measurement string) map[uuid.UUID]models.Exercise {
+		return map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID, Measurement: measurement}}
+	}
+	repsSet := func() *models.Set {
+		return &models.Set{SessionID: sessionID, ExerciseID: exerciseID, SetIndex: 1, Reps: 10, WeightKG: 50.0}
+	}
+
 	t.Run("adds set successfully", func(t *testing.T) {
-		service := NewSessionService(mockSessionRepository)
-		mockSessionRepository.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(exercises(models.MeasurementReps), nil)
 		mockSessionRepository.EXPECT().AddSet(ctx, gomock.Any()).Return(&models.Set{
 			ID:         setID,
 			SessionID:  sessionID,
@@ -72,41 +83,127 @@ func TestSessionService_AddSet(t *testing.T) {
 			Reps:       10,
 			WeightKG:   50.0,
 		}, nil)
-		res, err := service.AddSet(ctx, userID, sessionID, exerciseID, 1, 10, 50.0, nil)
+		res, err := service.AddSet(ctx, userID, repsSet())
 		require.NoError(t, err)
 		require.Equal(t, setID, res.ID)
 	})
 
 	t.Run("handles session ownership error", func(t *testing.T) {
-		service := NewSessionService(mockSessionRepository)
-		mockSessionRepository.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(false, nil)
-		res, err := service.AddSet(ctx, userID, sessionID, exerciseID, 1, 10, 50.0, nil)
-		require.Error(t, err)
-		require.ErrorContains(t, err, "session does not belong to user")
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return("", sql.ErrNoRows)
+		res, err := service.AddSet(ctx, userID, repsSet())
+		require.ErrorIs(t, err, ErrSessionNotFound)
 		require.Empty(t, res)
 	})
 
+	t.Run("rejects sets in planned and abandoned sessions", func(t *testing.T) {
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		for _, status := range []string{models.SessionPlanned, models.SessionAbandoned} {
+			mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(status, nil)
+			res, err := service.AddSet(ctx, userID, repsSet())
+			require.ErrorIs(t, err, ErrSessionState, status)
+			require.Nil(t, res)
+		}
+	})
+
+	t.Run("late logging into a completed session", func(t *testing.T) {
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionCompleted, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(exercises(models.MeasurementReps), nil)
+		mockSessionRepository.EXPECT().AddSet(ctx, gomock.Any()).Return(&models.Set{ID: setID}, nil)
+		_, err := service.AddSet(ctx, userID, repsSet())
+		require.NoError(t, err)
+	})
+
 	t.Run("handles repository error", func(t *testing.T) {
-		service := NewSessionService(mockSessionRepository)
-		mockSessionRepository.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(exercises(models.MeasurementReps), nil)
 		mockSessionRepository.EXPECT().AddSet(ctx, gomock.Any()).Return(&models.Set{}, errors.New("db error"))
-		res, err := service.AddSet(ctx, userID, sessionID, exerciseID, 1, 10, 50.0, nil)
+		res, err := service.AddSet(ctx, userID, repsSet())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "db error")
 		require.Empty(t, res)
 	})
+
+	t.Run("sets without a kind are working sets", func(t *testing.T) {
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(exercises(models.MeasurementReps), nil)
+		mockSessionRepository.EXPECT().AddSet(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, set *models.Set) (*models.Set, error) {
+			return set, nil
+		})
+		res, err := service.AddSet(ctx, userID, repsSet())
+		require.NoError(t, err)
+		require.Equal(t, models.SetKindWorking, res.Kind)
+	})
+
+	t.Run("rejects unknown exercise", func(t *testing.T) {
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{}, nil)
+		res, err := service.AddSet(ctx, userID, repsSet())
+		require.ErrorIs(t, err, ErrUnknownExercise)
+		require.Nil(t, res)
+	})
+
+	t.Run("adds hold set", func(t *testing.T) {
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
+		seconds := 45
+		set := &models.Set{SessionID: sessionID, ExerciseID: exerciseID, DurationSeconds: &seconds}
+		mockSessionRepository.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		mockExerciseRepository.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(exercises(models.MeasurementHold), nil)
+		mockSessionRepository.EXPECT().AddSet(ctx, set).Return(&models.Set{ID: setID, DurationSeconds: &seconds}, nil)
+		res, err := service.AddSet(ctx, userID, set)
+		require.NoError(t, err)
+		require.Equal(t, 45, *res.DurationSeconds)
+	})
+
+	t.Run("validates fields for the measurement", func(t *testing.T) {
+		seconds := 60
+		meters := 40.0
+		cases := []struct {
+			name        string
+			measurement string
+			set         models.Set
+			ok          bool
+		}{
+			{"reps without reps", models.MeasurementReps, models.Set{}, false},
+			{"reps with duration", models.MeasurementReps, models.Set{Reps: 5, DurationSeconds: &seconds}, false},
+			{"legacy exercise counts reps", "", models.Set{Reps: 5}, true},
+			{"hold without duration", models.MeasurementHold, models.Set{}, false},
+			{"hold with reps", models.MeasurementHold, models.Set{Reps: 3, DurationSeconds: &seconds}, false},
+			{"weighted hold", models.MeasurementHold, models.Set{DurationSeconds: &seconds, WeightKG: 10}, true},
+			{"duration with distance", models.MeasurementDuration, models.Set{DurationSeconds: &seconds, DistanceM: &meters}, false},
+			{"duration", models.MeasurementDuration, models.Set{DurationSeconds: &seconds}, true},
+			{"distance without distance", models.MeasurementDistance, models.Set{DurationSeconds: &seconds}, false},
+			{"distance with time", models.MeasurementDistance, models.Set{DistanceM: &meters, DurationSeconds: &seconds}, true},
+			{"distance with reps", models.MeasurementDistance, models.Set{DistanceM: &meters, Reps: 2}, false},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				err := validateMeasurement(&tc.set, tc.measurement)
+				if tc.ok {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, ErrInvalidSet)
+				}
+			})
+		}
+	})
 }
 
 func TestSessionService_ListSessions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockSessionRepository := mocks.NewMockSessionRepository(ctrl)
+	mockExerciseRepository := mocks.NewMockExerciseRepository(ctrl)
 	ctx := context.Background()
 	userID := uuid.New()
 	sessionID := uuid.New()
 
 	t.Run("lists sessions successfully", func(t *testing.T) {
-		service := NewSessionService(mockSessionRepository)
+		service := NewSessionService(mockSessionRepository, mockExerciseRepository)
 		mockSessionRepository.EXPECT().ListWithSets(ctx, userID).Return([]*models.Session{
 			{
 				ID:     sessionID,
@@ -123,3 +220,362 @@ func TestSessionService_ListSessions(t *testing.T) {
 	})
 
 }
+
+func TestSessionService_Groups(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID, groupID, exerciseID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	rounds, rest := 3, 90
+
+	newService := func(t *testing.T) (*SessionService, *mocks.MockSessionRepository, *mocks.MockExerciseRepository) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockSessionRepository(ctrl)
+		exercises := mocks.NewMockExerciseRepository(ctrl)
+		return NewSessionService(repo, exercises), repo, exercises
+	}
+	groupedSet := func(round *int) *models.Set {
+		return &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Reps: 8, GroupID: &groupID, Round: round}
+	}
+
+	t.Run("creates group in own session", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		repo.EXPECT().CreateGroup(ctx, &models.SetGroup{SessionID: sessionID, Kind: models.GroupCircuit, Rounds: &rounds, RestSeconds: &rest}).
+			Return(&models.SetGroup{ID: groupID, SessionID: sessionID, Kind: models.GroupCircuit, Rounds: &rounds, RestSeconds: &rest}, nil)
+
+		g, err := svc.CreateGroup(ctx, userID, sessionID, models.GroupCircuit, &rounds, &rest)
+		require.NoError(t, err)
+		require.Equal(t, groupID, g.ID)
+	})
+
+	t.Run("group in someone else's session", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(false, nil)
+
+		_, err := svc.CreateGroup(ctx, userID, sessionID, models.GroupSuperset, nil, nil)
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("grouped set defaults to the first round", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		repo.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID}}, nil)
+		repo.EXPECT().GetGroup(ctx, sessionID, groupID).Return(&models.SetGroup{ID: groupID, Rounds: &rounds}, nil)
+		repo.EXPECT().AddSet(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, set *models.Set) (*models.Set, error) {
+			return set, nil
+		})
+
+		set, err := svc.AddSet(ctx, userID, groupedSet(nil))
+		require.NoError(t, err)
+		require.Equal(t, 1, *set.Round)
+	})
+
+	t.Run("grouped set beyond the planned rounds", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		round := 4
+		repo.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID}}, nil)
+		repo.EXPECT().GetGroup(ctx, sessionID, groupID).Return(&models.SetGroup{ID: groupID, Rounds: &rounds}, nil)
+
+		_, err := svc.AddSet(ctx, userID, groupedSet(&round))
+		require.ErrorIs(t, err, ErrInvalidSet)
+	})
+
+	t.Run("grouped set with a group of another session", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		repo.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID}}, nil)
+		repo.EXPECT().GetGroup(ctx, sessionID, groupID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.AddSet(ctx, userID, groupedSet(nil))
+		require.ErrorIs(t, err, ErrUnknownGroup)
+	})
+
+	t.Run("round without a group", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		round := 2
+		repo.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID}}, nil)
+
+		_, err := svc.AddSet(ctx, userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Reps: 8, Round: &round})
+		require.ErrorIs(t, err, ErrInvalidSet)
+	})
+
+	t.Run("session detail", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{ID: sessionID, Groups: []models.SetGroup{{ID: groupID}}}, nil)
+
+		session, err := svc.GetSession(ctx, userID, sessionID)
+		require.NoError(t, err)
+		require.Len(t, session.Groups, 1)
+	})
+
+	t.Run("session detail of someone else's session", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.GetSession(ctx, userID, sessionID)
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestSessionService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*SessionService, *mocks.MockSessionRepository) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockSessionRepository(ctrl)
+		return NewSessionService(repo, mocks.NewMockExerciseRepository(ctrl)), repo
+	}
+
+	t.Run("logged sessions are completed", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *models.Session) (*models.Session, error) {
+			return s, nil
+		})
+
+		session, err := svc.CreateSession(ctx, userID, nil, nil, nil, "")
+		require.NoError(t, err)
+		require.Equal(t, models.SessionCompleted, session.Status)
+		require.Nil(t, session.StartedAt)
+	})
+
+	t.Run("sessions created in progress start now", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *models.Session) (*models.Session, error) {
+			return s, nil
+		})
+		yesterday := time.Now().Add(-24 * time.Hour)
+
+		session, err := svc.CreateSession(ctx, userID, &yesterday, nil, nil, models.SessionInProgress)
+		require.NoError(t, err)
+		require.Equal(t, models.SessionInProgress, session.Status)
+		require.NotNil(t, session.StartedAt)
+		require.Equal(t, *session.StartedAt, session.PerformedAt)
+	})
+
+	t.Run("sessions cannot be created abandoned", func(t *testing.T) {
+		svc, _ := newService(t)
+		_, err := svc.CreateSession(ctx, userID, nil, nil, nil, models.SessionAbandoned)
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("starts a planned session", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		repo.EXPECT().StartSession(ctx, userID, sessionID, gomock.Any()).Return(&models.Session{ID: sessionID, Status: models.SessionInProgress}, nil)
+
+		session, err := svc.StartSession(ctx, userID, sessionID)
+		require.NoError(t, err)
+		require.Equal(t, models.SessionInProgress, session.Status)
+	})
+
+	t.Run("starting a session that is not planned", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		repo.EXPECT().StartSession(ctx, userID, sessionID, gomock.Any()).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.StartSession(ctx, userID, sessionID)
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("starting someone else's session", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(false, nil)
+
+		_, err := svc.StartSession(ctx, userID, sessionID)
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("abandons a session in progress", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		repo.EXPECT().FinishSession(ctx, userID, sessionID, models.SessionAbandoned, gomock.Any()).
+			Return(&models.Session{ID: sessionID, Status: models.SessionAbandoned}, nil)
+
+		session, err := svc.FinishSession(ctx, userID, sessionID, models.SessionAbandoned)
+		require.NoError(t, err)
+		require.Equal(t, models.SessionAbandoned, session.Status)
+	})
+
+	t.Run("finishing a session that is not in progress", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().SessionBelongsToUser(ctx, sessionID, userID).Return(true, nil)
+		repo.EXPECT().FinishSession(ctx, userID, sessionID, models.SessionCompleted, gomock.Any()).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.FinishSession(ctx, userID, sessionID, models.SessionCompleted)
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("finishing as planned is not a finish", func(t *testing.T) {
+		svc, _ := newService(t)
+		_, err := svc.FinishSession(ctx, userID, sessionID, models.SessionPlanned)
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("closes sessions in progress for too long", func(t *testing.T) {
+		svc, repo := newService(t)
+		repo.EXPECT().CloseStaleSessions(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, cutoff time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now().Add(-staleSessionAge), cutoff, time.Minute)
+			return 2, nil
+		})
+
+		n, err := svc.CloseStaleSessions(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+}
+
+func TestSessionService_Rest(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID, exerciseID := uuid.New(), uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*SessionService, *mocks.MockSessionRepository, *mocks.MockExerciseRepository) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockSessionRepository(ctrl)
+		exercises := mocks.NewMockExerciseRepository(ctrl)
+		return NewSessionService(repo, exercises), repo, exercises
+	}
+
+	t.Run("sets without a completion time complete now", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		repo.EXPECT().GetStatus(ctx, userID, sessionID).Return(models.SessionInProgress, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).
+			Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID, Measurement: models.MeasurementReps}}, nil)
+		repo.EXPECT().AddSet(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, set *models.Set) (*models.Set, error) {
+			return set, nil
+		})
+
+		set, err := svc.AddSet(ctx, userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Reps: 5})
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), set.CompletedAt, time.Minute)
+	})
+
+	t.Run("sets cannot be completed in the future", func(t *testing.T) {
+		svc, _, _ := newService(t)
+		_, err := svc.AddSet(ctx, userID, &models.Set{SessionID: sessionID, ExerciseID: exerciseID, Reps: 5, CompletedAt: time.Now().Add(time.Hour)})
+		require.ErrorIs(t, err, ErrInvalidSet)
+	})
+
+	t.Run("actual rest runs from the end of one set to the start of the next", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+		hold := 60
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{ID: sessionID, Sets: []models.Set{
+			{SetIndex: 0, CompletedAt: start},
+			{SetIndex: 1, CompletedAt: start.Add(4 * time.Minute), DurationSeconds: &hold},
+			// logged out of order: completed between the first two
+			{SetIndex: 2, CompletedAt: start.Add(90 * time.Second)},
+		}}, nil)
+
+		session, err := svc.GetSession(ctx, userID, sessionID)
+		require.NoError(t, err)
+		require.Nil(t, session.Sets[0].ActualRestSeconds)
+		require.Equal(t, 90, *session.Sets[2].ActualRestSeconds)
+		require.Equal(t, 90, *session.Sets[1].ActualRestSeconds, "the hold started a minute before it was completed")
+	})
+}
+
+func TestSessionService_AddPlannedSet(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID, exerciseID, groupID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*SessionService, *mocks.MockSessionRepository, *mocks.MockExerciseRepository) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockSessionRepository(ctrl)
+		exercises := mocks.NewMockExerciseRepository(ctrl)
+		return NewSessionService(repo, exercises), repo, exercises
+	}
+
+	t.Run("plans a working set in a grouped round", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{ID: sessionID, Status: models.SessionPlanned}, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID}}, nil)
+		repo.EXPECT().GetGroup(ctx, sessionID, groupID).Return(&models.SetGroup{ID: groupID}, nil)
+		repo.EXPECT().AddPlannedSet(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, p *models.PlannedSet) (*models.PlannedSet, error) {
+			return p, nil
+		})
+
+		planned, err := svc.AddPlannedSet(ctx, userID, &models.PlannedSet{SessionID: sessionID, ExerciseID: exerciseID, Reps: 8, WeightKG: 60, GroupID: &groupID})
+		require.NoError(t, err)
+		require.Equal(t, models.SetKindWorking, planned.Kind)
+		require.Equal(t, 1, *planned.Round)
+	})
+
+	t.Run("planned sets are checked like logged ones", func(t *testing.T) {
+		svc, repo, exercises := newService(t)
+		hold := 30
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{ID: sessionID, Status: models.SessionInProgress}, nil)
+		exercises.EXPECT().FindByIDs(ctx, []uuid.UUID{exerciseID}).Return(map[uuid.UUID]models.Exercise{exerciseID: {ID: exerciseID}}, nil)
+
+		_, err := svc.AddPlannedSet(ctx, userID, &models.PlannedSet{SessionID: sessionID, ExerciseID: exerciseID, DurationSeconds: &hold})
+		require.ErrorIs(t, err, ErrInvalidSet)
+	})
+
+	t.Run("finished sessions cannot be planned", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{ID: sessionID, Status: models.SessionCompleted}, nil)
+
+		_, err := svc.AddPlannedSet(ctx, userID, &models.PlannedSet{SessionID: sessionID, ExerciseID: exerciseID, Reps: 8})
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("someone else's session", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		repo.EXPECT().GetWithSets(ctx, userID, sessionID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.AddPlannedSet(ctx, userID, &models.PlannedSet{SessionID: sessionID, ExerciseID: exerciseID, Reps: 8})
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestSessionService_LastAndNext(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	newService := func(t *testing.T) (*SessionService, *mocks.MockSessionRepository) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockSessionRepository(ctrl)
+		return NewSessionService(repo, mocks.NewMockExerciseRepository(ctrl)), repo
+	}
+
+	t.Run("last session with its sets", func(t *testing.T) {
+		service, repo := newService(t)
+		last := &models.Session{ID: uuid.New(), UserID: userID, Status: models.SessionCompleted}
+		repo.EXPECT().GetLastSession(ctx, userID).Return(last, nil)
+		repo.EXPECT().GetWithSets(ctx, userID, last.ID).Return(&models.Session{ID: last.ID, Sets: []models.Set{{Reps: 5}}}, nil)
+
+		got, err := service.LastSession(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, last.ID, got.ID)
+		require.Len(t, got.Sets, 1)
+	})
+
+	t.Run("no last session", func(t *testing.T) {
+		service, repo := newService(t)
+		repo.EXPECT().GetLastSession(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		got, err := service.LastSession(ctx, userID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("next planned session is the soonest", func(t *testing.T) {
+		service, repo := newService(t)
+		soonest := &models.Session{ID: uuid.New(), Status: models.SessionPlanned}
+		repo.EXPECT().ListUpcomingPlanned(ctx, userID, gomock.Any()).Return([]*models.Session{soonest, {ID: uuid.New()}}, nil)
+
+		got, err := service.NextPlannedSession(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, soonest, got)
+	})
+
+	t.Run("nothing planned", func(t *testing.T) {
+		service, repo := newService(t)
+		repo.EXPECT().ListUpcomingPlanned(ctx, userID, gomock.Any()).Return(nil, nil)
+
+		got, err := service.NextPlannedSession(ctx, userID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/oidc"
+	t "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// loginStateTTL bounds how long a user may take at the provider before the callback is rejected.
+const loginStateTTL = 10 * time.Minute
+
+type IdentityRepository interface {
+	FindUser(ctx context.Context, provider, subject string) (*models.User, error)
+	Link(ctx context.Context, userID uuid.UUID, provider, subject, email string) error
+	CreateUser(ctx context.Context, email, passwordHash string, emailVerified bool, provider, subject string) (*models.User, error)
+	SaveLoginState(ctx context.Context, stateHash, provider, codeVerifier, nonce string, expiresAt time.Time) error
+	ConsumeLoginState(ctx context.Context, stateHash, provider string) (string, string, error)
+}
+
+// IdentityProvider is an external OpenID provider; *oidc.Provider implements it.
+type IdentityProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Identity, error)
+}
+
+var (
+	ErrUnknownProvider    = errors.New("unknown identity provider")
+	ErrInvalidLoginState  = errors.New("invalid or expired login state")
+	ErrIdentityProvider   = errors.New("identity provider login failed")
+	ErrIdentityNoEmail    = errors.New("identity provider did not return an email")
+	ErrIdentityConflict   = errors.New("email belongs to an account that cannot be linked automatically")
+	ErrCreateLoginRequest = errors.New("failed to create login request")
+)
+
+// SocialLoginService signs users in through external OpenID providers and issues the same tokens as Login.
+type SocialLoginService struct {
+	users      UserRepository
+	identities IdentityRepository
+	providers  map[string]IdentityProvider
+	jwtSecret  string
+}
+
+func NewSocialLoginService(users UserRepository, identities IdentityRepository, providers map[string]IdentityProvider, jwtSecret string) *SocialLoginService {
+	return &SocialLoginService{
+		users:      users,
+		identities: identities,
+		providers:  providers,
+		jwtSecret:  jwtSecret,
+	}
+}
+
+// Providers returns the configured provider names in alphabetical order.
+func (s *SocialLoginService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartLogin begins an authorization code flow and returns the provider URL to send the user to.
+// The PKCE verifier and nonce stay server side, keyed by the hashed state.
+func (s *SocialLoginService) StartLogin(ctx context.Context, provider string) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	state, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCreateLoginRequest, err)
+	}
+	nonce, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCreateLoginRequest, err)
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCreateLoginRequest, err)
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, oidc.S256Challenge(verifier))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIdentityProvider, err)
+	}
+	if err := s.identities.SaveLoginState(ctx, t.HashOpaqueToken(state), provider, verifier, nonce, time.Now().Add(loginStateTTL)); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCreateLoginRequest, err)
+	}
+	return authURL, nil
+}
+
+// CompleteLogin handles the provider callback. It signs in the user linked to the identity, links the
+// identity to an existing account when both sides have verified the email, or creates a new account.
+// Like Login it returns a challenge token with ErrMFARequired when the account has two-factor auth on.
+func (s *SocialLoginService) CompleteLogin(ctx context.Context, provider, state, code string) (*models.User, string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, "", ErrUnknownProvider
+	}
+
+	verifier, nonce, err := s.identities.ConsumeLoginState(ctx, t.HashOpaqueToken(state), provider)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrInvalidLoginState
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+
+	identity, err := p.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrIdentityProvider, err)
+	}
+
+	user, err := s.identities.FindUser(ctx, provider, identity.Subject)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		user, err = s.resolveUser(ctx, provider, identity)
+		if err != nil {
+			return nil, "", err
+		}
+	case err != nil:
+		return nil, "", fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return nil, "", ErrAccountLocked
+	}
+	if user.TOTPEnabledAt != nil {
+		challenge, err := t.GenerateMFAChallenge(user.ID, s.jwtSecret)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
+		}
+		return user, challenge, ErrMFARequired
+	}
+
+	if err := restoreAccount(ctx, s.users, user); err != nil {
+		return nil, "", err
+	}
+	token, err := t.GenerateVersionedToken(user.ID, user.TokenVersion, s.jwtSecret)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrGenerateToken, err)
+	}
+	return user, token, nil
+}
+
+// resolveUser links a first-time identity to an account with the same email or creates a new account.
+// Linking requires a verified email on both sides, otherwise whoever registered the address first
+// (possibly an attacker pre-registering a victim's email) would gain the provider login.
+func (s *SocialLoginService) resolveUser(ctx context.Context, provider string, identity *oidc.Identity) (*models.User, error) {
+	if identity.Email == "" {
+		return nil, ErrIdentityNoEmail
+	}
+
+	existing, err := s.users.FindByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if !identity.EmailVerified || existing.EmailVerifiedAt == nil {
+			return nil, ErrIdentityConflict
+		}
+		if err := s.identities.Link(ctx, existing.ID, provider, identity.Subject, identity.Email); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCreateUser, err)
+		}
+		return existing, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("%w: %v", ErrFindUser, err)
+	}
+
+	// Provider-only accounts get a random password nobody knows; a password can be set via reset.
+	random, err := t.GenerateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHashPassword, err)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHashPassword, err)
+	}
+	user, err := s.identities.CreateUser(ctx, identity.Email, string(hashed), identity.EmailVerified, provider, identity.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCreateUser, err)
+	}
+	return user, nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/oidc"
+	"github.com/alexanderramin/kalistheniks/internal/oidc/oidctest"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	tok "github.com/alexanderramin/kalistheniks/internal/token"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=social.go -destination=./mocks/social_mock.go -package=mocks
+
+type socialDeps struct {
+	svc        *SocialLoginService
+	usersRepo  *mocks.MockUserRepository
+	identities *mocks.MockIdentityRepository
+	provider   *mocks.MockIdentityProvider
+}
+
+func newSocialDeps(t *testing.T) socialDeps {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	usersRepo := mocks.NewMockUserRepository(ctrl)
+	identities := mocks.NewMockIdentityRepository(ctrl)
+	provider := mocks.NewMockIdentityProvider(ctrl)
+	return socialDeps{
+		svc:        NewSocialLoginService(usersRepo, identities, map[string]IdentityProvider{"test": provider}, "testsecret"),
+		usersRepo:  usersRepo,
+		identities: identities,
+		provider:   provider,
+	}
+}
+
+func TestSocialLoginService_StartLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores hashed state with verifier and nonce", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		var sentState, sentNonce, sentChallenge string
+		deps.provider.EXPECT().AuthCodeURL(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, state, nonce, challenge string) (string, error) {
+				sentState, sentNonce, sentChallenge = state, nonce, challenge
+				return "https://idp.example.com/authorize?state=" + state, nil
+			})
+		deps.identities.EXPECT().SaveLoginState(ctx, gomock.Any(), "test", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, stateHash, _, verifier, nonce string, expiresAt time.Time) error {
+				require.Equal(t, tok.HashOpaqueToken(sentState), stateHash)
+				require.Equal(t, sentChallenge, oidc.S256Challenge(verifier))
+				require.Equal(t, sentNonce, nonce)
+				require.WithinDuration(t, time.Now().Add(loginStateTTL), expiresAt, time.Minute)
+				return nil
+			})
+
+		authURL, err := deps.svc.StartLogin(ctx, "test")
+		require.NoError(t, err)
+		require.Contains(t, authURL, sentState)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		_, err := deps.svc.StartLogin(ctx, "other")
+		require.ErrorIs(t, err, ErrUnknownProvider)
+	})
+}
+
+func TestSocialLoginService_CompleteLogin(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	verifiedAt := time.Now()
+	stateHash := tok.HashOpaqueToken("state")
+
+	expectExchange := func(deps socialDeps, identity *oidc.Identity) {
+		deps.identities.EXPECT().ConsumeLoginState(ctx, stateHash, "test").Return("verifier", "nonce", nil)
+		deps.provider.EXPECT().Exchange(ctx, "code", "verifier", "nonce").Return(identity, nil)
+	}
+
+	t.Run("linked identity signs in", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		expectExchange(deps, &oidc.Identity{Subject: "sub", Email: "a@example.com", EmailVerified: true})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(&models.User{ID: userID}, nil)
+
+		user, token, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.NoError(t, err)
+		require.Equal(t, userID, user.ID)
+		subject, err := tok.ParseToken(token, "testsecret")
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), subject)
+	})
+
+	t.Run("new identity creates account", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		expectExchange(deps, &oidc.Identity{Subject: "sub", Email: "a@example.com", EmailVerified: true})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(nil, sql.ErrNoRows)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "a@example.com").Return(nil, sql.ErrNoRows)
+		deps.identities.EXPECT().CreateUser(ctx, "a@example.com", gomock.Any(), true, "test", "sub").Return(&models.User{ID: userID}, nil)
+
+		_, token, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+	})
+
+	t.Run("links when both sides verified the email", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		expectExchange(deps, &oidc.Identity{Subject: "sub", Email: "a@example.com", EmailVerified: true})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(nil, sql.ErrNoRows)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "a@example.com").Return(&models.User{ID: userID, EmailVerifiedAt: &verifiedAt}, nil)
+		deps.identities.EXPECT().Link(ctx, userID, "test", "sub", "a@example.com").Return(nil)
+
+		user, _, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.NoError(t, err)
+		require.Equal(t, userID, user.ID)
+	})
+
+	t.Run("refuses to link unverified local account", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		expectExchange(deps, &oidc.Identity{Subject: "sub", Email: "a@example.com", EmailVerified: true})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(nil, sql.ErrNoRows)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "a@example.com").Return(&models.User{ID: userID}, nil)
+
+		_, _, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.ErrorIs(t, err, ErrIdentityConflict)
+	})
+
+	t.Run("refuses to link email the provider did not verify", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		expectExchange(deps, &oidc.Identity{Subject: "sub", Email: "a@example.com"})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(nil, sql.ErrNoRows)
+		deps.usersRepo.EXPECT().FindByEmail(ctx, "a@example.com").Return(&models.User{ID: userID, EmailVerifiedAt: &verifiedAt}, nil)
+
+		_, _, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.ErrorIs(t, err, ErrIdentityConflict)
+	})
+
+	t.Run("identity without email", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		expectExchange(deps, &oidc.Identity{Subject: "sub"})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(nil, sql.ErrNoRows)
+
+		_, _, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.ErrorIs(t, err, ErrIdentityNoEmail)
+	})
+
+	t.Run("unknown or reused state", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		deps.identities.EXPECT().ConsumeLoginState(ctx, stateHash, "test").Return("", "", sql.ErrNoRows)
+
+		_, _, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.ErrorIs(t, err, ErrInvalidLoginState)
+	})
+
+	t.Run("two-factor account gets challenge", func(t *testing.T) {
+		deps := newSocialDeps(t)
+		enabledAt := time.Now()
+		expectExchange(deps, &oidc.Identity{Subject: "sub"})
+		deps.identities.EXPECT().FindUser(ctx, "test", "sub").Return(&models.User{ID: userID, TOTPEnabledAt: &enabledAt}, nil)
+
+		_, challenge, err := deps.svc.CompleteLogin(ctx, "test", "state", "code")
+		require.ErrorIs(t, err, ErrMFARequired)
+		_, err = tok.ParseMFAChallenge(challenge, "testsecret")
+		require.NoError(t, err)
+	})
+}
+
+func TestSocialLoginService_MockProvider(t *testing.T) {
+	ctx := context.Background()
+	idp := oidctest.NewServer("kalistheniks", "client-secret")
+	defer idp.Close()
+	idp.SetUser(oidctest.User{Subject: "idp-user", Email: "social@example.com", EmailVerified: true})
+
+	ctrl := gomock.NewController(t)
+	usersRepo := mocks.NewMockUserRepository(ctrl)
+	identities := mocks.NewMockIdentityRepository(ctrl)
+	provider := oidc.NewProvider(idp.Config("http://localhost:8080/auth/oidc/mock/callback"), nil)
+	svc := NewSocialLoginService(usersRepo, identities, map[string]IdentityProvider{"mock": provider}, "testsecret")
+
+	var savedHash, savedVerifier, savedNonce string
+	identities.EXPECT().SaveLoginState(ctx, gomock.Any(), "mock", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, stateHash, _, verifier, nonce string, _ time.Time) error {
+			savedHash, savedVerifier, savedNonce = stateHash, verifier, nonce
+			return nil
+		})
+	authURL, err := svc.StartLogin(ctx, "mock")
+	require.NoError(t, err)
+
+	code, state, err := idp.Authorize(authURL)
+	require.NoError(t, err)
+	require.Equal(t, savedHash, tok.HashOpaqueToken(state))
+
+	userID := uuid.New()
+	identities.EXPECT().ConsumeLoginState(ctx, savedHash, "mock").Return(savedVerifier, savedNonce, nil)
+	identities.EXPECT().FindUser(ctx, "mock", "idp-user").Return(nil, sql.ErrNoRows)
+	usersRepo.EXPECT().FindByEmail(ctx, "social@example.com").Return(nil, sql.ErrNoRows)
+	identities.EXPECT().CreateUser(ctx, "social@example.com", gomock.Any(), true, "mock", "idp-user").
+		Return(&models.User{ID: userID, Email: "social@example.com"}, nil)
+
+	user, token, err := svc.CompleteLogin(ctx, "mock", state, code)
+	require.NoError(t, err)
+	require.Equal(t, userID, user.ID)
+	subject, err := tok.ParseToken(token, "testsecret")
+	require.NoError(t, err)
+	require.Equal(t, userID.String(), subject)
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type StatsSessionRepository interface {
+	ListCompleted(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+	ListTrainedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error)
+}
+
+// StatsService summarizes a user's training history.
+type StatsService struct {
+	sessions   StatsSessionRepository
+	profiles   ProfileRepository
+	exercises  ExerciseRepository
+	bodyweight BodyweightRepository
+}
+
+func NewStatsService(sessions StatsSessionRepository, profiles ProfileRepository, exercises ExerciseRepository, bodyweight BodyweightRepository) *StatsService {
+	return &StatsService{sessions: sessions, profiles: profiles, exercises: exercises, bodyweight: bodyweight}
+}
+
+// Consistency reports the user's weekly training streaks over their whole history, and how often, how
+// long and on which days they trained over the last weeks, this one included.
+func (s *StatsService) Consistency(ctx context.Context, userID uuid.UUID, weeks int) (*models.ConsistencyStats, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessions.ListCompleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(profile)
+	current := weekStart(time.Now().In(loc))
+	first := current.AddDate(0, 0, -7*(weeks-1))
+
+	stats := &models.ConsistencyStats{
+		TotalSessions:     len(sessions),
+		Weeks:             make([]models.WeeklySessions, weeks),
+		SessionsByWeekday: make(map[time.Weekday]int, 7),
+	}
+	for i := range stats.Weeks {
+		stats.Weeks[i].WeekStart = first.AddDate(0, 0, 7*i)
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		stats.SessionsByWeekday[d] = 0
+	}
+
+	// Week starts come from weekStart in one location and carry no monotonic reading, so they are safe
+	// map keys.
+	trained := make(map[time.Time]bool)
+	var timed int
+	var totalDuration time.Duration
+	for _, session := range sessions {
+		local := session.PerformedAt.In(loc)
+		week := weekStart(local)
+		trained[week] = true
+
+		i := int(week.Sub(first).Hours()/24+0.5) / 7
+		if week.Before(first) || i >= weeks {
+			continue
+		}
+		stats.Weeks[i].Sessions++
+		stats.SessionsByWeekday[local.Weekday()]++
+		if session.StartedAt != nil && session.FinishedAt != nil && session.FinishedAt.After(*session.StartedAt) {
+			timed++
+			totalDuration += session.FinishedAt.Sub(*session.StartedAt)
+		}
+	}
+
+	inWindow := 0
+	for _, w := range stats.Weeks {
+		inWindow += w.Sessions
+	}
+	stats.SessionsPerWeek = math.Round(float64(inWindow)/float64(weeks)*100) / 100
+	if timed > 0 {
+		avg := int((totalDuration / time.Duration(timed)).Seconds())
+		stats.AverageDurationSeconds = &avg
+	}
+	stats.CurrentStreakWeeks, stats.LongestStreakWeeks = streaks(trained, current)
+	return stats, nil
+}
+
+// streaks returns the current and longest runs of consecutive weeks with training, given the start of
+// every week trained and of the current week. The current week does not break a streak before it ends.
+func streaks(trained map[time.Time]bool, current time.Time) (currentStreak, longest int) {
+	week := current
+	if !trained[week] {
+		week = week.AddDate(0, 0, -7)
+	}
+	for trained[week] {
+		currentStreak++
+		week = week.AddDate(0, 0, -7)
+	}
+
+	for week := range trained {
+		if trained[week.AddDate(0, 0, -7)] {
+			continue // not the first week of its run
+		}
+		run := 0
+		for w := week; trained[w]; w = w.AddDate(0, 0, 7) {
+			run++
+		}
+		longest = max(longest, run)
+	}
+	return currentStreak, longest
+}
+
+// WeeklyVolume adds up the user's working sets for each of the last weeks, oldest first and including the
+// current week. Weeks start on Monday in the user's time zone. Loads include the exercise's share of the
+// bodyweight logged closest to each session.
+func (s *StatsService) WeeklyVolume(ctx context.Context, userID uuid.UUID, weeks int) ([]models.WeeklyVolume, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(profile)
+	first := weekStart(time.Now().In(loc)).AddDate(0, 0, -7*(weeks-1))
+	sessions, err := s.sessions.ListTrainedSince(ctx, userID, first)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]models.WeeklyVolume, weeks)
+	for i := range out {
+		out[i].WeekStart = first.AddDate(0, 0, 7*i)
+	}
+	var ids []uuid.UUID
+	var inWindow []*models.Session
+	for _, session := range sessions {
+		week := weekStart(session.PerformedAt.In(loc))
+		if week.Before(first) || len(session.Sets) == 0 {
+			continue
+		}
+		inWindow = append(inWindow, session)
+		for _, set := range session.Sets {
+			ids = append(ids, set.ExerciseID)
+		}
+	}
+	if len(inWindow) == 0 {
+		return out, nil
+	}
+	exercises, err := s.exercises.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.bodyweight.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range inWindow {
+		i := int(weekStart(session.PerformedAt.In(loc)).Sub(first).Hours()/24+0.5) / 7
+		if i >= weeks {
+			continue
+		}
+		bodyweight := nearestBodyweight(entries, session.PerformedAt, profile.BodyweightKG)
+		counted := false
+		for _, set := range session.Sets {
+			if set.Kind != models.SetKindWorking {
+				continue
+			}
+			load := setLoad(set, exercises[set.ExerciseID].BodyweightFraction, bodyweight).EffectiveKG
+			out[i].Sets++
+			out[i].Reps += set.Reps
+			out[i].VolumeKG = round2(out[i].VolumeKG + load*float64(set.Reps))
+			counted = true
+		}
+		if counted {
+			out[i].Sessions++
+		}
+	}
+	return out, nil
+}
+
+// RecentRecords returns the personal records the user set from since on, newest first: working sets that
+// beat the heaviest earlier set of their exercise, or matched its weight with more reps. An exercise's
+// first set is not a record, and only its latest record is reported.
+func (s *StatsService) RecentRecords(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.PersonalRecord, error) {
+	// Records are judged against the whole history, oldest first.
+	sessions, err := s.sessions.ListTrainedSince(ctx, userID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	best := make(map[uuid.UUID]models.Set)
+	recent := make(map[uuid.UUID]models.PersonalRecord)
+	for _, session := range sessions {
+		for _, set := range session.Sets {
+			if set.Kind != models.SetKindWorking {
+				continue
+			}
+			current, ok := best[set.ExerciseID]
+			if ok && (set.WeightKG < current.WeightKG || set.WeightKG == current.WeightKG && set.Reps <= current.Reps) {
+				continue
+			}
+			best[set.ExerciseID] = set
+			if ok && !session.PerformedAt.Before(since) {
+				recent[set.ExerciseID] = models.PersonalRecord{
+					ExerciseID:  set.ExerciseID,
+					WeightKG:    set.WeightKG,
+					Reps:        set.Reps,
+					SessionID:   session.ID,
+					PerformedAt: session.PerformedAt,
+				}
+			}
+		}
+	}
+
+	records := make([]models.PersonalRecord, 0, len(recent))
+	for _, record := range recent {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].PerformedAt.Equal(records[j].PerformedAt) {
+			return records[i].PerformedAt.After(records[j].PerformedAt)
+		}
+		return records[i].ExerciseID.String() < records[j].ExerciseID.String()
+	})
+	return records, nil
+}
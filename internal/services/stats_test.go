@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=stats.go -destination=./mocks/stats_mock.go -package=mocks
+func TestStatsService_Consistency(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	newService := func(t *testing.T, timeZone string) (*StatsService, *mocks.MockStatsSessionRepository) {
+		ctrl := gomock.NewController(t)
+		sessions := mocks.NewMockStatsSessionRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: timeZone}, nil)
+		return NewStatsService(sessions, profiles, mocks.NewMockExerciseRepository(ctrl), mocks.NewMockBodyweightRepository(ctrl)), sessions
+	}
+	completed := func(at time.Time) *models.Session {
+		return &models.Session{ID: uuid.New(), Status: models.SessionCompleted, PerformedAt: at}
+	}
+	thisWeek := weekStart(time.Now().UTC())
+
+	t.Run("streaks, weekly counts and durations", func(t *testing.T) {
+		svc, sessions := newService(t, "UTC")
+		var history []*models.Session
+		// an older run of four weeks
+		for w := 12; w >= 9; w-- {
+			history = append(history, completed(thisWeek.AddDate(0, 0, -7*w).Add(10*time.Hour)))
+		}
+		// the current run: two weeks ago, last week twice and this week
+		started := thisWeek.AddDate(0, 0, -6).Add(18 * time.Hour) // last Tuesday
+		finished := started.Add(50 * time.Minute)
+		timed := completed(started)
+		timed.StartedAt, timed.FinishedAt = &started, &finished
+		history = append(history,
+			completed(thisWeek.AddDate(0, 0, -14).Add(10*time.Hour)),
+			completed(thisWeek.AddDate(0, 0, -7).Add(10*time.Hour)),
+			timed,
+			completed(thisWeek.Add(10*time.Hour)),
+		)
+		sessions.EXPECT().ListCompleted(ctx, userID).Return(history, nil)
+
+		stats, err := svc.Consistency(ctx, userID, 4)
+		require.NoError(t, err)
+		require.Equal(t, 3, stats.CurrentStreakWeeks)
+		require.Equal(t, 4, stats.LongestStreakWeeks)
+		require.Equal(t, 8, stats.TotalSessions)
+		require.Len(t, stats.Weeks, 4)
+		require.Equal(t, thisWeek.AddDate(0, 0, -21), stats.Weeks[0].WeekStart)
+		require.Equal(t, []int{0, 1, 2, 1}, []int{stats.Weeks[0].Sessions, stats.Weeks[1].Sessions, stats.Weeks[2].Sessions, stats.Weeks[3].Sessions})
+		require.Equal(t, 1.0, stats.SessionsPerWeek)
+		require.Equal(t, 3000, *stats.AverageDurationSeconds, "only timed sessions count")
+		require.Equal(t, 3, stats.SessionsByWeekday[time.Monday])
+		require.Equal(t, 1, stats.SessionsByWeekday[time.Tuesday])
+		require.Contains(t, stats.SessionsByWeekday, time.Sunday)
+	})
+
+	t.Run("this week does not break the streak before it ends", func(t *testing.T) {
+		svc, sessions := newService(t, "UTC")
+		sessions.EXPECT().ListCompleted(ctx, userID).Return([]*models.Session{
+			completed(thisWeek.AddDate(0, 0, -14).Add(10 * time.Hour)),
+			completed(thisWeek.AddDate(0, 0, -7).Add(10 * time.Hour)),
+		}, nil)
+
+		stats, err := svc.Consistency(ctx, userID, 8)
+		require.NoError(t, err)
+		require.Equal(t, 2, stats.CurrentStreakWeeks)
+		require.Equal(t, 2, stats.LongestStreakWeeks)
+		require.Nil(t, stats.AverageDurationSeconds)
+	})
+
+	t.Run("a missed week ends the streak", func(t *testing.T) {
+		svc, sessions := newService(t, "UTC")
+		sessions.EXPECT().ListCompleted(ctx, userID).Return([]*models.Session{
+			completed(thisWeek.AddDate(0, 0, -14).Add(10 * time.Hour)),
+		}, nil)
+
+		stats, err := svc.Consistency(ctx, userID, 8)
+		require.NoError(t, err)
+		require.Equal(t, 0, stats.CurrentStreakWeeks)
+		require.Equal(t, 1, stats.LongestStreakWeeks)
+	})
+
+	t.Run("days and weeks follow the user's time zone", func(t *testing.T) {
+		svc, sessions := newService(t, "America/New_York")
+		// 02:00 UTC on a Monday is still Sunday evening in New York, the previous week there
+		sessions.EXPECT().ListCompleted(ctx, userID).Return([]*models.Session{
+			completed(thisWeek.AddDate(0, 0, -7).Add(2 * time.Hour)),
+		}, nil)
+
+		stats, err := svc.Consistency(ctx, userID, 4)
+		require.NoError(t, err)
+		require.Equal(t, 1, stats.SessionsByWeekday[time.Sunday])
+		require.Equal(t, 0, stats.SessionsByWeekday[time.Monday])
+		require.Equal(t, 1, stats.Weeks[1].Sessions)
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		svc, sessions := newService(t, "UTC")
+		sessions.EXPECT().ListCompleted(ctx, userID).Return(nil, nil)
+
+		stats, err := svc.Consistency(ctx, userID, 8)
+		require.NoError(t, err)
+		require.Zero(t, stats.CurrentStreakWeeks)
+		require.Zero(t, stats.SessionsPerWeek)
+		require.Len(t, stats.SessionsByWeekday, 7)
+	})
+}
+
+func TestStatsService_WeeklyVolume(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	squatID, pullUpID := uuid.New(), uuid.New()
+	thisWeek := weekStart(time.Now().UTC())
+
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockStatsSessionRepository(ctrl)
+	profiles := mocks.NewMockProfileRepository(ctrl)
+	exercises := mocks.NewMockExerciseRepository(ctrl)
+	bodyweight := mocks.NewMockBodyweightRepository(ctrl)
+	svc := NewStatsService(sessions, profiles, exercises, bodyweight)
+
+	profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+	sessions.EXPECT().ListTrainedSince(ctx, userID, thisWeek.AddDate(0, 0, -7)).Return([]*models.Session{
+		{ID: uuid.New(), PerformedAt: thisWeek.Add(10 * time.Hour), Sets: []models.Set{
+			{ExerciseID: squatID, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 40},
+			{ExerciseID: squatID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+			{ExerciseID: pullUpID, Kind: models.SetKindWorking, Reps: 8},
+		}},
+		{ID: uuid.New(), PerformedAt: thisWeek.AddDate(0, 0, -3), Sets: []models.Set{
+			{ExerciseID: squatID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 90},
+		}},
+	}, nil)
+	exercises.EXPECT().FindByIDs(ctx, gomock.Any()).Return(map[uuid.UUID]models.Exercise{
+		squatID:  {ID: squatID, Name: "Squat"},
+		pullUpID: {ID: pullUpID, Name: "Pull-up", BodyweightFraction: 1},
+	}, nil)
+	bodyweight.EXPECT().List(ctx, userID).Return([]*models.BodyweightEntry{{WeightKG: 80, MeasuredAt: thisWeek}}, nil)
+
+	weeks, err := svc.WeeklyVolume(ctx, userID, 2)
+	require.NoError(t, err)
+	require.Len(t, weeks, 2)
+	require.Equal(t, models.WeeklyVolume{WeekStart: thisWeek.AddDate(0, 0, -7), Sessions: 1, Sets: 1, Reps: 5, VolumeKG: 450}, weeks[0])
+	require.Equal(t, models.WeeklyVolume{WeekStart: thisWeek, Sessions: 1, Sets: 2, Reps: 13, VolumeKG: 1140}, weeks[1],
+		"warm-ups are left out and pull-ups count the logged bodyweight")
+}
+
+func TestStatsService_RecentRecords(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	squatID, benchID, rowID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now().UTC()
+
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockStatsSessionRepository(ctrl)
+	svc := NewStatsService(sessions, mocks.NewMockProfileRepository(ctrl), mocks.NewMockExerciseRepository(ctrl), mocks.NewMockBodyweightRepository(ctrl))
+
+	recent := &models.Session{ID: uuid.New(), PerformedAt: now.AddDate(0, 0, -2), Sets: []models.Set{
+		{ExerciseID: squatID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 105},
+		{ExerciseID: benchID, Kind: models.SetKindWorking, Reps: 6, WeightKG: 80},
+		{ExerciseID: rowID, Kind: models.SetKindWorking, Reps: 8, WeightKG: 60},
+	}}
+	lastWeek := &models.Session{ID: uuid.New(), PerformedAt: now.AddDate(0, 0, -7), Sets: []models.Set{
+		{ExerciseID: squatID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 102.5},
+		{ExerciseID: benchID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 80},
+		{ExerciseID: benchID, Kind: models.SetKindAMRAP, Reps: 12, WeightKG: 85},
+	}}
+	longAgo := &models.Session{ID: uuid.New(), PerformedAt: now.AddDate(0, -3, 0), Sets: []models.Set{
+		{ExerciseID: squatID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100},
+		{ExerciseID: benchID, Kind: models.SetKindWorking, Reps: 5, WeightKG: 75},
+	}}
+	sessions.EXPECT().ListTrainedSince(ctx, userID, time.Time{}).Return([]*models.Session{longAgo, lastWeek, recent}, nil)
+
+	records, err := svc.RecentRecords(ctx, userID, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, records, 2, "a first set is no record")
+	require.Contains(t, records, models.PersonalRecord{ExerciseID: squatID, WeightKG: 105, Reps: 5, SessionID: recent.ID, PerformedAt: recent.PerformedAt})
+	require.Contains(t, records, models.PersonalRecord{ExerciseID: benchID, WeightKG: 80, Reps: 6, SessionID: recent.ID, PerformedAt: recent.PerformedAt},
+		"more reps at the same weight is a record, AMRAP sets are not")
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/google/uuid"
+)
+
+type TemplateRepository interface {
+	Create(ctx context.Context, t *models.SessionTemplate) (*models.SessionTemplate, error)
+	Get(ctx context.Context, userID, templateID uuid.UUID) (*models.SessionTemplate, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error)
+}
+
+// ProgressionSource supplies the next progression target per exercise; *plan.PlanService implements it.
+type ProgressionSource interface {
+	Targets(ctx context.Context, userID uuid.UUID, exerciseIDs []uuid.UUID) (map[uuid.UUID]*models.PlanSuggestion, error)
+}
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrEmptyTemplate    = errors.New("session has no sets to save")
+)
+
+// TemplateService saves sessions as reusable templates and plans new sessions from them.
+type TemplateService struct {
+	templates   TemplateRepository
+	sessions    SessionRepository
+	progression ProgressionSource
+}
+
+func NewTemplateService(templates TemplateRepository, sessions SessionRepository, progression ProgressionSource) *TemplateService {
+	return &TemplateService{templates: templates, sessions: sessions, progression: progression}
+}
+
+// SaveTemplate saves the structure of one of the user's sessions: its groups and its sets in the order they
+// were completed, with what each set achieved and the rest before it. Without a name the template is named
+// after the session type, or else the day the session was performed.
+func (s *TemplateService) SaveTemplate(ctx context.Context, userID, sessionID uuid.UUID, name string) (*models.SessionTemplate, error) {
+	session, err := s.sessions.GetWithSets(ctx, userID, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(session.Sets) == 0 {
+		return nil, ErrEmptyTemplate
+	}
+	if name == "" {
+		name = "Workout of " + session.PerformedAt.Format(time.DateOnly)
+		if session.SessionType != nil {
+			name = *session.SessionType
+		}
+	}
+
+	template := &models.SessionTemplate{
+		UserID:      userID,
+		Name:        name,
+		SessionType: session.SessionType,
+		Groups:      make([]models.TemplateGroup, len(session.Groups)),
+		Sets:        make([]models.TemplateSet, len(session.Sets)),
+	}
+	groupIndex := make(map[uuid.UUID]int, len(session.Groups))
+	for i, g := range session.Groups {
+		groupIndex[g.ID] = i
+		template.Groups[i] = models.TemplateGroup{GroupIndex: i, Kind: g.Kind, Rounds: g.Rounds, RestSeconds: g.RestSeconds}
+	}
+
+	fillActualRest(session.Sets)
+	sets := append([]models.Set{}, session.Sets...)
+	sort.SliceStable(sets, func(a, b int) bool { return sets[a].CompletedAt.Before(sets[b].CompletedAt) })
+	for i, set := range sets {
+		rest := set.RestSeconds
+		if rest == nil {
+			rest = set.ActualRestSeconds
+		}
+		ts := models.TemplateSet{
+			Position:        i,
+			ExerciseID:      set.ExerciseID,
+			Kind:            set.Kind,
+			Reps:            set.Reps,
+			WeightKG:        set.WeightKG,
+			DurationSeconds: set.DurationSeconds,
+			DistanceM:       set.DistanceM,
+			RestSeconds:     rest,
+			Round:           set.Round,
+		}
+		if set.GroupID != nil {
+			if index, ok := groupIndex[*set.GroupID]; ok {
+				ts.GroupIndex = &index
+			}
+		}
+		template.Sets[i] = ts
+	}
+	return s.templates.Create(ctx, template)
+}
+
+// ListTemplates returns the user's templates, newest first.
+func (s *TemplateService) ListTemplates(ctx context.Context, userID uuid.UUID) ([]*models.SessionTemplate, error) {
+	return s.templates.List(ctx, userID)
+}
+
+// CreateSessionFromTemplate creates a session laid out like one of the user's templates, planned unless
+// asked to start now. Working sets of exercises the user has trained take their latest progression target;
+// warm-ups and other kinds, and exercises without history, keep what the template recorded. The session
+// type comes from the template unless one is given.
+func (s *TemplateService) CreateSessionFromTemplate(ctx context.Context, userID, templateID uuid.UUID, performedAt *time.Time, sessionType, notes *string, status string) (*models.Session, error) {
+	switch status {
+	case "":
+		status = models.SessionPlanned
+	case models.SessionPlanned, models.SessionInProgress:
+	default:
+		return nil, ErrSessionState
+	}
+	template, err := s.templates.Get(ctx, userID, templateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sessionType == nil {
+		sessionType = template.SessionType
+	}
+	session, err := newSession(userID, performedAt, sessionType, notes, status)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs := make(map[int]uuid.UUID, len(template.Groups))
+	session.Groups = make([]models.SetGroup, len(template.Groups))
+	for i, g := range template.Groups {
+		groupIDs[g.GroupIndex] = uuid.New()
+		session.Groups[i] = models.SetGroup{
+			ID:          groupIDs[g.GroupIndex],
+			GroupIndex:  g.GroupIndex,
+			Kind:        g.Kind,
+			Rounds:      g.Rounds,
+			RestSeconds: g.RestSeconds,
+		}
+	}
+
+	var exerciseIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, set := range template.Sets {
+		if set.Kind == models.SetKindWorking && !seen[set.ExerciseID] {
+			seen[set.ExerciseID] = true
+			exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		}
+	}
+	targets := map[uuid.UUID]*models.PlanSuggestion{}
+	if len(exerciseIDs) > 0 {
+		targets, err = s.progression.Targets(ctx, userID, exerciseIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	session.PlannedSets = make([]models.PlannedSet, len(template.Sets))
+	for i, set := range template.Sets {
+		planned := models.PlannedSet{
+			Position:        set.Position,
+			ExerciseID:      set.ExerciseID,
+			Kind:            set.Kind,
+			Reps:            set.Reps,
+			WeightKG:        set.WeightKG,
+			DurationSeconds: set.DurationSeconds,
+			DistanceM:       set.DistanceM,
+			RestSeconds:     set.RestSeconds,
+			Round:           set.Round,
+		}
+		if set.GroupIndex != nil {
+			id := groupIDs[*set.GroupIndex]
+			planned.GroupID = &id
+		}
+		if target, ok := targets[set.ExerciseID]; ok && set.Kind == models.SetKindWorking {
+			planned.Reps = target.Reps
+			planned.WeightKG = target.WeightKG
+			planned.DurationSeconds = target.DurationSeconds
+			planned.DistanceM = target.DistanceM
+			if planned.RestSeconds == nil {
+				rest := target.RestSeconds
+				planned.RestSeconds = &rest
+			}
+		}
+		session.PlannedSets[i] = planned
+	}
+	return s.sessions.CreateWithPlan(ctx, session)
+}
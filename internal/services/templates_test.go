@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=templates.go -destination=./mocks/templates_mock.go -package=mocks
+func TestTemplateService_SaveTemplate(t *testing.T) {
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+	squat, pullUp, dip := uuid.New(), uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*TemplateService, *mocks.MockTemplateRepository, *mocks.MockSessionRepository) {
+		ctrl := gomock.NewController(t)
+		templates := mocks.NewMockTemplateRepository(ctrl)
+		sessions := mocks.NewMockSessionRepository(ctrl)
+		return NewTemplateService(templates, sessions, mocks.NewMockProgressionSource(ctrl)), templates, sessions
+	}
+
+	t.Run("saves sets in the order they were completed", func(t *testing.T) {
+		svc, templates, sessions := newService(t)
+		start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+		groupID := uuid.New()
+		planned := 150
+		round := 1
+		sessionType := "upper"
+		sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{
+			ID:          sessionID,
+			SessionType: &sessionType,
+			Groups:      []models.SetGroup{{ID: groupID, GroupIndex: 3, Kind: models.GroupSuperset}},
+			Sets: []models.Set{
+				{SetIndex: 0, ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100, CompletedAt: start, RestSeconds: &planned},
+				{SetIndex: 2, ExerciseID: dip, Kind: models.SetKindWorking, Reps: 10, CompletedAt: start.Add(5 * time.Minute), GroupID: &groupID, Round: &round},
+				{SetIndex: 1, ExerciseID: pullUp, Kind: models.SetKindWorking, Reps: 8, CompletedAt: start.Add(3 * time.Minute), GroupID: &groupID, Round: &round},
+			},
+		}, nil)
+		templates.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, tpl *models.SessionTemplate) (*models.SessionTemplate, error) {
+			return tpl, nil
+		})
+
+		tpl, err := svc.SaveTemplate(ctx, userID, sessionID, "")
+		require.NoError(t, err)
+		require.Equal(t, "upper", tpl.Name, "named after the session type")
+		require.Equal(t, []models.TemplateGroup{{GroupIndex: 0, Kind: models.GroupSuperset}}, tpl.Groups)
+		require.Len(t, tpl.Sets, 3)
+		require.Equal(t, squat, tpl.Sets[0].ExerciseID)
+		require.Equal(t, 150, *tpl.Sets[0].RestSeconds, "the rest the client timed")
+		require.Equal(t, pullUp, tpl.Sets[1].ExerciseID)
+		require.Equal(t, 180, *tpl.Sets[1].RestSeconds, "the rest actually taken")
+		require.Equal(t, 0, *tpl.Sets[1].GroupIndex)
+		require.Equal(t, dip, tpl.Sets[2].ExerciseID)
+		require.Equal(t, 2, tpl.Sets[2].Position)
+	})
+
+	t.Run("sessions without sets cannot be saved", func(t *testing.T) {
+		svc, _, sessions := newService(t)
+		sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(&models.Session{ID: sessionID}, nil)
+
+		_, err := svc.SaveTemplate(ctx, userID, sessionID, "Empty")
+		require.ErrorIs(t, err, ErrEmptyTemplate)
+	})
+
+	t.Run("someone else's session", func(t *testing.T) {
+		svc, _, sessions := newService(t)
+		sessions.EXPECT().GetWithSets(ctx, userID, sessionID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.SaveTemplate(ctx, userID, sessionID, "")
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+func TestTemplateService_CreateSessionFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	userID, templateID := uuid.New(), uuid.New()
+	squat, plank, fresh := uuid.New(), uuid.New(), uuid.New()
+
+	newService := func(t *testing.T) (*TemplateService, *mocks.MockTemplateRepository, *mocks.MockSessionRepository, *mocks.MockProgressionSource) {
+		ctrl := gomock.NewController(t)
+		templates := mocks.NewMockTemplateRepository(ctrl)
+		sessions := mocks.NewMockSessionRepository(ctrl)
+		progression := mocks.NewMockProgressionSource(ctrl)
+		return NewTemplateService(templates, sessions, progression), templates, sessions, progression
+	}
+
+	rest := 90
+	hold := 30
+	groupIndex := 0
+	round := 2
+	upper := "upper"
+	template := &models.SessionTemplate{
+		ID:          templateID,
+		SessionType: &upper,
+		Groups:      []models.TemplateGroup{{GroupIndex: 0, Kind: models.GroupCircuit}},
+		Sets: []models.TemplateSet{
+			{Position: 0, ExerciseID: squat, Kind: models.SetKindWarmup, Reps: 10, WeightKG: 20},
+			{Position: 1, ExerciseID: squat, Kind: models.SetKindWorking, Reps: 5, WeightKG: 100, RestSeconds: &rest},
+			{Position: 2, ExerciseID: plank, Kind: models.SetKindWorking, DurationSeconds: &hold, GroupIndex: &groupIndex, Round: &round},
+			{Position: 3, ExerciseID: fresh, Kind: models.SetKindWorking, Reps: 12},
+		},
+	}
+
+	t.Run("working sets take the latest targets", func(t *testing.T) {
+		svc, templates, sessions, progression := newService(t)
+		templates.EXPECT().Get(ctx, userID, templateID).Return(template, nil)
+		longer := 35
+		progression.EXPECT().Targets(ctx, userID, []uuid.UUID{squat, plank, fresh}).Return(map[uuid.UUID]*models.PlanSuggestion{
+			squat: {ExerciseID: squat, Reps: 5, WeightKG: 102.5, RestSeconds: 210},
+			plank: {ExerciseID: plank, DurationSeconds: &longer, RestSeconds: 60},
+		}, nil)
+		sessions.EXPECT().CreateWithPlan(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *models.Session) (*models.Session, error) {
+			return s, nil
+		})
+
+		session, err := svc.CreateSessionFromTemplate(ctx, userID, templateID, nil, nil, nil, "")
+		require.NoError(t, err)
+		require.Equal(t, models.SessionPlanned, session.Status)
+		require.Equal(t, "upper", *session.SessionType)
+		require.Len(t, session.Groups, 1)
+
+		planned := session.PlannedSets
+		require.Len(t, planned, 4)
+		require.Equal(t, 20.0, planned[0].WeightKG, "warm-ups keep the template's load")
+		require.Equal(t, 102.5, planned[1].WeightKG)
+		require.Equal(t, 90, *planned[1].RestSeconds, "the template's rest wins")
+		require.Equal(t, 35, *planned[2].DurationSeconds)
+		require.Equal(t, 60, *planned[2].RestSeconds, "the suggested rest fills in")
+		require.Equal(t, session.Groups[0].ID, *planned[2].GroupID)
+		require.Equal(t, 2, *planned[2].Round)
+		require.Equal(t, 12, planned[3].Reps, "no history keeps the template's reps")
+	})
+
+	t.Run("sessions can start straight away", func(t *testing.T) {
+		svc, templates, sessions, progression := newService(t)
+		templates.EXPECT().Get(ctx, userID, templateID).Return(template, nil)
+		progression.EXPECT().Targets(ctx, userID, gomock.Any()).Return(map[uuid.UUID]*models.PlanSuggestion{}, nil)
+		sessions.EXPECT().CreateWithPlan(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *models.Session) (*models.Session, error) {
+			return s, nil
+		})
+
+		session, err := svc.CreateSessionFromTemplate(ctx, userID, templateID, nil, nil, nil, models.SessionInProgress)
+		require.NoError(t, err)
+		require.Equal(t, models.SessionInProgress, session.Status)
+		require.NotNil(t, session.StartedAt)
+	})
+
+	t.Run("completed sessions cannot be planned", func(t *testing.T) {
+		svc, _, _, _ := newService(t)
+		_, err := svc.CreateSessionFromTemplate(ctx, userID, templateID, nil, nil, nil, models.SessionCompleted)
+		require.ErrorIs(t, err, ErrSessionState)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		svc, templates, _, _ := newService(t)
+		templates.EXPECT().Get(ctx, userID, templateID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.CreateSessionFromTemplate(ctx, userID, templateID, nil, nil, nil, "")
+		require.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+}
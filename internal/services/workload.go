@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/rules"
+	"github.com/google/uuid"
+)
+
+type WorkloadSessionRepository interface {
+	ListTrainedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.Session, error)
+}
+
+type ReadinessRepository interface {
+	Upsert(ctx context.Context, c *models.ReadinessCheckIn) (*models.ReadinessCheckIn, error)
+	GetForDay(ctx context.Context, userID uuid.UUID, day time.Time) (*models.ReadinessCheckIn, error)
+}
+
+var ErrInvalidCheckIn = errors.New("invalid readiness check-in")
+
+// minChronicHistoryDays is how far back training has to go before the chronic load means enough to
+// compare with. With less, every week looks like a spike.
+const minChronicHistoryDays = 21
+
+// WorkloadService tracks training load and readiness.
+type WorkloadService struct {
+	sessions  WorkloadSessionRepository
+	readiness ReadinessRepository
+	profiles  ProfileRepository
+}
+
+func NewWorkloadService(sessions WorkloadSessionRepository, readiness ReadinessRepository, profiles ProfileRepository) *WorkloadService {
+	return &WorkloadService{sessions: sessions, readiness: readiness, profiles: profiles}
+}
+
+// CheckIn records how the user feels today, in their time zone, replacing an earlier check-in of the
+// same day. Each rating runs from 1 to 5.
+func (s *WorkloadService) CheckIn(ctx context.Context, userID uuid.UUID, sleep, soreness, stress int) (*models.ReadinessCheckIn, error) {
+	for _, r := range []struct {
+		name  string
+		value int
+	}{{"sleep", sleep}, {"soreness", soreness}, {"stress", stress}} {
+		if r.value < 1 || r.value > 5 {
+			return nil, fmt.Errorf("%w: %s must be between 1 and 5", ErrInvalidCheckIn, r.name)
+		}
+	}
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.readiness.Upsert(ctx, &models.ReadinessCheckIn{
+		UserID:   userID,
+		Day:      localDate(time.Now(), profile),
+		Sleep:    sleep,
+		Soreness: soreness,
+		Stress:   stress,
+	})
+}
+
+// Workload compares the user's acute and chronic training load and, with today's check-in, decides how
+// far suggested volume should be scaled down.
+func (s *WorkloadService) Workload(ctx context.Context, userID uuid.UUID) (*models.Workload, error) {
+	profile, err := s.profiles.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	sessions, err := s.sessions.ListTrainedSince(ctx, userID, now.AddDate(0, 0, -rules.ChronicDays))
+	if err != nil {
+		return nil, err
+	}
+
+	acuteSince := now.AddDate(0, 0, -rules.AcuteDays)
+	var acute, chronic float64
+	for _, session := range sessions {
+		load := rules.SessionLoad(session.Sets)
+		chronic += load
+		if !session.PerformedAt.Before(acuteSince) {
+			acute += load
+		}
+	}
+	workload := &models.Workload{
+		AcuteLoad:   acute,
+		ChronicLoad: round2(chronic * rules.AcuteDays / rules.ChronicDays),
+	}
+	if len(sessions) > 0 && sessions[0].PerformedAt.Before(now.AddDate(0, 0, -minChronicHistoryDays)) {
+		if ratio := rules.Ratio(workload.AcuteLoad, workload.ChronicLoad); ratio != nil {
+			rounded := round2(*ratio)
+			workload.Ratio = &rounded
+		}
+	}
+
+	checkIn, err := s.readiness.GetForDay(ctx, userID, localDate(now, profile))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, err
+	default:
+		score := rules.ReadinessScore(*checkIn)
+		workload.Readiness = checkIn
+		workload.ReadinessScore = &score
+	}
+	workload.VolumeScale, workload.Notes = rules.VolumeScale(workload.Ratio, workload.ReadinessScore)
+	return workload, nil
+}
+
+// localDate returns the date t falls on in the user's time zone, as midnight UTC.
+func localDate(t time.Time, profile *models.Profile) time.Time {
+	local := t.In(userLocation(profile))
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
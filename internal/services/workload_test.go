@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/alexanderramin/kalistheniks/internal/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockgen -source=workload.go -destination=./mocks/workload_mock.go -package=mocks
+func TestWorkloadService_CheckIn(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("stores today's check-in in the user's time zone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		readiness := mocks.NewMockReadinessRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		svc := NewWorkloadService(mocks.NewMockWorkloadSessionRepository(ctrl), readiness, profiles)
+
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "Pacific/Kiritimati"}, nil)
+		local := time.Now().In(time.FixedZone("LINT", 14*60*60))
+		readiness.EXPECT().Upsert(ctx, &models.ReadinessCheckIn{
+			UserID:   userID,
+			Day:      time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC),
+			Sleep:    4,
+			Soreness: 2,
+			Stress:   3,
+		}).Return(&models.ReadinessCheckIn{ID: uuid.New()}, nil)
+
+		_, err := svc.CheckIn(ctx, userID, 4, 2, 3)
+		require.NoError(t, err)
+	})
+
+	t.Run("ratings run from 1 to 5", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc := NewWorkloadService(mocks.NewMockWorkloadSessionRepository(ctrl), mocks.NewMockReadinessRepository(ctrl), mocks.NewMockProfileRepository(ctrl))
+
+		_, err := svc.CheckIn(ctx, userID, 4, 0, 3)
+		require.ErrorIs(t, err, ErrInvalidCheckIn)
+		require.Contains(t, err.Error(), "soreness")
+		_, err = svc.CheckIn(ctx, userID, 6, 2, 3)
+		require.ErrorIs(t, err, ErrInvalidCheckIn)
+	})
+}
+
+func TestWorkloadService_Workload(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now().UTC()
+	rpe := func(v int) *int { return &v }
+	// session has n working sets at the given RPE
+	session := func(daysAgo, n, rating int) *models.Session {
+		s := &models.Session{ID: uuid.New(), PerformedAt: now.AddDate(0, 0, -daysAgo)}
+		for i := 0; i < n; i++ {
+			s.Sets = append(s.Sets, models.Set{Kind: models.SetKindWorking, RPE: rpe(rating)})
+		}
+		return s
+	}
+
+	newService := func(t *testing.T, sessions []*models.Session, checkIn *models.ReadinessCheckIn) *WorkloadService {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockWorkloadSessionRepository(ctrl)
+		readiness := mocks.NewMockReadinessRepository(ctrl)
+		profiles := mocks.NewMockProfileRepository(ctrl)
+		profiles.EXPECT().GetProfile(ctx, userID).Return(&models.Profile{UserID: userID, TimeZone: "UTC"}, nil)
+		repo.EXPECT().ListTrainedSince(ctx, userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, since time.Time) ([]*models.Session, error) {
+				require.WithinDuration(t, now.AddDate(0, 0, -28), since, time.Minute)
+				return sessions, nil
+			})
+		if checkIn != nil {
+			readiness.EXPECT().GetForDay(ctx, userID, gomock.Any()).Return(checkIn, nil)
+		} else {
+			readiness.EXPECT().GetForDay(ctx, userID, gomock.Any()).Return(nil, sql.ErrNoRows)
+		}
+		return NewWorkloadService(repo, readiness, profiles)
+	}
+
+	t.Run("steady training", func(t *testing.T) {
+		// one session of 10 sets at RPE 8 each week
+		svc := newService(t, []*models.Session{session(24, 10, 8), session(17, 10, 8), session(10, 10, 8), session(3, 10, 8)}, nil)
+
+		w, err := svc.Workload(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 80.0, w.AcuteLoad)
+		require.Equal(t, 80.0, w.ChronicLoad)
+		require.Equal(t, 1.0, *w.Ratio)
+		require.Nil(t, w.Readiness)
+		require.Equal(t, 1.0, w.VolumeScale)
+		require.Empty(t, w.Notes)
+	})
+
+	t.Run("a spike scales volume down", func(t *testing.T) {
+		svc := newService(t, []*models.Session{session(24, 5, 7), session(17, 5, 7), session(5, 10, 9), session(2, 10, 9), session(1, 10, 9)}, nil)
+
+		w, err := svc.Workload(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 270.0, w.AcuteLoad)
+		require.Equal(t, 85.0, w.ChronicLoad)
+		require.Equal(t, 3.18, *w.Ratio)
+		require.Equal(t, 0.7, w.VolumeScale)
+		require.Contains(t, w.Notes, "spiked")
+	})
+
+	t.Run("too little history for a ratio", func(t *testing.T) {
+		svc := newService(t, []*models.Session{session(10, 10, 8), session(3, 10, 8)}, nil)
+
+		w, err := svc.Workload(ctx, userID)
+		require.NoError(t, err)
+		require.Nil(t, w.Ratio, "two weeks of training would look like a spike")
+		require.Equal(t, 1.0, w.VolumeScale)
+	})
+
+	t.Run("poor readiness scales volume down", func(t *testing.T) {
+		checkIn := &models.ReadinessCheckIn{Sleep: 2, Soreness: 4, Stress: 4}
+		svc := newService(t, nil, checkIn)
+
+		w, err := svc.Workload(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, checkIn, w.Readiness)
+		require.Equal(t, 0.25, *w.ReadinessScore)
+		require.Equal(t, 0.7, w.VolumeScale)
+		require.Contains(t, w.Notes, "Readiness")
+	})
+}
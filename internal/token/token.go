@@ -1,6 +1,10 @@
 package token
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -8,33 +12,106 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	issuer = "kalistheniks-api"
+	// accessAudience marks tokens that grant API access.
+	accessAudience = "kalistheniks-users"
+	// mfaAudience marks short-lived tokens that only prove the password step of a two-step login.
+	mfaAudience = "kalistheniks-mfa"
+
+	accessTokenTTL  = 24 * time.Hour
+	mfaChallengeTTL = 5 * time.Minute
+)
+
+// claims extends the registered claims with the user's token version. Bumping the stored version
+// revokes every access token issued before.
+type claims struct {
+	jwt.RegisteredClaims
+	Version int `json:"ver,omitempty"`
+}
+
 // GenerateToken creates a signed JWT for the provided user.
 func GenerateToken(userID uuid.UUID, secret string) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   userID.String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Issuer:    "kalistheniks-api",
-		Audience:  jwt.ClaimStrings{"kalistheniks-users"},
-		ID:        uuid.New().String(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	return GenerateVersionedToken(userID, 0, secret)
+}
+
+// GenerateVersionedToken creates a signed JWT bound to the user's current token version.
+func GenerateVersionedToken(userID uuid.UUID, version int, secret string) (string, error) {
+	return generate(userID, version, secret, accessAudience, accessTokenTTL)
 }
 
 // ParseToken validates the JWT and extracts the user identifier.
 func ParseToken(tokenString, secret string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+	subject, _, err := ParseVersionedToken(tokenString, secret)
+	return subject, err
+}
+
+// ParseVersionedToken validates the JWT and extracts the user identifier and token version.
+func ParseVersionedToken(tokenString, secret string) (string, int, error) {
+	c, err := parse(tokenString, secret, accessAudience)
+	if err != nil {
+		return "", 0, err
+	}
+	return c.Subject, c.Version, nil
+}
+
+// GenerateMFAChallenge creates a short-lived token to exchange, together with a second factor, for an access token.
+func GenerateMFAChallenge(userID uuid.UUID, secret string) (string, error) {
+	return generate(userID, 0, secret, mfaAudience, mfaChallengeTTL)
+}
+
+// ParseMFAChallenge validates a challenge token and extracts the user identifier.
+func ParseMFAChallenge(tokenString, secret string) (string, error) {
+	c, err := parse(tokenString, secret, mfaAudience)
+	if err != nil {
+		return "", err
+	}
+	return c.Subject, nil
+}
+
+func generate(userID uuid.UUID, version int, secret, audience string, ttl time.Duration) (string, error) {
+	c := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			ID:        uuid.New().String(),
+		},
+		Version: version,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+	return token.SignedString([]byte(secret))
+}
+
+func parse(tokenString, secret, audience string) (*claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &claims{}, func(token *jwt.Token) (interface{}, error) {
 		if token.Method != jwt.SigningMethodHS256 {
 			return nil, errors.New("unexpected signing method")
 		}
 		return []byte(secret), nil
-	})
+	}, jwt.WithAudience(audience), jwt.WithIssuer(issuer))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
-		return claims.Subject, nil
+	if c, ok := token.Claims.(*claims); ok && token.Valid {
+		return c, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// GenerateOpaqueToken returns a random URL-safe token for single-use links such as password resets.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	return "", errors.New("invalid token")
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashOpaqueToken returns the SHA-256 hex digest stored in place of an opaque token.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
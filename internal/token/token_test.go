@@ -82,3 +82,78 @@ func TestGenerateAndParseToken(t *testing.T) {
 		require.NotEmpty(t, claims.ID)
 	})
 }
+
+func TestVersionedToken(t *testing.T) {
+	userID := uuid.New()
+	secret := "supersecret"
+
+	t.Run("round trip carries version", func(t *testing.T) {
+		token, err := GenerateVersionedToken(userID, 3, secret)
+		require.NoError(t, err)
+
+		subject, version, err := ParseVersionedToken(token, secret)
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), subject)
+		require.Equal(t, 3, version)
+	})
+
+	t.Run("unversioned token has version zero", func(t *testing.T) {
+		token, err := GenerateToken(userID, secret)
+		require.NoError(t, err)
+
+		_, version, err := ParseVersionedToken(token, secret)
+		require.NoError(t, err)
+		require.Zero(t, version)
+	})
+}
+
+func TestMFAChallenge(t *testing.T) {
+	userID := uuid.New()
+	secret := "supersecret"
+
+	t.Run("round trip success", func(t *testing.T) {
+		challenge, err := GenerateMFAChallenge(userID, secret)
+		require.NoError(t, err)
+
+		parsedUser, err := ParseMFAChallenge(challenge, secret)
+		require.NoError(t, err)
+		require.Equal(t, userID.String(), parsedUser)
+	})
+
+	t.Run("challenge is not an access token", func(t *testing.T) {
+		challenge, err := GenerateMFAChallenge(userID, secret)
+		require.NoError(t, err)
+
+		parsedUser, err := ParseToken(challenge, secret)
+		require.Error(t, err)
+		require.Empty(t, parsedUser)
+	})
+
+	t.Run("access token is not a challenge", func(t *testing.T) {
+		access, err := GenerateToken(userID, secret)
+		require.NoError(t, err)
+
+		parsedUser, err := ParseMFAChallenge(access, secret)
+		require.Error(t, err)
+		require.Empty(t, parsedUser)
+	})
+}
+
+func TestOpaqueToken(t *testing.T) {
+	t.Run("generates unique tokens", func(t *testing.T) {
+		a, err := GenerateOpaqueToken()
+		require.NoError(t, err)
+		b, err := GenerateOpaqueToken()
+		require.NoError(t, err)
+		require.NotEqual(t, a, b)
+		require.Len(t, a, 43)
+	})
+
+	t.Run("hash is deterministic and hides the token", func(t *testing.T) {
+		tok, err := GenerateOpaqueToken()
+		require.NoError(t, err)
+		require.Equal(t, HashOpaqueToken(tok), HashOpaqueToken(tok))
+		require.NotContains(t, HashOpaqueToken(tok), tok)
+		require.Len(t, HashOpaqueToken(tok), 64)
+	})
+}
@@ -0,0 +1,76 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 key from RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 vectors, truncated to six digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tt.code, code, "unix time %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	t.Run("accepts current code", func(t *testing.T) {
+		step, ok := Validate(rfcSecret, "050471", now)
+		require.True(t, ok)
+		require.Equal(t, Step(now), step)
+	})
+
+	t.Run("accepts previous step within skew", func(t *testing.T) {
+		code, err := Code(rfcSecret, Step(now)-1)
+		require.NoError(t, err)
+		_, ok := Validate(rfcSecret, code, now)
+		require.True(t, ok)
+	})
+
+	t.Run("rejects code outside skew", func(t *testing.T) {
+		code, err := Code(rfcSecret, Step(now)-3)
+		require.NoError(t, err)
+		_, ok := Validate(rfcSecret, code, now)
+		require.False(t, ok)
+	})
+
+	t.Run("rejects malformed code", func(t *testing.T) {
+		_, ok := Validate(rfcSecret, "12", now)
+		require.False(t, ok)
+	})
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	uri := ProvisioningURI("Kalistheniks", "user@example.com", secret)
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", parsed.Scheme)
+	require.Equal(t, "totp", parsed.Host)
+	require.Equal(t, "/Kalistheniks:user@example.com", parsed.Path)
+	require.Equal(t, secret, parsed.Query().Get("secret"))
+	require.Equal(t, "Kalistheniks", parsed.Query().Get("issuer"))
+}
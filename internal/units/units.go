@@ -0,0 +1,63 @@
+// Package units converts loads between the metric and imperial systems. Weights are stored in
+// kilograms; pounds only exist at the API edge.
+package units
+
+import (
+	"math"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+)
+
+// KilogramsPerPound is the exact international avoirdupois pound.
+const KilogramsPerPound = 0.45359237
+
+// Symbol returns the weight unit shown to users of the given system.
+func Symbol(system string) string {
+	if system == models.UnitsImperial {
+		return "lb"
+	}
+	return "kg"
+}
+
+// ToKG converts a weight in the system's unit to kilograms at the precision they are stored with.
+func ToKG(value float64, system string) float64 {
+	if system == models.UnitsImperial {
+		value *= KilogramsPerPound
+	}
+	return round(value, 2)
+}
+
+// FromKG converts stored kilograms to the system's unit. Pounds are shown to a tenth, which is finer
+// than any plate but hides the noise of the kilogram round trip.
+func FromKG(kg float64, system string) float64 {
+	if system == models.UnitsImperial {
+		return round(kg/KilogramsPerPound, 1)
+	}
+	return round(kg, 2)
+}
+
+// Increment is the smallest realistic load jump in the system's unit: a pair of the smallest common
+// plates. small selects fractional plates (1.25 kg or 2.5 lb) for lifters close to their ceiling.
+func Increment(system string, small bool) float64 {
+	step := 2.5
+	if system == models.UnitsImperial {
+		step = 5
+	}
+	if small {
+		step /= 2
+	}
+	return step
+}
+
+// Snap rounds value to the nearest multiple of step.
+func Snap(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return round(math.Round(value/step)*step, 2)
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
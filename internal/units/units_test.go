@@ -0,0 +1,42 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/alexanderramin/kalistheniks/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversion(t *testing.T) {
+	t.Run("metric is unchanged", func(t *testing.T) {
+		require.Equal(t, 82.5, ToKG(82.5, models.UnitsMetric))
+		require.Equal(t, 82.5, FromKG(82.5, models.UnitsMetric))
+		require.Equal(t, "kg", Symbol(models.UnitsMetric))
+	})
+
+	t.Run("imperial converts pounds", func(t *testing.T) {
+		require.Equal(t, 61.23, ToKG(135, models.UnitsImperial))
+		require.Equal(t, 135.0, FromKG(61.23, models.UnitsImperial))
+		require.Equal(t, "lb", Symbol(models.UnitsImperial))
+	})
+
+	t.Run("pounds survive the stored round trip", func(t *testing.T) {
+		for _, lb := range []float64{2.5, 45, 137.3, 225, 405.5} {
+			require.Equal(t, lb, FromKG(ToKG(lb, models.UnitsImperial), models.UnitsImperial))
+		}
+	})
+}
+
+func TestIncrement(t *testing.T) {
+	require.Equal(t, 2.5, Increment(models.UnitsMetric, false))
+	require.Equal(t, 1.25, Increment(models.UnitsMetric, true))
+	require.Equal(t, 5.0, Increment(models.UnitsImperial, false))
+	require.Equal(t, 2.5, Increment(models.UnitsImperial, true))
+}
+
+func TestSnap(t *testing.T) {
+	require.Equal(t, 82.5, Snap(81.8, 2.5))
+	require.Equal(t, 140.0, Snap(139.99, 5))
+	require.Equal(t, 101.25, Snap(101.2, 1.25))
+	require.Equal(t, 7.0, Snap(7, 0))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -118,3 +119,24 @@ func ValidateStringLength(value string, minLen, maxLen int, fieldName string) er
 	}
 	return nil
 }
+
+// ValidateOneOf checks if a string is one of the allowed values
+func ValidateOneOf(value string, allowed []string, fieldName string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s must be one of %s", ErrInvalidValue, fieldName, strings.Join(allowed, ", "))
+}
+
+// ValidateTimeZone checks if a string is an IANA time zone name such as "Europe/Berlin"
+func ValidateTimeZone(name string, fieldName string) error {
+	if name == "" || name == "Local" {
+		return fmt.Errorf("%w: %s must be an IANA time zone", ErrInvalidValue, fieldName)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("%w: %s must be an IANA time zone", ErrInvalidValue, fieldName)
+	}
+	return nil
+}
@@ -245,3 +245,56 @@ func TestValidateStringLength(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOneOf(t *testing.T) {
+	allowed := []string{"metric", "imperial"}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"first value", "metric", false},
+		{"second value", "imperial", false},
+		{"unknown value", "furlongs", true},
+		{"case sensitive", "Metric", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOneOf(tt.value, allowed, "units")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidValue)
+				assert.Contains(t, err.Error(), "units")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		zone    string
+		wantErr bool
+	}{
+		{"utc", "UTC", false},
+		{"region", "Europe/Berlin", false},
+		{"americas", "America/New_York", false},
+		{"unknown", "Mars/Olympus_Mons", true},
+		{"empty", "", true},
+		{"server local", "Local", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTimeZone(tt.zone, "time_zone")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidValue)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
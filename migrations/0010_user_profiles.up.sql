@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    bodyweight_kg NUMERIC(5,2) CHECK (bodyweight_kg > 0),
+    height_cm NUMERIC(5,1) CHECK (height_cm > 0),
+    birth_year INTEGER CHECK (birth_year >= 1900),
+    experience TEXT CHECK (experience IN ('beginner', 'intermediate', 'advanced')),
+    units TEXT NOT NULL DEFAULT 'metric' CHECK (units IN ('metric', 'imperial')),
+    time_zone TEXT NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
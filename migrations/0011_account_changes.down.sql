@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS email_change_requests;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS token_version;
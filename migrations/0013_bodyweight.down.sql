@@ -0,0 +1,4 @@
+ALTER TABLE exercises
+DROP COLUMN IF EXISTS bodyweight_fraction;
+
+DROP TABLE IF EXISTS bodyweight_entries;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS bodyweight_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    measured_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    weight_kg NUMERIC(5,2) NOT NULL CHECK (weight_kg > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bodyweight_entries_user_measured ON bodyweight_entries(user_id, measured_at);
+
+-- Share of the athlete's bodyweight moved in one rep; 0 for external-load exercises.
+ALTER TABLE exercises
+ADD COLUMN IF NOT EXISTS bodyweight_fraction NUMERIC(3,2) NOT NULL DEFAULT 0
+    CHECK (bodyweight_fraction >= 0 AND bodyweight_fraction <= 1);
+
+UPDATE exercises SET bodyweight_fraction = 0.64 WHERE name = 'Push Up';
+UPDATE exercises SET bodyweight_fraction = 0.55 WHERE name = 'Inverted Row';
+UPDATE exercises SET bodyweight_fraction = 0.85 WHERE name = 'Air Squat';
+UPDATE exercises SET bodyweight_fraction = 0.95 WHERE name = 'Dip';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_equipment;
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS equipment;
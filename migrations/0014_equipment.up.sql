@@ -0,0 +1,19 @@
+-- How an exercise is loaded, which decides the weights a gym can actually make for it.
+ALTER TABLE exercises
+ADD COLUMN IF NOT EXISTS equipment TEXT NOT NULL DEFAULT 'barbell'
+    CHECK (equipment IN ('barbell', 'dumbbell', 'bodyweight'));
+
+UPDATE exercises SET equipment = 'bodyweight'
+WHERE name IN ('Push Up', 'Inverted Row', 'Air Squat', 'Dip', 'Plank');
+
+-- One row per user; users without a row get the standard equipment for their unit system.
+CREATE TABLE IF NOT EXISTS user_equipment (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    bar_kg NUMERIC(5,2) NOT NULL CHECK (bar_kg >= 0),
+    plates_kg NUMERIC(5,2)[] NOT NULL DEFAULT '{}',
+    dumbbells_kg NUMERIC(5,2)[] NOT NULL DEFAULT '{}',
+    has_rings BOOLEAN NOT NULL DEFAULT FALSE,
+    has_pullup_bar BOOLEAN NOT NULL DEFAULT FALSE,
+    has_parallettes BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,9 @@
+ALTER TABLE sets
+DROP COLUMN IF EXISTS distance_m,
+DROP COLUMN IF EXISTS duration_seconds;
+
+DELETE FROM exercises
+WHERE name IN ('L-Sit', 'Handstand Hold', 'Jump Rope', 'Farmer''s Carry')
+  AND NOT EXISTS (SELECT 1 FROM sets WHERE sets.exercise_id = exercises.id);
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS measurement;
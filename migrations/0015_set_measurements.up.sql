@@ -0,0 +1,20 @@
+-- What a set of an exercise records: reps, how long a position is held, how long the work lasts, or
+-- how far it goes.
+ALTER TABLE exercises
+ADD COLUMN IF NOT EXISTS measurement TEXT NOT NULL DEFAULT 'reps'
+    CHECK (measurement IN ('reps', 'hold', 'duration', 'distance'));
+
+UPDATE exercises SET measurement = 'hold' WHERE name = 'Plank';
+
+INSERT INTO exercises (name, body_part, primary_muscle, secondary_muscle, equipment, measurement)
+VALUES
+  ('L-Sit',           'core',       'abdominals',       'hip_flexors',     'bodyweight', 'hold'),
+  ('Handstand Hold',  'shoulder',   'deltoids',         'triceps_brachii', 'bodyweight', 'hold'),
+  ('Jump Rope',       'lower_leg',  'gastrocnemius',    NULL,              'bodyweight', 'duration'),
+  ('Farmer''s Carry', 'lower_arm',  'forearm_flexors',  'trapezius',       'dumbbell',   'distance')
+ON CONFLICT (name) DO NOTHING;
+
+-- Reps stay 0 for sets measured by time or distance.
+ALTER TABLE sets
+ADD COLUMN IF NOT EXISTS duration_seconds INTEGER CHECK (duration_seconds > 0),
+ADD COLUMN IF NOT EXISTS distance_m NUMERIC(10,2) CHECK (distance_m > 0);
@@ -0,0 +1 @@
+ALTER TABLE sets DROP COLUMN IF EXISTS kind;
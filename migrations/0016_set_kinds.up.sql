@@ -0,0 +1,4 @@
+-- What role a set plays in the session. Only working sets feed progression, records and volume by default.
+ALTER TABLE sets
+ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'working'
+    CHECK (kind IN ('warmup', 'working', 'dropset', 'amrap', 'backoff', 'failure'));
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sets_group_id;
+
+ALTER TABLE sets
+DROP COLUMN IF EXISTS round,
+DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS set_groups;
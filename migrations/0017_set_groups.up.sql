@@ -0,0 +1,17 @@
+-- Sets performed together within a session: supersets, giant sets, circuits, EMOMs and AMRAP blocks.
+CREATE TABLE IF NOT EXISTS set_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    group_index INTEGER NOT NULL CHECK (group_index >= 0),
+    kind TEXT NOT NULL CHECK (kind IN ('superset', 'giant_set', 'circuit', 'emom', 'amrap')),
+    rounds INTEGER CHECK (rounds > 0),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, group_index)
+);
+
+ALTER TABLE sets
+ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES set_groups(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS round INTEGER CHECK (round > 0);
+
+CREATE INDEX IF NOT EXISTS idx_sets_group_id ON sets(group_id);
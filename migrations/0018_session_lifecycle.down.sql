@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sessions_in_progress_started_at;
+
+ALTER TABLE sessions
+DROP CONSTRAINT IF EXISTS sessions_finished_after_started,
+DROP COLUMN IF EXISTS finished_at,
+DROP COLUMN IF EXISTS started_at,
+DROP COLUMN IF EXISTS status;
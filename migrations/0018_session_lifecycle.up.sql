@@ -0,0 +1,12 @@
+-- Sessions move from planned to in_progress when started and end completed or abandoned. Sessions
+-- logged before this migration were recorded after the fact, so they count as completed.
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'completed'
+    CHECK (status IN ('planned', 'in_progress', 'completed', 'abandoned')),
+ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS finished_at TIMESTAMPTZ,
+ADD CONSTRAINT sessions_finished_after_started CHECK (finished_at >= started_at);
+
+-- The stale session job looks for long-running sessions.
+CREATE INDEX IF NOT EXISTS idx_sessions_in_progress_started_at ON sessions(started_at)
+    WHERE status = 'in_progress';
@@ -0,0 +1,2 @@
+ALTER TABLE sets DROP COLUMN IF EXISTS rest_seconds;
+ALTER TABLE sets DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,7 @@
+-- When the client says a set was finished, and the rest its timer counted down before the set.
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+UPDATE sets SET completed_at = created_at WHERE completed_at IS NULL;
+ALTER TABLE sets ALTER COLUMN completed_at SET DEFAULT now();
+ALTER TABLE sets ALTER COLUMN completed_at SET NOT NULL;
+
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS rest_seconds INT CHECK (rest_seconds >= 0);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS planned_sets;
+DROP TABLE IF EXISTS template_sets;
+DROP TABLE IF EXISTS template_groups;
+DROP TABLE IF EXISTS session_templates;
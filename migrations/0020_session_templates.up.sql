@@ -0,0 +1,52 @@
+-- Reusable session structures saved from past sessions.
+CREATE TABLE IF NOT EXISTS session_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    session_type TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_templates_user_id ON session_templates(user_id);
+
+CREATE TABLE IF NOT EXISTS template_groups (
+    template_id UUID NOT NULL REFERENCES session_templates(id) ON DELETE CASCADE,
+    group_index INTEGER NOT NULL CHECK (group_index >= 0),
+    kind TEXT NOT NULL CHECK (kind IN ('superset', 'giant_set', 'circuit', 'emom', 'amrap')),
+    rounds INTEGER CHECK (rounds > 0),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    PRIMARY KEY (template_id, group_index)
+);
+
+CREATE TABLE IF NOT EXISTS template_sets (
+    template_id UUID NOT NULL REFERENCES session_templates(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    exercise_id UUID NOT NULL REFERENCES exercises(id) ON DELETE RESTRICT,
+    kind TEXT NOT NULL CHECK (kind IN ('warmup', 'working', 'dropset', 'amrap', 'backoff', 'failure')),
+    reps INTEGER NOT NULL CHECK (reps >= 0),
+    weight_kg NUMERIC(10,2) NOT NULL DEFAULT 0,
+    duration_seconds INTEGER,
+    distance_m NUMERIC(10,2),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    group_index INTEGER,
+    round INTEGER CHECK (round > 0),
+    PRIMARY KEY (template_id, position),
+    FOREIGN KEY (template_id, group_index) REFERENCES template_groups(template_id, group_index)
+);
+
+-- Targets for the sets of a session that has not been trained yet, e.g. one created from a template.
+CREATE TABLE IF NOT EXISTS planned_sets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    exercise_id UUID NOT NULL REFERENCES exercises(id) ON DELETE RESTRICT,
+    kind TEXT NOT NULL CHECK (kind IN ('warmup', 'working', 'dropset', 'amrap', 'backoff', 'failure')),
+    reps INTEGER NOT NULL CHECK (reps >= 0),
+    weight_kg NUMERIC(10,2) NOT NULL DEFAULT 0,
+    duration_seconds INTEGER,
+    distance_m NUMERIC(10,2),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    group_id UUID REFERENCES set_groups(id) ON DELETE SET NULL,
+    round INTEGER CHECK (round > 0),
+    UNIQUE (session_id, position)
+);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_sessions_planned_performed_at;
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS missed_count,
+DROP COLUMN IF EXISTS rescheduled_from;
+
+ALTER TABLE user_profiles
+DROP COLUMN IF EXISTS training_days;
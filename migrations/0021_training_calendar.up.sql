@@ -0,0 +1,14 @@
+-- Training days are the weekdays a user plans to train, by lowercase English name.
+ALTER TABLE user_profiles
+ADD COLUMN IF NOT EXISTS training_days TEXT[] NOT NULL DEFAULT '{}'
+    CHECK (training_days <@ ARRAY['monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday', 'sunday']);
+
+-- A planned session keeps the time it was first scheduled for when it is moved, and counts how often it
+-- was moved because its day passed without it being started.
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS rescheduled_from TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS missed_count INTEGER NOT NULL DEFAULT 0 CHECK (missed_count >= 0);
+
+-- The missed session job looks for planned sessions whose time has passed.
+CREATE INDEX IF NOT EXISTS idx_sessions_planned_performed_at ON sessions(performed_at)
+    WHERE status = 'planned';
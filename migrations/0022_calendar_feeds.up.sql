@@ -0,0 +1,7 @@
+-- A calendar feed lets calendar apps, which cannot send an Authorization header, subscribe to a user's
+-- planned sessions. Each user has at most one feed; issuing a new token replaces the old one.
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+-- A readiness check-in rates how the user feels on a day, each on a scale of 1 to 5. One check-in per day
+-- in the user's time zone; checking in again the same day replaces it.
+CREATE TABLE IF NOT EXISTS readiness_checkins (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    sleep SMALLINT NOT NULL CHECK (sleep BETWEEN 1 AND 5),
+    soreness SMALLINT NOT NULL CHECK (soreness BETWEEN 1 AND 5),
+    stress SMALLINT NOT NULL CHECK (stress BETWEEN 1 AND 5),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, day)
+);